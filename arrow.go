@@ -0,0 +1,157 @@
+//go:build arrow
+
+package tritonparser
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// DecodeArrow decodes fixed-width outputs of inferResponse into Apache Arrow arrays by output name.
+// Elements of outputs of any rank are laid out in row-major order. Arrays of numeric outputs
+// share memory with their little-endian raw contents, BOOL and packed 4-bit outputs are copied.
+// Outputs of other datatypes, e.g. BYTES, are rejected. Caller must Release returned arrays.
+//
+// DecodeArrow is available with "arrow" build tag.
+func DecodeArrow[T TritonModelInferResponseOutputs](
+	inferResponse TritonModelInferResponse[T],
+	opts Options,
+) (map[string]arrow.Array, error) {
+	if err := responseError(inferResponse); err != nil {
+		return nil, err
+	}
+
+	outputs := inferResponse.GetOutputs()
+	res := make(map[string]arrow.Array, len(outputs))
+
+	rawBytes, err := joinChunks(outputs, inferResponse.GetRawOutputContents(), &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	release := func() {
+		for _, arr := range res {
+			arr.Release()
+		}
+	}
+
+	for i, o := range outputs {
+		if !opts.selected(o.GetName()) {
+			continue
+		}
+
+		raw, err := rawContents(o, i, rawBytes, &opts)
+		if err != nil {
+			release()
+
+			return nil, err
+		}
+
+		b, err := decompress(raw, &opts)
+		if err != nil {
+			release()
+
+			return nil, fmt.Errorf("output %s: %w", o.GetName(), err)
+		}
+
+		arr, err := arrowArray(o, b, &opts)
+		if err != nil {
+			release()
+
+			return nil, fmt.Errorf("output %s: %w", o.GetName(), err)
+		}
+
+		if prev, ok := res[o.GetName()]; ok {
+			// the last of outputs with the same name wins, as in Unmarshal.
+			prev.Release()
+		}
+
+		res[o.GetName()] = arr
+	}
+
+	return res, nil
+}
+
+// arrowArray returns Arrow array of elements of output in rawBytes.
+func arrowArray(output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) (arrow.Array, error) {
+	size, err := OutputByteSize(output.GetDatatype(), output.GetShape())
+	if err != nil {
+		return nil, err
+	}
+
+	if size != len(rawBytes) {
+		return nil, fmt.Errorf("raw contents length %d doesn't match shape %v", len(rawBytes), output.GetShape())
+	}
+
+	if opts.byteOrder(output.GetName()) != binary.LittleEndian {
+		return nil, errors.New("arrow arrays require little-endian raw contents")
+	}
+
+	count := 1
+	for _, dim := range output.GetShape() {
+		count *= int(dim)
+	}
+
+	var dt arrow.DataType
+
+	switch output.GetDatatype() {
+	case BOOL:
+		bld := array.NewBooleanBuilder(memory.DefaultAllocator)
+		defer bld.Release()
+
+		for _, v := range rawBytes {
+			bld.Append(v != 0)
+		}
+
+		return bld.NewArray(), nil
+	case INT4, UINT4:
+		unpacked := make([]byte, count)
+		for j := range unpacked {
+			n := rawBytes[j/2] >> (4 * (j % 2)) & 0x0f
+			if output.GetDatatype() == INT4 {
+				n = byte(int8(n<<4) >> 4)
+			}
+
+			unpacked[j] = n
+		}
+
+		dt, rawBytes = arrow.PrimitiveTypes.Uint8, unpacked
+		if output.GetDatatype() == INT4 {
+			dt = arrow.PrimitiveTypes.Int8
+		}
+	case INT8:
+		dt = arrow.PrimitiveTypes.Int8
+	case INT16:
+		dt = arrow.PrimitiveTypes.Int16
+	case INT32:
+		dt = arrow.PrimitiveTypes.Int32
+	case INT64:
+		dt = arrow.PrimitiveTypes.Int64
+	case UINT8:
+		dt = arrow.PrimitiveTypes.Uint8
+	case UINT16:
+		dt = arrow.PrimitiveTypes.Uint16
+	case UINT32:
+		dt = arrow.PrimitiveTypes.Uint32
+	case UINT64:
+		dt = arrow.PrimitiveTypes.Uint64
+	case FLOAT16:
+		dt = arrow.FixedWidthTypes.Float16
+	case FLOAT32:
+		dt = arrow.PrimitiveTypes.Float32
+	case FLOAT64:
+		dt = arrow.PrimitiveTypes.Float64
+	default:
+		return nil, fmt.Errorf("%s is not a fixed-width datatype", output.GetDatatype())
+	}
+
+	data := array.NewData(dt, count, []*memory.Buffer{nil, memory.NewBufferBytes(rawBytes)}, nil, 0, 0)
+	defer data.Release()
+
+	return array.MakeFromData(data), nil
+}
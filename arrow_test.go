@@ -0,0 +1,104 @@
+//go:build arrow
+
+package tritonparser
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+func TestDecodeArrow(t *testing.T) {
+	scores := make([]byte, 0, 16)
+	for _, v := range []float32{0.5, 1.5, -2, 4} {
+		scores = binary.LittleEndian.AppendUint32(scores, math.Float32bits(v))
+	}
+
+	resp := &Response{
+		Outputs: []TritonModelInferResponseOutputs{
+			&httpOutput{Name: "scores", Datatype: FLOAT32, Shape: []int64{2, 2}},
+			&httpOutput{Name: "mask", Datatype: BOOL, Shape: []int64{3}},
+			&httpOutput{Name: "codes", Datatype: INT4, Shape: []int64{3}},
+		},
+		RawOutputContents: [][]byte{scores, {1, 0, 1}, {0xf7, 0x01}},
+	}
+
+	arrays, err := DecodeArrow(resp, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		for _, arr := range arrays {
+			arr.Release()
+		}
+	}()
+
+	f, ok := arrays["scores"].(*array.Float32)
+	if !ok {
+		t.Fatalf("scores: unexpected array %T", arrays["scores"])
+	}
+
+	if got := f.Float32Values(); len(got) != 4 || got[0] != 0.5 || got[1] != 1.5 || got[2] != -2 || got[3] != 4 {
+		t.Errorf("scores: got %v", got)
+	}
+
+	b, ok := arrays["mask"].(*array.Boolean)
+	if !ok {
+		t.Fatalf("mask: unexpected array %T", arrays["mask"])
+	}
+
+	if b.Len() != 3 || !b.Value(0) || b.Value(1) || !b.Value(2) {
+		t.Errorf("mask: got %v", b)
+	}
+
+	c, ok := arrays["codes"].(*array.Int8)
+	if !ok {
+		t.Fatalf("codes: unexpected array %T", arrays["codes"])
+	}
+
+	if got := c.Int8Values(); len(got) != 3 || got[0] != 7 || got[1] != -1 || got[2] != 1 {
+		t.Errorf("codes: got %v", got)
+	}
+}
+
+func TestDecodeArrowErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *httpOutput
+		raw    []byte
+		opts   Options
+	}{
+		{
+			name:   "variable size",
+			output: &httpOutput{Name: "labels", Datatype: STRING, Shape: []int64{1}},
+			raw:    []byte{1, 0, 0, 0, 'a'},
+		},
+		{
+			name:   "length mismatch",
+			output: &httpOutput{Name: "ids", Datatype: INT32, Shape: []int64{2}},
+			raw:    []byte{1, 0, 0, 0},
+		},
+		{
+			name:   "big-endian",
+			output: &httpOutput{Name: "ids", Datatype: INT32, Shape: []int64{1}},
+			raw:    []byte{0, 0, 0, 1},
+			opts:   Options{ByteOrder: binary.BigEndian},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &Response{
+				Outputs:           []TritonModelInferResponseOutputs{tt.output},
+				RawOutputContents: [][]byte{tt.raw},
+			}
+
+			if _, err := DecodeArrow(resp, tt.opts); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// isAtomic reports whether field is of sync/atomic type values are stored into with Store method.
+func isAtomic(field reflect.Value) bool {
+	if !field.CanAddr() {
+		return false
+	}
+
+	switch field.Addr().Interface().(type) {
+	case *atomic.Bool, *atomic.Int32, *atomic.Int64, *atomic.Uint32, *atomic.Uint64, *atomic.Value:
+		return true
+	default:
+		return false
+	}
+}
+
+// unmarshalAtomic decodes output into value of type stored by atomic field and stores it with Store,
+// so other goroutines may load it concurrently. atomic.Value receives value of native type of output,
+// which must not change between stores.
+func unmarshalAtomic(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	if a, ok := field.Addr().Interface().(*atomic.Value); ok {
+		val, err := decodeNative(output, rawBytes, opts)
+		if err != nil {
+			return err
+		}
+
+		// Store panics on value of other type than stored before.
+		if prev := a.Load(); prev != nil && reflect.TypeOf(prev) != val.Type() {
+			return fmt.Errorf("output %s: types doesn't match exp: %T got: %s", output.GetName(), prev, val.Type())
+		}
+
+		a.Store(val.Interface())
+
+		return nil
+	}
+
+	var t reflect.Type
+
+	switch field.Addr().Interface().(type) {
+	case *atomic.Bool:
+		t = reflect.TypeFor[bool]()
+	case *atomic.Int32:
+		t = reflect.TypeFor[int32]()
+	case *atomic.Int64:
+		t = reflect.TypeFor[int64]()
+	case *atomic.Uint32:
+		t = reflect.TypeFor[uint32]()
+	default:
+		t = reflect.TypeFor[uint64]()
+	}
+
+	val := reflect.New(t).Elem()
+	if err := parse(map[string]reflect.Value{output.GetName(): val}, output, rawBytes, opts); err != nil {
+		return err
+	}
+
+	switch a := field.Addr().Interface().(type) {
+	case *atomic.Bool:
+		a.Store(val.Bool())
+	case *atomic.Int32:
+		a.Store(int32(val.Int()))
+	case *atomic.Int64:
+		a.Store(val.Int())
+	case *atomic.Uint32:
+		a.Store(uint32(val.Uint()))
+	case *atomic.Uint64:
+		a.Store(val.Uint())
+	}
+
+	return nil
+}
+
+// loadAtomic returns value stored in atomic field, which is invalid for atomic.Value that was never stored.
+func loadAtomic(field reflect.Value) reflect.Value {
+	switch a := field.Addr().Interface().(type) {
+	case *atomic.Bool:
+		return reflect.ValueOf(a.Load())
+	case *atomic.Int32:
+		return reflect.ValueOf(a.Load())
+	case *atomic.Int64:
+		return reflect.ValueOf(a.Load())
+	case *atomic.Uint32:
+		return reflect.ValueOf(a.Load())
+	case *atomic.Uint64:
+		return reflect.ValueOf(a.Load())
+	case *atomic.Value:
+		return reflect.ValueOf(a.Load())
+	default:
+		return reflect.Value{}
+	}
+}
+
+// zeroField sets field to zero value. Atomic fields are zeroed with Store, so concurrent loads don't race.
+// atomic.Value that was never stored is left as is.
+func zeroField(field reflect.Value) {
+	if !isAtomic(field) {
+		field.SetZero()
+
+		return
+	}
+
+	switch a := field.Addr().Interface().(type) {
+	case *atomic.Bool:
+		a.Store(false)
+	case *atomic.Int32:
+		a.Store(0)
+	case *atomic.Int64:
+		a.Store(0)
+	case *atomic.Uint32:
+		a.Store(0)
+	case *atomic.Uint64:
+		a.Store(0)
+	case *atomic.Value:
+		if prev := a.Load(); prev != nil {
+			a.Store(reflect.Zero(reflect.TypeOf(prev)).Interface())
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package tritonparser
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAtomicZeroedConcurrently(t *testing.T) {
+	var res struct {
+		Count atomic.Int32 `triton:"count"`
+		Score atomic.Int64 `triton:"score,missing=-1"`
+	}
+
+	present := &Response{
+		Outputs: []TritonModelInferResponseOutputs{
+			&httpOutput{Name: "count", Datatype: INT32, Shape: []int64{1}},
+			&httpOutput{Name: "score", Datatype: INT64, Shape: []int64{1}},
+		},
+		RawOutputContents: [][]byte{{5, 0, 0, 0}, {0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	}
+	absent := &Response{
+		Outputs:           present.Outputs,
+		RawOutputContents: [][]byte{nil, {0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_, _ = res.Count.Load(), res.Score.Load()
+			}
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		if err := Unmarshal(present, &res); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := Unmarshal(absent, &res); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+
+	if res.Count.Load() != 0 || res.Score.Load() != 0 {
+		t.Errorf("got count %d and score %d, want zeroes", res.Count.Load(), res.Score.Load())
+	}
+}
@@ -0,0 +1,137 @@
+package tritonparser
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// permuteAxes returns output with axes permuted by perm, so axis i of result is axis perm[i] of output,
+// and its raw contents with elements reordered accordingly.
+func permuteAxes(
+	output TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+	perm []int,
+) (TritonModelInferResponseOutputs, []byte, error) {
+	shape := output.GetShape()
+
+	sorted := slices.Sorted(slices.Values(perm))
+	for i, axis := range sorted {
+		if len(perm) != len(shape) || axis != i {
+			return nil, nil, fmt.Errorf("output %s: %s option requires permutation of %d axes, got %v",
+				output.GetName(), tagAxes, len(shape), perm)
+		}
+	}
+
+	if len(shape) == 0 {
+		return output, rawBytes, nil
+	}
+
+	permuted := make([]int64, len(shape))
+	for i, axis := range perm {
+		permuted[i] = shape[axis]
+	}
+
+	if output.GetDatatype() == STRING && len(rawBytes) == 0 {
+		// empty contents are empty strings of any order.
+		return reshaped{output, permuted}, rawBytes, nil
+	}
+
+	bounds, err := elementBounds(output, rawBytes, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("output %s: %w", output.GetName(), err)
+	}
+
+	strides := make([]int, len(shape))
+	stride := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= int(shape[i])
+	}
+
+	res := make([]byte, 0, len(rawBytes))
+	index := make([]int, len(shape))
+
+	for range len(bounds) {
+		offset := 0
+		for i, axis := range perm {
+			offset += index[i] * strides[axis]
+		}
+
+		res = append(res, rawBytes[bounds[offset][0]:bounds[offset][1]]...)
+
+		// advance index in row-major order of permuted shape.
+		for i := len(index) - 1; i >= 0; i-- {
+			index[i]++
+			if int64(index[i]) < permuted[i] {
+				break
+			}
+
+			index[i] = 0
+		}
+	}
+
+	return reshaped{output, permuted}, res, nil
+}
+
+// elementBounds returns start and end offsets of every element of output within rawBytes.
+// Packed 4-bit elements don't have their own bytes and aren't supported.
+func elementBounds(output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) ([][2]int, error) {
+	count := 1
+	for _, dim := range output.GetShape() {
+		if dim < 0 {
+			return nil, fmt.Errorf("invalid shape: %v", output.GetShape())
+		}
+
+		if dim != 0 && int64(count) > math.MaxInt/dim {
+			return nil, fmt.Errorf("shape %v is too large", output.GetShape())
+		}
+
+		count *= int(dim)
+	}
+
+	// every element takes at least a byte, so hostile shape can't force large allocation.
+	res := make([][2]int, 0, min(count, len(rawBytes)))
+
+	switch output.GetDatatype() {
+	case INT4, UINT4:
+		return nil, fmt.Errorf("%s elements are packed and can't be reordered", output.GetDatatype())
+	case STRING:
+		offset := 0
+		for range count {
+			_, end, err := stringBounds(rawBytes, offset, opts.byteOrder(output.GetName()), opts.StringLengthPrefix)
+			if err != nil {
+				return nil, err
+			}
+
+			// length prefix moves with its element.
+			res = append(res, [2]int{offset, end})
+			offset = end
+		}
+
+		if offset != len(rawBytes) {
+			return nil, trailingBytesError(len(rawBytes)-offset, count)
+		}
+	default:
+		size, err := OutputByteSize(output.GetDatatype(), output.GetShape())
+		if err != nil {
+			return nil, err
+		}
+
+		if size != len(rawBytes) {
+			return nil, fmt.Errorf("raw contents length %d doesn't match %d elements of shape %v", len(rawBytes), count, output.GetShape())
+		}
+
+		if count == 0 {
+			return res, nil
+		}
+
+		elem := size / count
+		for i := range count {
+			res = append(res, [2]int{i * elem, (i + 1) * elem})
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,157 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAxes(t *testing.T) {
+	type result struct {
+		Transposed [][]int32    `triton:"transposed,axes=1,0"`
+		Identity   [][]int32    `triton:"identity,axes=0,1"`
+		Labels     [][]string   `triton:"labels,axes=1,0"`
+		Empty      [][]string   `triton:"empty,axes=1,0"`
+		Cube       Tensor[int8] `triton:"cube,axes=2,0,1"`
+		Row        []float32    `triton:"row,axes=1,0"`
+		Reversed   [][]int32    `triton:"reversed,axes=1,0,reverse"`
+		Half       []float32    `triton:"half,axes=1,0"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "transposed", datatype: INT32, shape: []int64{2, 3}},
+			{name: "identity", datatype: INT32, shape: []int64{2, 2}},
+			{name: "labels", datatype: STRING, shape: []int64{2, 2}},
+			{name: "empty", datatype: STRING, shape: []int64{2, 2}},
+			{name: "cube", datatype: INT8, shape: []int64{2, 2, 2}},
+			{name: "row", datatype: FLOAT32, shape: []int64{3, 1}},
+			{name: "reversed", datatype: INT32, shape: []int64{2, 2}},
+			{name: "half", datatype: FLOAT16, shape: []int64{2, 1}},
+		},
+		raw: [][]byte{
+			int32Bytes(1, 2, 3, 4, 5, 6),
+			int32Bytes(1, 2, 3, 4),
+			encodeStrings("a", "bb", "", "c"),
+			{},
+			{0, 1, 2, 3, 4, 5, 6, 7},
+			float32Bytes(1, 2, 3),
+			int32Bytes(1, 2, 3, 4),
+			halfBytes(0x3c00, 0xc000),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Transposed: [][]int32{{1, 4}, {2, 5}, {3, 6}},
+		Identity:   [][]int32{{1, 2}, {3, 4}},
+		Labels:     [][]string{{"a", ""}, {"bb", "c"}},
+		Empty:      [][]string{{"", ""}, {"", ""}},
+		// element [i][j][k] of result is element [j][k][i] of output.
+		Cube:     Tensor[int8]{Data: []int8{0, 2, 4, 6, 1, 3, 5, 7}, Shape: []int64{2, 2, 2}},
+		Row:      []float32{1, 2, 3},
+		Reversed: [][]int32{{2, 4}, {1, 3}},
+		Half:     []float32{1, -2},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestAxesErrors(t *testing.T) {
+	var res struct {
+		Matrix  [][]int32 `triton:"matrix,axes=1,0"`
+		Short   [][]int32 `triton:"short,axes=0"`
+		Repeat  [][]int32 `triton:"repeat,axes=0,0"`
+		Range   [][]int32 `triton:"range,axes=0,2"`
+		Invalid [][]int32 `triton:"invalid,axes=x"`
+		Packed  [][]int8  `triton:"packed,axes=1,0"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "too few axes",
+			output: &testOutput{name: "short", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(1),
+			want:   "output short: axes option requires permutation of 2 axes, got [0]",
+		},
+		{
+			name:   "repeated axis",
+			output: &testOutput{name: "repeat", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(1),
+			want:   "output repeat: axes option requires permutation of 2 axes, got [0 0]",
+		},
+		{
+			name:   "axis out of range",
+			output: &testOutput{name: "range", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(1),
+			want:   "output range: axes option requires permutation of 2 axes, got [0 2]",
+		},
+		{
+			name:   "rank mismatch",
+			output: &testOutput{name: "matrix", datatype: INT32, shape: []int64{2}},
+			raw:    int32Bytes(1, 2),
+			want:   "output matrix: axes option requires permutation of 1 axes, got [1 0]",
+		},
+		{
+			name:   "invalid value",
+			output: &testOutput{name: "invalid", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(1),
+			want:   `output invalid: invalid axes value: "x"`,
+		},
+		{
+			name:   "packed",
+			output: &testOutput{name: "packed", datatype: INT4, shape: []int64{2, 1}},
+			raw:    []byte{0x21},
+			want:   "output packed: INT4 elements are packed and can't be reordered",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "matrix", datatype: INT32, shape: []int64{2, 2}},
+			raw:    int32Bytes(1, 2, 3),
+			want:   "output matrix: raw contents length 12 doesn't match 4 elements of shape [2 2]",
+		},
+		{
+			name:   "short string",
+			output: &testOutput{name: "matrix", datatype: STRING, shape: []int64{2, 1}},
+			raw:    encodeStrings("a"),
+			want:   "output matrix: binary read failed: string length at offset 5",
+		},
+		{
+			name:   "trailing string bytes",
+			output: &testOutput{name: "matrix", datatype: STRING, shape: []int64{1, 1}},
+			raw:    encodeStrings("a", "b"),
+			want:   "output matrix: 5 trailing bytes after 1 elements",
+		},
+		{
+			name:   "negative dimension",
+			output: &testOutput{name: "matrix", datatype: INT32, shape: []int64{2, -1}},
+			raw:    int32Bytes(1, 2),
+			want:   "invalid shape: [2 -1]",
+		},
+		{
+			name:   "hostile shape",
+			output: &testOutput{name: "matrix", datatype: STRING, shape: []int64{1 << 62, 3}},
+			raw:    encodeStrings("a"),
+			want:   "output matrix: shape [4611686018427387904 3] is too large",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
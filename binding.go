@@ -0,0 +1,61 @@
+package tritonparser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errBindingClosed = errors.New("binding is closed")
+
+// Binding is a destination struct bound for repeated decoding.
+// Fields of the struct are resolved once, so decoding into the same struct instance
+// skips reflection over its tags on every response.
+// Binding is not safe for concurrent use. It may be pooled with Reset and Close.
+type Binding[T TritonModelInferResponseOutputs] struct {
+	fields *fieldSet
+	opts   Options
+}
+
+// Bind binds v for decoding with opts. v must be pointer to structure.
+func Bind[T TritonModelInferResponseOutputs](v any, opts Options) (*Binding[T], error) {
+	b := &Binding[T]{opts: opts}
+	if err := b.Reset(v); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Decode decodes inferResponse into bound struct the same way as UnmarshalWithOptions.
+func (b *Binding[T]) Decode(inferResponse TritonModelInferResponse[T]) error {
+	if b.fields == nil {
+		return errBindingClosed
+	}
+
+	return decodeFields(inferResponse, b.fields, &b.opts)
+}
+
+// Reset rebinds b to v, which must be pointer to structure. Options are kept.
+func (b *Binding[T]) Reset(v any) error {
+	rv := reflect.ValueOf(v)
+	if err := checkDestination(rv); err != nil {
+		return err
+	}
+
+	if rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("v must be pointer to structure, got pointer to %s", rv.Elem().Kind())
+	}
+
+	b.fields = newFieldSet(rv, &b.opts)
+
+	return nil
+}
+
+// Close releases bound struct, so b doesn't keep it alive while pooled.
+// Decode fails until b is rebound with Reset.
+func (b *Binding[T]) Close() error {
+	b.fields = nil
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package tritonparser
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestBindingResetsValidity(t *testing.T) {
+	var res struct {
+		Score sql.Null[int32] `triton:"score"`
+	}
+
+	b, err := Bind[TritonModelInferResponseOutputs](&res, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	with := &Response{
+		Outputs:           []TritonModelInferResponseOutputs{&httpOutput{Name: "score", Datatype: INT32, Shape: []int64{1}}},
+		RawOutputContents: [][]byte{{1, 0, 0, 0}},
+	}
+
+	if err := b.Decode(with); err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.Score.Valid || res.Score.V != 1 {
+		t.Fatalf("got %+v, want valid 1", res.Score)
+	}
+
+	if err := b.Decode(&Response{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Score.Valid {
+		t.Errorf("got %+v after response without output, want invalid", res.Score)
+	}
+}
+
+func TestBindingPositionalFallbackPerResponse(t *testing.T) {
+	var res struct {
+		A int32 `triton:"a"`
+		B int32 `triton:"b"`
+	}
+
+	b, err := Bind[TritonModelInferResponseOutputs](&res, Options{PositionalFallback: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := &Response{
+		Outputs: []TritonModelInferResponseOutputs{
+			&httpOutput{Name: "x", Datatype: INT32, Shape: []int64{1}},
+			&httpOutput{Name: "b", Datatype: INT32, Shape: []int64{1}},
+		},
+		RawOutputContents: [][]byte{{1, 0, 0, 0}, {2, 0, 0, 0}},
+	}
+
+	if err := b.Decode(first); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.A != 1 || res.B != 2 {
+		t.Fatalf("got %+v, want {A:1 B:2}", res)
+	}
+
+	// x is at position of b now, so it must not be decoded into a as in the first response.
+	res.A, res.B = 0, 0
+	second := &Response{
+		Outputs: []TritonModelInferResponseOutputs{
+			&httpOutput{Name: "a", Datatype: INT32, Shape: []int64{1}},
+			&httpOutput{Name: "x", Datatype: INT32, Shape: []int64{1}},
+		},
+		RawOutputContents: [][]byte{{3, 0, 0, 0}, {4, 0, 0, 0}},
+	}
+
+	if err := b.Decode(second); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.A != 3 || res.B != 4 {
+		t.Errorf("got %+v, want {A:3 B:4}", res)
+	}
+}
+
+func TestBindingLifecycle(t *testing.T) {
+	type result struct {
+		Score int32 `triton:"score"`
+	}
+
+	resp := &Response{
+		Outputs:           []TritonModelInferResponseOutputs{&httpOutput{Name: "score", Datatype: INT32, Shape: []int64{1}}},
+		RawOutputContents: [][]byte{{0, 0, 0, 1}},
+	}
+
+	var first, second result
+
+	b, err := Bind[TritonModelInferResponseOutputs](&first, Options{ByteOrder: binary.BigEndian})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Decode(resp); !errors.Is(err, errBindingClosed) {
+		t.Fatalf("got error %v after Close, want %v", err, errBindingClosed)
+	}
+
+	if err := b.Reset(&second); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Decode(resp); err != nil {
+		t.Fatal(err)
+	}
+
+	// options are kept by Reset.
+	if first.Score != 0 || second.Score != 1 {
+		t.Errorf("got first %+v and second %+v, want only second decoded", first, second)
+	}
+}
+
+func TestBindingErrors(t *testing.T) {
+	var (
+		score int32
+		res   struct {
+			Score int32 `triton:"score"`
+		}
+		nilPtr *struct{}
+	)
+
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{name: "not pointer", v: res},
+		{name: "nil pointer", v: nilPtr},
+		{name: "pointer to int", v: &score},
+		{name: "pointer to map", v: &map[string]int32{}},
+		{name: "nil", v: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Bind[TritonModelInferResponseOutputs](tt.v, Options{}); err == nil {
+				t.Error("got nil error from Bind")
+			}
+
+			b, err := Bind[TritonModelInferResponseOutputs](&res, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := b.Reset(tt.v); err == nil {
+				t.Error("got nil error from Reset")
+			}
+		})
+	}
+
+	b, err := Bind[TritonModelInferResponseOutputs](&res, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	malformed := &Response{
+		Outputs:           []TritonModelInferResponseOutputs{&httpOutput{Name: "score", Datatype: INT32, Shape: []int64{1}}},
+		RawOutputContents: [][]byte{{1}},
+	}
+
+	if err := b.Decode(malformed); err == nil {
+		t.Error("got nil error for malformed contents")
+	}
+}
@@ -0,0 +1,44 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isBoxedSlice reports whether t is a slice of any or slice of slices of any.
+func isBoxedSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && scalarType(t) == reflect.TypeFor[any]()
+}
+
+// unmarshalBoxed decodes output into []any field, or [][]any for rows of multidimensional output,
+// boxing elements of native type of output datatype, e.g. float32 for FP32.
+// Outputs of any rank are flattened into []any field.
+func unmarshalBoxed(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	if field.Type().Elem().Kind() != reflect.Slice {
+		count := int64(1)
+		for _, dim := range output.GetShape() {
+			if dim < 0 {
+				return fmt.Errorf("invalid shape: %v", output.GetShape())
+			}
+
+			count *= dim
+		}
+
+		output = reshaped{output, []int64{1, count}}
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	elem := reflect.TypeFor[any]()
+	res := mapElements(val, elem, func(v reflect.Value) reflect.Value {
+		boxed := reflect.New(elem).Elem()
+		boxed.Set(v)
+
+		return boxed
+	})
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
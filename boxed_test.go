@@ -0,0 +1,122 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBoxed(t *testing.T) {
+	type result struct {
+		Floats []any   `triton:"floats"`
+		Mixed  []any   `triton:"mixed"`
+		Labels []any   `triton:"labels"`
+		Cube   []any   `triton:"cube"`
+		Rows   [][]any `triton:"rows"`
+		Flags  []any   `triton:"flags"`
+		Half   []any   `triton:"half"`
+		Empty  []any   `triton:"empty"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "floats", datatype: FLOAT32, shape: []int64{1, 2}},
+			{name: "mixed", datatype: INT64, shape: []int64{2}},
+			{name: "labels", datatype: STRING, shape: []int64{1, 2}},
+			{name: "cube", datatype: INT32, shape: []int64{2, 1, 2}},
+			{name: "rows", datatype: UINT8, shape: []int64{2, 2}},
+			{name: "flags", datatype: BOOL, shape: []int64{1, 2}},
+			{name: "half", datatype: FLOAT16, shape: []int64{1}},
+			{name: "empty", datatype: INT32, shape: []int64{0}},
+		},
+		raw: [][]byte{
+			float32Bytes(0.5, 1.5),
+			int64Bytes(-1, 2),
+			encodeStrings("a", "b"),
+			int32Bytes(1, 2, 3, 4),
+			{1, 2, 3, 4},
+			{1, 0},
+			halfBytes(0x3c00),
+			{},
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Floats: []any{float32(0.5), float32(1.5)},
+		Mixed:  []any{int64(-1), int64(2)},
+		Labels: []any{"a", "b"},
+		Cube:   []any{int32(1), int32(2), int32(3), int32(4)},
+		Rows:   [][]any{{uint8(1), uint8(2)}, {uint8(3), uint8(4)}},
+		Flags:  []any{true, false},
+		Half:   []any{float32(1)},
+		Empty:  []any{},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %#v, want %#v", res, want)
+	}
+}
+
+func TestBoxedErrors(t *testing.T) {
+	var res struct {
+		Flat []any   `triton:"flat"`
+		Rows [][]any `triton:"rows"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "short",
+			output: &testOutput{name: "flat", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 2)[:7],
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "short string",
+			output: &testOutput{name: "flat", datatype: STRING, shape: []int64{2}},
+			raw:    encodeStrings("a"),
+			want:   "string length at offset 5",
+		},
+		{
+			name:   "negative dimension",
+			output: &testOutput{name: "flat", datatype: INT32, shape: []int64{2, -1}},
+			raw:    int32Bytes(1, 2),
+			want:   "invalid shape: [2 -1]",
+		},
+		{
+			name:   "unknown datatype",
+			output: &testOutput{name: "flat", datatype: "COMPLEX", shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "COMPLEX",
+		},
+		{
+			name:   "vector into rows",
+			output: &testOutput{name: "rows", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 2),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "higher rank into rows",
+			output: &testOutput{name: "rows", datatype: INT32, shape: []int64{1, 1, 2}},
+			raw:    int32Bytes(1, 2),
+			want:   "len(shape) > 2 is not yet supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
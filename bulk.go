@@ -0,0 +1,24 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"slices"
+	"unsafe"
+)
+
+// hostLittleEndian reports whether memory layout of numbers matches little-endian raw contents.
+var hostLittleEndian = binary.NativeEndian.Uint16([]byte{1, 0}) == 1 //nolint:gochecknoglobals // constant of host.
+
+// appendReinterpreted appends elements of little-endian b to arr by copying their bytes as is,
+// which is only valid on little-endian hosts. len(b) must be a multiple of element size.
+func appendReinterpreted[T float32 | float64](b []byte, arr []T) []T {
+	var t T
+	n := len(b) / int(unsafe.Sizeof(t))
+	start := len(arr)
+	arr = slices.Grow(arr, n)[:start+n]
+
+	dst := arr[start:]
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(dst))), len(b)), b)
+
+	return arr
+}
@@ -0,0 +1,208 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+type byteOrderResult struct {
+	Scalar  int32     `triton:"scalar"`
+	Array   []uint16  `triton:"array"`
+	Matrix  [][]int64 `triton:"matrix"`
+	Strings []string  `triton:"strings"`
+}
+
+func byteOrderResponse(order binary.AppendByteOrder) *testResponse {
+	strs := order.AppendUint32(nil, 2)
+	strs = append(strs, "ab"...)
+
+	return &testResponse{
+		outputs: []*testOutput{
+			{name: "scalar", datatype: INT32, shape: []int64{1}},
+			{name: "array", datatype: UINT16, shape: []int64{1, 2}},
+			{name: "matrix", datatype: INT64, shape: []int64{2, 1}},
+			{name: "strings", datatype: STRING, shape: []int64{1, 1}},
+		},
+		raw: [][]byte{
+			order.AppendUint32(nil, 0x01020304),
+			order.AppendUint16(order.AppendUint16(nil, 0x0102), 0x0304),
+			order.AppendUint64(order.AppendUint64(nil, 5), 6),
+			strs,
+		},
+	}
+}
+
+func TestByteOrder(t *testing.T) {
+	want := byteOrderResult{
+		Scalar:  0x01020304,
+		Array:   []uint16{0x0102, 0x0304},
+		Matrix:  [][]int64{{5}, {6}},
+		Strings: []string{"ab"},
+	}
+
+	tests := []struct {
+		name  string
+		order binary.AppendByteOrder
+		opts  Options
+	}{
+		{name: "default", order: binary.LittleEndian},
+		{name: "little endian", order: binary.LittleEndian, opts: Options{ByteOrder: binary.LittleEndian}},
+		{name: "big endian", order: binary.BigEndian, opts: Options{ByteOrder: binary.BigEndian}},
+		{
+			name:  "per output",
+			order: binary.BigEndian,
+			opts:  Options{ByteOrderFor: func(string) binary.ByteOrder { return binary.BigEndian }},
+		},
+		{
+			name:  "per output fallback",
+			order: binary.BigEndian,
+			opts: Options{
+				ByteOrder:    binary.BigEndian,
+				ByteOrderFor: func(string) binary.ByteOrder { return nil },
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res byteOrderResult
+			if err := UnmarshalWithOptions(byteOrderResponse(tt.order), &res, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, want) {
+				t.Errorf("got %+v, want %+v", res, want)
+			}
+		})
+	}
+}
+
+func TestByteOrderForMixed(t *testing.T) {
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "big", datatype: INT32, shape: []int64{1}},
+			{name: "little", datatype: INT32, shape: []int64{1}},
+		},
+		raw: [][]byte{binary.BigEndian.AppendUint32(nil, 7), binary.LittleEndian.AppendUint32(nil, 8)},
+	}
+
+	var res struct {
+		Big    int32 `triton:"big"`
+		Little int32 `triton:"little"`
+	}
+
+	opts := Options{ByteOrderFor: func(name string) binary.ByteOrder {
+		if name == "big" {
+			return binary.BigEndian
+		}
+
+		return nil
+	}}
+
+	if err := UnmarshalWithOptions(resp, &res, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Big != 7 || res.Little != 8 {
+		t.Errorf("got %+v, want {Big:7 Little:8}", res)
+	}
+}
+
+func TestByteOrderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+	}{
+		{name: "short scalar", output: &testOutput{name: "scalar", datatype: INT32, shape: []int64{1}}, raw: []byte{0, 0, 1}},
+		{name: "short array", output: &testOutput{name: "array", datatype: UINT16, shape: []int64{1, 2}}, raw: []byte{0, 1, 0}},
+		{
+			name:   "string prefix",
+			output: &testOutput{name: "strings", datatype: STRING, shape: []int64{1, 1}},
+			raw:    binary.LittleEndian.AppendUint32(nil, 2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res byteOrderResult
+
+			resp := &testResponse{outputs: []*testOutput{tt.output}, raw: [][]byte{tt.raw}}
+			if err := UnmarshalWithOptions(resp, &res, Options{ByteOrder: binary.BigEndian}); err == nil {
+				t.Fatalf("got nil error, result %+v", res)
+			}
+		})
+	}
+}
+
+func TestByteOrderTag(t *testing.T) {
+	type result struct {
+		Big     int32    `triton:"big,bigendian"`
+		Little  int32    `triton:"little,littleendian"`
+		Default int32    `triton:"default"`
+		Strings []string `triton:"strings,bigendian"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "big", datatype: INT32, shape: []int64{1}},
+			{name: "little", datatype: INT32, shape: []int64{1}},
+			{name: "default", datatype: INT32, shape: []int64{1}},
+			{name: "strings", datatype: STRING, shape: []int64{1, 1}},
+		},
+		raw: [][]byte{
+			binary.BigEndian.AppendUint32(nil, 1),
+			binary.LittleEndian.AppendUint32(nil, 2),
+			binary.BigEndian.AppendUint32(nil, 3),
+			append(binary.BigEndian.AppendUint32(nil, 1), 'a'),
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want result
+	}{
+		{name: "options little endian", want: result{Big: 1, Little: 2, Default: 3 << 24, Strings: []string{"a"}}},
+		{
+			name: "options big endian",
+			opts: Options{ByteOrder: binary.BigEndian},
+			want: result{Big: 1, Little: 2, Default: 3, Strings: []string{"a"}},
+		},
+		{
+			name: "tag overrides per output",
+			opts: Options{ByteOrderFor: func(string) binary.ByteOrder { return binary.BigEndian }},
+			want: result{Big: 1, Little: 2, Default: 3, Strings: []string{"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res result
+			if err := UnmarshalWithOptions(resp, &res, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteOrderTagErrors(t *testing.T) {
+	var res struct {
+		Strings []string `triton:"strings,bigendian"`
+	}
+
+	// little-endian prefix read as big-endian claims 16M bytes.
+	resp := &testResponse{
+		outputs: []*testOutput{{name: "strings", datatype: STRING, shape: []int64{1, 1}}},
+		raw:     [][]byte{encodeStrings("a")},
+	}
+
+	if err := Unmarshal(resp, &res); err == nil {
+		t.Fatalf("got nil error, result %q", res.Strings)
+	}
+}
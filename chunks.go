@@ -0,0 +1,107 @@
+package tritonparser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// joinChunks regroups rawBytes, whose entries may be chunks of raw contents of outputs,
+// so that entry i holds the whole raw contents of output i.
+// Consecutive entries are joined until byte size of output is reached, see Options.ChunkedRawContents.
+func joinChunks[T TritonModelInferResponseOutputs](outputs []T, rawBytes [][]byte, opts *Options) ([][]byte, error) {
+	if !opts.ChunkedRawContents || len(rawBytes) <= len(outputs) {
+		return rawBytes, nil
+	}
+
+	res := make([][]byte, 0, len(outputs))
+	next := 0
+
+	for _, o := range outputs {
+		if next >= len(rawBytes) {
+			break
+		}
+
+		raw, n := rawBytes[next], 1
+		for next+n < len(rawBytes) {
+			done, err := chunksComplete(o, raw, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			if done {
+				break
+			}
+
+			if n == 1 {
+				// the first chunk is shared with response, so it's copied before appending.
+				raw = append([]byte(nil), raw...)
+			}
+
+			raw = append(raw, rawBytes[next+n]...)
+			n++
+		}
+
+		if n > 1 && opts.Trace != nil {
+			opts.tracef("output %s: joined %d chunks of raw contents into %d bytes", o.GetName(), n, len(raw))
+		}
+
+		res = append(res, raw)
+		next += n
+	}
+
+	if next < len(rawBytes) {
+		return nil, fmt.Errorf("%d chunks of raw contents left after the last output", len(rawBytes)-next)
+	}
+
+	return res, nil
+}
+
+// chunksComplete reports whether raw holds the whole raw contents of output.
+// Its size is given by binary_data_size parameter, implied by datatype and shape,
+// or, for STRING outputs, found by walking length prefixes of its elements.
+func chunksComplete(output TritonModelInferResponseOutputs, raw []byte, opts *Options) (bool, error) {
+	if params, ok := getParameters(output); ok && params[binaryDataSize] != nil {
+		size, ok := intParameter(params[binaryDataSize])
+		if !ok {
+			return false, fmt.Errorf("output %s: invalid %s: %v", output.GetName(), binaryDataSize, params[binaryDataSize])
+		}
+
+		return int64(len(raw)) >= size, nil
+	}
+
+	size, err := OutputByteSize(output.GetDatatype(), output.GetShape())
+	switch {
+	case err == nil:
+		return len(raw) >= size, nil
+	case !errors.Is(err, ErrVariableSize):
+		return false, fmt.Errorf("output %s: %w", output.GetName(), err)
+	}
+
+	count := int64(1)
+	for _, dim := range output.GetShape() {
+		if dim < 0 {
+			return false, fmt.Errorf("output %s: invalid shape: %v", output.GetName(), output.GetShape())
+		}
+
+		// every element takes at least its length prefix.
+		if dim != 0 && count > math.MaxInt/4/dim {
+			return false, fmt.Errorf("output %s: shape %v is too large", output.GetName(), output.GetShape())
+		}
+
+		count *= dim
+	}
+
+	order := opts.byteOrder(output.GetName())
+	for i, offset := int64(0), 0; i < count; i++ {
+		_, end, err := stringBounds(raw, offset, order, opts.StringLengthPrefix)
+		if err != nil {
+			// element is cut by the end of chunk.
+			return false, nil //nolint:nilerr // incomplete contents are not an error here.
+		}
+
+		offset = end
+	}
+
+	return true, nil
+}
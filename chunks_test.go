@@ -0,0 +1,193 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type chunksResult struct {
+	Ints  []int32  `triton:"ints"`
+	Words []string `triton:"words"`
+	Value int64    `triton:"value"`
+}
+
+func TestChunkedRawContents(t *testing.T) {
+	words := encodeStrings("ab", "cde")
+
+	tests := []struct {
+		name    string
+		outputs []TritonModelInferResponseOutputs
+		raw     [][]byte
+		want    chunksResult
+	}{
+		{
+			name: "fixed size",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "ints", Datatype: INT32, Shape: []int64{1, 3}},
+				&httpOutput{Name: "value", Datatype: INT64, Shape: []int64{1}},
+			},
+			raw:  [][]byte{int32Bytes(1), int32Bytes(2, 3)[:6], int32Bytes(2, 3)[6:], int64Bytes(4)},
+			want: chunksResult{Ints: []int32{1, 2, 3}, Value: 4},
+		},
+		{
+			name: "strings cut within prefix and element",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "words", Datatype: STRING, Shape: []int64{2}},
+				&httpOutput{Name: "value", Datatype: INT64, Shape: []int64{1}},
+			},
+			raw:  [][]byte{words[:2], words[2:7], words[7:], int64Bytes(5)},
+			want: chunksResult{Words: []string{"ab", "cde"}, Value: 5},
+		},
+		{
+			name: "binary data size",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "ints", Datatype: INT32, Shape: []int64{1, 2}, Parameters: map[string]any{binaryDataSize: int64(8)}},
+				&httpOutput{Name: "value", Datatype: INT64, Shape: []int64{1}, Parameters: map[string]any{binaryDataSize: uint64(8)}},
+			},
+			raw:  [][]byte{int32Bytes(6), int32Bytes(7), int64Bytes(8)},
+			want: chunksResult{Ints: []int32{6, 7}, Value: 8},
+		},
+		{
+			name: "empty output",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "ints", Datatype: INT32, Shape: []int64{1, 0}},
+				&httpOutput{Name: "value", Datatype: INT64, Shape: []int64{1}},
+			},
+			raw:  [][]byte{{}, int64Bytes(9)[:4], int64Bytes(9)[4:]},
+			want: chunksResult{Ints: []int32{}, Value: 9},
+		},
+		{
+			name: "entry per output",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "ints", Datatype: INT32, Shape: []int64{1, 1}},
+				&httpOutput{Name: "value", Datatype: INT64, Shape: []int64{1}},
+			},
+			raw:  [][]byte{int32Bytes(1), int64Bytes(2)},
+			want: chunksResult{Ints: []int32{1}, Value: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res chunksResult
+
+			resp := &Response{Outputs: tt.outputs, RawOutputContents: tt.raw}
+			if err := UnmarshalWithOptions(resp, &res, Options{ChunkedRawContents: true}); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkedRawContentsCopy(t *testing.T) {
+	// the first chunk has spare capacity, which must not be overwritten by joined chunks.
+	buf := append(int32Bytes(1), int32Bytes(100)...)
+	resp := &Response{
+		Outputs:           []TritonModelInferResponseOutputs{&httpOutput{Name: "ints", Datatype: INT32, Shape: []int64{1, 2}}},
+		RawOutputContents: [][]byte{buf[:4], int32Bytes(2)},
+	}
+
+	var res chunksResult
+	if err := UnmarshalWithOptions(resp, &res, Options{ChunkedRawContents: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res.Ints, []int32{1, 2}) {
+		t.Errorf("got %v, want [1 2]", res.Ints)
+	}
+
+	if !reflect.DeepEqual(buf, int32Bytes(1, 100)) {
+		t.Errorf("first chunk was modified: %v", buf)
+	}
+}
+
+func TestChunkedRawContentsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []TritonModelInferResponseOutputs
+		raw     [][]byte
+		want    string
+	}{
+		{
+			name:    "chunks left",
+			outputs: []TritonModelInferResponseOutputs{&httpOutput{Name: "value", Datatype: INT64, Shape: []int64{1}}},
+			raw:     [][]byte{int64Bytes(1), {1}, {2}},
+			want:    "2 chunks of raw contents left after the last output",
+		},
+		{
+			name: "chunks exhausted",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "ints", Datatype: INT32, Shape: []int64{1, 3}},
+				&httpOutput{Name: "value", Datatype: INT64, Shape: []int64{1}},
+			},
+			raw:  [][]byte{int32Bytes(1), int32Bytes(2), int32Bytes(3)},
+			want: "output value: raw contents are missing",
+		},
+		{
+			name: "negative binary data size",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "value", Datatype: INT64, Shape: []int64{1}, Parameters: map[string]any{binaryDataSize: int64(-1)}},
+			},
+			raw:  [][]byte{int64Bytes(1)[:4], int64Bytes(1)[4:]},
+			want: "output value: invalid binary_data_size: -1",
+		},
+		{
+			name: "float binary data size",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "value", Datatype: INT64, Shape: []int64{1}, Parameters: map[string]any{binaryDataSize: 8.0}},
+			},
+			raw:  [][]byte{int64Bytes(1)[:4], int64Bytes(1)[4:]},
+			want: "output value: invalid binary_data_size: 8",
+		},
+		{
+			name: "negative dimension",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "ints", Datatype: INT32, Shape: []int64{1, -1}},
+			},
+			raw:  [][]byte{int32Bytes(1), int32Bytes(2)},
+			want: "output ints: invalid shape: [1 -1]",
+		},
+		{
+			name: "negative string dimension",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "words", Datatype: STRING, Shape: []int64{2, -1}},
+			},
+			raw:  [][]byte{encodeStrings("a"), encodeStrings("b")},
+			want: "output words: invalid shape: [2 -1]",
+		},
+		{
+			name: "hostile string shape",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "words", Datatype: STRING, Shape: []int64{1 << 32, 1 << 32}},
+			},
+			raw:  [][]byte{encodeStrings("a"), encodeStrings("b")},
+			want: "output words: shape [4294967296 4294967296] is too large",
+		},
+		{
+			name: "string cut by the last chunk",
+			outputs: []TritonModelInferResponseOutputs{
+				&httpOutput{Name: "words", Datatype: STRING, Shape: []int64{2}},
+			},
+			raw:  [][]byte{encodeStrings("ab"), encodeStrings("cd")[:5]},
+			want: "output words",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res chunksResult
+
+			resp := &Response{Outputs: tt.outputs, RawOutputContents: tt.raw}
+
+			err := UnmarshalWithOptions(resp, &res, Options{ChunkedRawContents: true})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
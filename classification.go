@@ -0,0 +1,72 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Classification is an entry of output produced by Triton classification extension.
+type Classification struct {
+	Score float32
+	Index int
+	Label string
+}
+
+// ParseClassification parses classification entry in "score:index:label" format, e.g. "0.95:3:cat".
+// Label is optional.
+func ParseClassification(s string) (Classification, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) < 2 {
+		return Classification{}, fmt.Errorf("invalid classification: %q", s)
+	}
+
+	score, err := strconv.ParseFloat(parts[0], 32)
+	if err != nil {
+		return Classification{}, fmt.Errorf("invalid classification score: %w", err)
+	}
+
+	idx, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Classification{}, fmt.Errorf("invalid classification index: %w", err)
+	}
+
+	c := Classification{Score: float32(score), Index: idx}
+	if len(parts) == 3 {
+		c.Label = parts[2]
+	}
+
+	return c, nil
+}
+
+// decodeClassifications decodes STRING output into Classification field or slices of them.
+func decodeClassifications(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	if output.GetDatatype() != STRING {
+		return fmt.Errorf("output %s: classification requires %s datatype, got %s", output.GetName(), STRING, output.GetDatatype())
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	res := mapElements(val, reflect.TypeFor[Classification](), func(v reflect.Value) reflect.Value {
+		if err != nil {
+			return reflect.ValueOf(Classification{})
+		}
+
+		var c Classification
+		c, err = ParseClassification(v.String())
+		if err != nil {
+			err = fmt.Errorf("output %s: %w", output.GetName(), err)
+		}
+
+		return reflect.ValueOf(c)
+	})
+	if err != nil {
+		return err
+	}
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
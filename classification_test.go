@@ -0,0 +1,123 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseClassification(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Classification
+		wantErr string
+	}{
+		{in: "0.95:3:cat", want: Classification{Score: 0.95, Index: 3, Label: "cat"}},
+		{in: "0.5:0", want: Classification{Score: 0.5}},
+		{in: "1:2:a:b", want: Classification{Score: 1, Index: 2, Label: "a:b"}},
+		{in: "0.1:4:", want: Classification{Score: 0.1, Index: 4}},
+		{in: "0.95", wantErr: "invalid classification"},
+		{in: "", wantErr: "invalid classification"},
+		{in: "high:3:cat", wantErr: "invalid classification score"},
+		{in: "0.95:three:cat", wantErr: "invalid classification index"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseClassification(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseClassification(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+
+			continue
+		}
+
+		if err != nil || got != tt.want {
+			t.Errorf("ParseClassification(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestClassifications(t *testing.T) {
+	type result struct {
+		Top   Classification     `triton:"top"`
+		List  []Classification   `triton:"list"`
+		Batch [][]Classification `triton:"batch"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "top", datatype: STRING, shape: []int64{1}},
+			{name: "list", datatype: STRING, shape: []int64{1, 2}},
+			{name: "batch", datatype: STRING, shape: []int64{2, 1}},
+		},
+		raw: [][]byte{
+			encodeStrings("0.5:1:dog"),
+			encodeStrings("0.5:1:dog", "0.25:2"),
+			encodeStrings("1:0:a", "0:1:b"),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Top:   Classification{Score: 0.5, Index: 1, Label: "dog"},
+		List:  []Classification{{Score: 0.5, Index: 1, Label: "dog"}, {Score: 0.25, Index: 2}},
+		Batch: [][]Classification{{{Score: 1, Label: "a"}}, {{Index: 1, Label: "b"}}},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestClassificationsErrors(t *testing.T) {
+	var res struct {
+		Top  Classification   `triton:"top"`
+		List []Classification `triton:"list"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "datatype",
+			output: &testOutput{name: "top", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(1),
+			want:   "classification requires BYTES datatype",
+		},
+		{
+			name:   "entry",
+			output: &testOutput{name: "list", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("0.5:1", "bad"),
+			want:   "output list: invalid classification",
+		},
+		{
+			name:   "shape",
+			output: &testOutput{name: "top", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("0.5:1", "0.5:2"),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "contents",
+			output: &testOutput{name: "list", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("0.5:1"),
+			want:   "output list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
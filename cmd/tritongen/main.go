@@ -0,0 +1,254 @@
+// Tritongen generates reflection-free decoders of triton tagged structs.
+//
+// It's meant to be run by go generate:
+//
+//	//go:generate go run github.com/TiregeRRR/triton_parser/cmd/tritongen -type=Result
+//
+// For every listed type it writes function
+//
+//	func DecodeResult[T tritonparser.TritonModelInferResponseOutputs](
+//		inferResponse tritonparser.TritonModelInferResponse[T], v *Result) error
+//
+// into file <source>_triton.go. Fields must be of predeclared boolean, numeric or string type,
+// or slices or slices of slices of them, decoded with tritonparser.DecodeValue, DecodeArray and DecodeMatrix.
+// The only supported tag option is required. Raw contents are little-endian.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+)
+
+const importPath = "github.com/TiregeRRR/triton_parser"
+
+//nolint:gochecknoglobals // predeclared types are constant.
+var elements = map[string]bool{
+	"bool": true, "string": true, "float32": true, "float64": true,
+	"int8": true, "int16": true, "int32": true, "int64": true,
+	"uint8": true, "uint16": true, "uint32": true, "uint64": true, "byte": true,
+}
+
+// field is a struct field decoded from output.
+type field struct {
+	name     string
+	output   string
+	decoder  string
+	elem     string
+	required bool
+}
+
+func main() {
+	types := flag.String("type", "", "comma-separated names of struct types")
+	file := flag.String("file", os.Getenv("GOFILE"), "source file declaring types, $GOFILE by default")
+	flag.Parse()
+
+	if *types == "" || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*file, strings.Split(*types, ",")); err != nil {
+		log.Fatalf("tritongen: %s", err)
+	}
+}
+
+func run(file string, types []string) error {
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, file, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	decoders := make([][]field, len(types))
+	required := false
+
+	for i, name := range types {
+		st, err := lookupStruct(f, name)
+		if err != nil {
+			return err
+		}
+
+		if decoders[i], err = parseFields(name, st); err != nil {
+			return err
+		}
+
+		for _, fd := range decoders[i] {
+			required = required || fd.required
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by tritongen; DO NOT EDIT.\n\npackage %s\n\nimport (\n", f.Name.Name)
+
+	if required {
+		buf.WriteString("\"errors\"\n\n")
+	}
+
+	fmt.Fprintf(&buf, "tritonparser %q\n)\n", importPath)
+
+	for i, name := range types {
+		writeDecoder(&buf, name, decoders[i])
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated code: %w", err)
+	}
+
+	out := strings.TrimSuffix(file, ".go") + "_triton.go"
+
+	return os.WriteFile(out, src, 0o600)
+}
+
+// lookupStruct returns declaration of struct type name in f.
+func lookupStruct(f *ast.File, name string) (*ast.StructType, error) {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec) //nolint:forcetypeassert // specs of type declarations are type specs.
+			if ts.Name.Name != name {
+				continue
+			}
+
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return nil, fmt.Errorf("type %s is not a struct", name)
+			}
+
+			return st, nil
+		}
+	}
+
+	return nil, fmt.Errorf("type %s is not declared in %s", name, f.Name.Name)
+}
+
+// parseFields returns triton tagged fields of struct st.
+func parseFields(typeName string, st *ast.StructType) ([]field, error) {
+	var res []field
+
+	for _, f := range st.Fields.List {
+		if f.Tag == nil || len(f.Names) != 1 {
+			continue
+		}
+
+		tag := reflect.StructTag(strings.Trim(f.Tag.Value, "`")).Get("triton")
+		if tag == "" {
+			continue
+		}
+
+		output, opts, _ := strings.Cut(tag, ",")
+		fd := field{name: f.Names[0].Name, output: output}
+
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "":
+			case "required":
+				fd.required = true
+			default:
+				return nil, fmt.Errorf("%s.%s: tag option %q is not supported", typeName, fd.name, opt)
+			}
+		}
+
+		var err error
+		if fd.decoder, fd.elem, err = decoderOf(f.Type); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, fd.name, err)
+		}
+
+		res = append(res, fd)
+	}
+
+	return res, nil
+}
+
+// decoderOf returns name of tritonparser function decoding field of type expr and its element type.
+func decoderOf(expr ast.Expr) (string, string, error) {
+	decoders := []string{"DecodeValue", "DecodeArray", "DecodeMatrix"}
+
+	for _, decoder := range decoders {
+		if ident, ok := expr.(*ast.Ident); ok && elements[ident.Name] {
+			return decoder, ident.Name, nil
+		}
+
+		arr, ok := expr.(*ast.ArrayType)
+		if !ok || arr.Len != nil {
+			break
+		}
+
+		expr = arr.Elt
+	}
+
+	return "", "", errors.New("field type must be a predeclared boolean, numeric or string type, or slice of it")
+}
+
+// writeDecoder writes decoder of struct type name with fields to buf.
+func writeDecoder(buf *bytes.Buffer, name string, fields []field) {
+	fmt.Fprintf(buf, "\n// Decode%[1]s decodes inferResponse into v without reflection.\n", name)
+	fmt.Fprintf(buf, "func Decode%[1]s[T tritonparser.TritonModelInferResponseOutputs]("+
+		"inferResponse tritonparser.TritonModelInferResponse[T], v *%[1]s) error {\n", name)
+	buf.WriteString("raw := inferResponse.GetRawOutputContents()\n")
+
+	for i, fd := range fields {
+		if fd.required {
+			fmt.Fprintf(buf, "found%d := false\n", i)
+		}
+	}
+
+	buf.WriteString("for i, o := range inferResponse.GetOutputs() {\n")
+	buf.WriteString("var b []byte\nif i < len(raw) {\nb = raw[i]\n}\n\n")
+	buf.WriteString("var err error\nswitch o.GetName() {\n")
+
+	seen := make(map[string]bool)
+
+	for i, fd := range fields {
+		if seen[fd.output] {
+			continue
+		}
+
+		seen[fd.output] = true
+
+		fmt.Fprintf(buf, "case %q:\n", fd.output)
+
+		// fields sharing output name receive the same value.
+		for j := i; j < len(fields); j++ {
+			if fields[j].output != fd.output {
+				continue
+			}
+
+			decode := fmt.Sprintf("v.%s, err = tritonparser.%s[%s](o, b)\n", fields[j].name, fields[j].decoder, fields[j].elem)
+			if j != i {
+				decode = "if err == nil {\n" + decode + "}\n"
+			}
+
+			buf.WriteString(decode)
+
+			if fields[j].required {
+				fmt.Fprintf(buf, "found%d = true\n", j)
+			}
+		}
+	}
+
+	buf.WriteString("}\n\nif err != nil {\nreturn err\n}\n}\n\n")
+
+	for i, fd := range fields {
+		if fd.required {
+			fmt.Fprintf(buf, "if !found%d {\nreturn errors.New(%q)\n}\n\n", i, "required output "+fd.output+" is missing")
+		}
+	}
+
+	buf.WriteString("return nil\n}\n")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//nolint:gochecknoglobals // test flag.
+var update = flag.Bool("update", false, "update golden files")
+
+func TestRunGolden(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "result.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(t.TempDir(), "result.go")
+	if err := os.WriteFile(file, src, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run(file, []string{"Result"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(filepath.Dir(file), "result_triton.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "result_triton.go.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("generated code doesn't match %s:\n%s", golden, got)
+	}
+
+	checkCompiles(t, src, got)
+}
+
+// checkCompiles builds package of source and generated files inside the module, so generated code is
+// checked against current tritonparser API.
+func checkCompiles(t *testing.T, src, generated []byte) {
+	t.Helper()
+
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command is not available")
+	}
+
+	dir, err := os.MkdirTemp("testdata", "build")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := os.WriteFile(filepath.Join(dir, "result.go"), src, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "result_triton.go"), generated, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command(gobin, "vet", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("generated code doesn't compile: %s\n%s", err, out)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		typ  string
+		want string
+	}{
+		{name: "missing type", src: "type Other struct{}", typ: "Result", want: "is not declared"},
+		{name: "not struct", src: "type Result int", typ: "Result", want: "is not a struct"},
+		{name: "option", src: "type Result struct{ A int32 `triton:\"a,ragged\"` }", typ: "Result", want: "not supported"},
+		{name: "map", src: "type Result struct{ A map[string]int32 `triton:\"a\"` }", typ: "Result", want: "field type"},
+		{name: "fixed array", src: "type Result struct{ A [2]int32 `triton:\"a\"` }", typ: "Result", want: "field type"},
+		{name: "rank 3", src: "type Result struct{ A [][][]int32 `triton:\"a\"` }", typ: "Result", want: "field type"},
+		{name: "named type", src: "type Result struct{ A Score `triton:\"a\"` }", typ: "Result", want: "field type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "result.go")
+			if err := os.WriteFile(file, []byte("package testdata\n\n"+tt.src+"\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			err := run(file, []string{tt.typ})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package testdata
+
+type Result struct {
+	Score   float32   `triton:"score,required"`
+	Labels  []string  `triton:"labels"`
+	Boxes   [][]int32 `triton:"boxes"`
+	Ok      bool      `triton:"ok"`
+	Scores  []float32 `triton:"score"`
+	Ignored int64
+	Raw     []byte      `triton:"raw,required"`
+	Matrix  [][]float64 `triton:"matrix"`
+}
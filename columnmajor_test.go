@@ -0,0 +1,95 @@
+package tritonparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestColumnMajor(t *testing.T) {
+	tests := []struct {
+		name        string
+		datatype    string
+		raw         []byte
+		columnMajor bool
+		wantInts    [][]int32
+		wantString  [][]string
+	}{
+		{name: "row major", datatype: INT32, raw: int32Bytes(1, 2, 3, 4, 5, 6), wantInts: [][]int32{{1, 2, 3}, {4, 5, 6}}},
+		{
+			name:        "column major",
+			datatype:    INT32,
+			raw:         int32Bytes(1, 4, 2, 5, 3, 6),
+			columnMajor: true,
+			wantInts:    [][]int32{{1, 2, 3}, {4, 5, 6}},
+		},
+		{
+			name:        "column major strings",
+			datatype:    STRING,
+			raw:         encodeStrings("a", "d", "b", "e", "c", "f"),
+			columnMajor: true,
+			wantString:  [][]string{{"a", "b", "c"}, {"d", "e", "f"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res struct {
+				Ints    [][]int32  `triton:"ints"`
+				Strings [][]string `triton:"strings"`
+			}
+
+			name := "ints"
+			if tt.datatype == STRING {
+				name = "strings"
+			}
+
+			resp := &testResponse{
+				outputs: []*testOutput{{name: name, datatype: tt.datatype, shape: []int64{2, 3}}},
+				raw:     [][]byte{tt.raw},
+			}
+
+			if err := UnmarshalWithOptions(resp, &res, Options{ColumnMajor: tt.columnMajor}); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res.Ints, tt.wantInts) || !reflect.DeepEqual(res.Strings, tt.wantString) {
+				t.Errorf("got %v %v, want %v %v", res.Ints, res.Strings, tt.wantInts, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestColumnMajorErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		datatype string
+		raw      []byte
+	}{
+		{name: "short", datatype: INT32, raw: int32Bytes(1, 4, 2, 5, 3)},
+		{name: "misaligned", datatype: INT32, raw: int32Bytes(1, 4, 2, 5, 3, 6)[:22]},
+		{name: "short strings", datatype: STRING, raw: encodeStrings("a", "d", "b")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res struct {
+				Ints    [][]int32  `triton:"ints"`
+				Strings [][]string `triton:"strings"`
+			}
+
+			name := "ints"
+			if tt.datatype == STRING {
+				name = "strings"
+			}
+
+			resp := &testResponse{
+				outputs: []*testOutput{{name: name, datatype: tt.datatype, shape: []int64{2, 3}}},
+				raw:     [][]byte{tt.raw},
+			}
+
+			if err := UnmarshalWithOptions(resp, &res, Options{ColumnMajor: true}); err == nil {
+				t.Fatalf("got nil error, result %v %v", res.Ints, res.Strings)
+			}
+		})
+	}
+}
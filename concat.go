@@ -0,0 +1,122 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// concatField is a field receiving concatenation of one-dimensional outputs.
+type concatField struct {
+	name  string
+	field reflect.Value
+	parts []string
+}
+
+// getConcatFields returns fields tagged with concat option in order of fields declaration.
+func getConcatFields(rv reflect.Value, opts *Options) []concatField {
+	fieldsNum := rv.Elem().NumField()
+	var res []concatField
+
+	for i := 0; i < fieldsNum; i++ {
+		name, tagOpts := parseTag(opts.fieldTag(rv.Elem().Type().Field(i)))
+		if parts, ok := tagOpts.Get(tagConcat); ok {
+			res = append(res, concatField{name: name, field: rv.Elem().Field(i), parts: strings.Split(parts, "|")})
+		}
+	}
+
+	return res
+}
+
+// decodeConcat decodes parts of cf in order and stores their concatenation to its field.
+// Parts must share datatype. Field is untouched and false is returned if none of parts is present.
+func decodeConcat[T TritonModelInferResponseOutputs](cf concatField, outputs []T, rawBytes [][]byte, opts *Options) (bool, error) {
+	idx := make(map[string]int, len(outputs))
+	for i, o := range outputs {
+		idx[o.GetName()] = i
+	}
+
+	var (
+		res      reflect.Value
+		datatype string
+		found    int
+	)
+
+	for _, part := range cf.parts {
+		i, ok := idx[part]
+		if !ok {
+			continue
+		}
+
+		found++
+		o := outputs[i]
+
+		switch {
+		case datatype == "":
+			datatype = o.GetDatatype()
+		case datatype != o.GetDatatype():
+			return false, fmt.Errorf("output %s: %s option requires parts of the same datatype, got %s and %s",
+				cf.name, tagConcat, datatype, o.GetDatatype())
+		}
+
+		val, err := decodeVector(o, i, rawBytes, opts)
+		if err != nil {
+			return false, fmt.Errorf("output %s: %s option: %w", cf.name, tagConcat, err)
+		}
+
+		if !res.IsValid() {
+			res = reflect.MakeSlice(val.Type(), 0, val.Len())
+		}
+
+		res = reflect.AppendSlice(res, val)
+	}
+
+	switch found {
+	case 0:
+		return false, nil
+	case len(cf.parts):
+	default:
+		return false, fmt.Errorf("output %s: %d of %d %s parts are missing", cf.name, len(cf.parts)-found, len(cf.parts), tagConcat)
+	}
+
+	if opts.Trace != nil {
+		opts.tracef("output %s: concatenated %d parts", cf.name, found)
+	}
+
+	if err := setTransformed(cf.field, res, cf.name, opts); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// decodeVector decodes output i of one-dimensional shape, [N] or [1, N], into slice of its native type.
+func decodeVector[T TritonModelInferResponseOutputs](o T, i int, rawBytes [][]byte, opts *Options) (reflect.Value, error) {
+	raw, err := rawContents(o, i, rawBytes, opts)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	opts.Stats.add(o, raw)
+
+	b, err := decompress(raw, opts)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("output %s: %w", o.GetName(), err)
+	}
+
+	var output TritonModelInferResponseOutputs = o
+	if shape := o.GetShape(); len(shape) == 1 {
+		output = reshaped{o, []int64{1, shape[0]}}
+	}
+
+	val, err := decodeNative(output, b, opts)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if val.Type().Elem().Kind() == reflect.Slice {
+		return reflect.Value{}, fmt.Errorf("output %s: one-dimensional shape required, got %v", o.GetName(), o.GetShape())
+	}
+
+	return val, nil
+}
@@ -0,0 +1,101 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	type result struct {
+		Emb    []float32 `triton:"emb,concat=emb_0|emb_1|emb_2"`
+		Labels []string  `triton:"labels,concat=labels_0|labels_1"`
+		Absent []int32   `triton:"absent,concat=absent_0|absent_1"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "emb_1", datatype: FLOAT32, shape: []int64{2}},
+			{name: "emb_0", datatype: FLOAT32, shape: []int64{1, 1}},
+			{name: "emb_2", datatype: FLOAT32, shape: []int64{1, 0}},
+			{name: "labels_0", datatype: STRING, shape: []int64{1}},
+			{name: "labels_1", datatype: STRING, shape: []int64{1, 2}},
+		},
+		raw: [][]byte{float32Bytes(2, 3), float32Bytes(1), {}, encodeStrings("a"), encodeStrings("b", "c")},
+	}
+
+	res := result{Absent: []int32{7}}
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{Emb: []float32{1, 2, 3}, Labels: []string{"a", "b", "c"}, Absent: []int32{7}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestConcatErrors(t *testing.T) {
+	var res struct {
+		Emb []float32 `triton:"emb,concat=emb_0|emb_1"`
+	}
+
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		want    string
+	}{
+		{
+			name: "datatypes",
+			outputs: []*testOutput{
+				{name: "emb_0", datatype: FLOAT32, shape: []int64{1}},
+				{name: "emb_1", datatype: FLOAT64, shape: []int64{1}},
+			},
+			raw:  [][]byte{float32Bytes(1), int64Bytes(2)},
+			want: "output emb: concat option requires parts of the same datatype, got FP32 and FP64",
+		},
+		{
+			name:    "missing part",
+			outputs: []*testOutput{{name: "emb_1", datatype: FLOAT32, shape: []int64{1}}},
+			raw:     [][]byte{float32Bytes(1)},
+			want:    "output emb: 1 of 2 concat parts are missing",
+		},
+		{
+			name: "matrix part",
+			outputs: []*testOutput{
+				{name: "emb_0", datatype: FLOAT32, shape: []int64{2, 1}},
+				{name: "emb_1", datatype: FLOAT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2), float32Bytes(3)},
+			want: "output emb_0: one-dimensional shape required, got [2 1]",
+		},
+		{
+			name: "short part",
+			outputs: []*testOutput{
+				{name: "emb_0", datatype: FLOAT32, shape: []int64{1}},
+				{name: "emb_1", datatype: FLOAT32, shape: []int64{2}},
+			},
+			raw:  [][]byte{float32Bytes(1), float32Bytes(2)[:3]},
+			want: "output emb: concat option",
+		},
+		{
+			name: "field type",
+			outputs: []*testOutput{
+				{name: "emb_0", datatype: INT32, shape: []int64{1}},
+				{name: "emb_1", datatype: INT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{int32Bytes(1), int32Bytes(2)},
+			want: "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package tritonparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+var numberType = reflect.TypeFor[json.Number]()
+
+// LossyConversion describes coercion of decoded value into destination type, e.g. a narrower one.
+type LossyConversion struct {
+	// Output is the name of converted output.
+	Output string
+	// From is the type decoded from datatype of output.
+	From reflect.Type
+	// To is the type of destination field.
+	To reflect.Type
+}
+
+func (c LossyConversion) String() string {
+	return fmt.Sprintf("output %s: %s -> %s", c.Output, c.From, c.To)
+}
+
+// checkType returns error if value of type exp can't be stored to field.
+func checkType(field reflect.Value, exp reflect.Type, opts *Options) error {
+	if field.Type() == exp {
+		return nil
+	}
+
+	if (opts.Coerce || opts.OnTypeMismatch != nil) && isConvertible(exp, field.Type()) {
+		return nil
+	}
+
+	if isNumberConvertible(exp, field.Type()) || isNamed(exp, field.Type()) {
+		return nil
+	}
+
+	return fmt.Errorf("types doesn't match exp: %s got: %s", exp.String(), field.Type().String())
+}
+
+// setValue stores val to field, converting it if Options.Coerce or Options.OnTypeMismatch allows it.
+func setValue(field, val reflect.Value, output string, opts *Options) error {
+	if field.Type() == val.Type() {
+		field.Set(val)
+
+		return nil
+	}
+
+	from, to := scalarType(val.Type()), scalarType(field.Type())
+	if opts.OnLossyConversion != nil && isLossy(from, to) {
+		c := LossyConversion{Output: output, From: from, To: to}
+		if err := opts.OnLossyConversion(c); err != nil {
+			return fmt.Errorf("lossy conversion %s: %w", c, err)
+		}
+	}
+
+	mismatch := opts.OnTypeMismatch != nil && isConvertible(val.Type(), field.Type()) && !isNamed(val.Type(), field.Type())
+
+	res, err := convert(val, field.Type(), mismatch)
+	if err != nil {
+		return fmt.Errorf("output %s: %w", output, err)
+	}
+
+	field.Set(res)
+
+	if mismatch {
+		opts.OnTypeMismatch(LossyConversion{Output: output, From: from, To: to})
+	}
+
+	return nil
+}
+
+// convert converts val to type to. Values out of range of to are rejected if checkRange is set,
+// otherwise only changes of sign are.
+func convert(val reflect.Value, to reflect.Type, checkRange bool) (reflect.Value, error) {
+	if val.Kind() != reflect.Slice {
+		if to == numberType && isNumeric(val.Kind()) {
+			return formatNumber(val), nil
+		}
+
+		if err := checkSign(val, to); err != nil {
+			return reflect.Value{}, err
+		}
+
+		if checkRange {
+			if err := checkOverflow(val, to); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+
+		return val.Convert(to), nil
+	}
+
+	res := reflect.MakeSlice(to, val.Len(), val.Len())
+	for i := 0; i < val.Len(); i++ {
+		v, err := convert(val.Index(i), to.Elem(), checkRange)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		res.Index(i).Set(v)
+	}
+
+	return res, nil
+}
+
+// checkSign returns error if integer val changes sign when converted to to,
+// e.g. negative value into unsigned type or large unsigned value into signed one.
+func checkSign(val reflect.Value, to reflect.Type) error {
+	switch {
+	case isInt(val.Kind()) && isUint(to.Kind()) && val.Int() < 0:
+		return fmt.Errorf("negative value %d overflows %s", val.Int(), to)
+	case isUint(val.Kind()) && isInt(to.Kind()) &&
+		(val.Uint() > math.MaxInt64 || reflect.Zero(to).OverflowInt(int64(val.Uint()))):
+		return fmt.Errorf("value %d overflows %s", val.Uint(), to)
+	default:
+		return nil
+	}
+}
+
+// checkOverflow returns error if numeric val is out of range of to.
+// Changes of sign are checked by checkSign.
+func checkOverflow(val reflect.Value, to reflect.Type) error {
+	zero := reflect.Zero(to)
+
+	switch k, tk := val.Kind(), to.Kind(); {
+	case isInt(k) && isInt(tk) && zero.OverflowInt(val.Int()),
+		isInt(k) && isUint(tk) && zero.OverflowUint(uint64(val.Int())),
+		isUint(k) && isUint(tk) && zero.OverflowUint(val.Uint()),
+		isFloat(k) && isFloat(tk) && zero.OverflowFloat(val.Float()):
+		return fmt.Errorf("value %v overflows %s", val, to)
+	case isFloat(k) && !isFloat(tk):
+		// hi+1 is a power of two, so it's exact even for 64-bit types.
+		lo, hi := intRange(to)
+		if f := val.Float(); !(f >= lo && f < hi+1) {
+			return fmt.Errorf("value %v overflows %s", val, to)
+		}
+	}
+
+	return nil
+}
+
+// isConvertible reports whether from can be coerced to to.
+// Only numeric values and slices of them are coerced.
+func isConvertible(from, to reflect.Type) bool {
+	if from.Kind() == reflect.Slice && to.Kind() == reflect.Slice {
+		return isConvertible(from.Elem(), to.Elem())
+	}
+
+	return isNumeric(from.Kind()) && isNumeric(to.Kind())
+}
+
+// isNamed reports whether to is a named type with the same kind as from, e.g. type Label int32,
+// or slices of them. Such types represent exactly the same values, so they don't require coercion.
+func isNamed(from, to reflect.Type) bool {
+	if from.Kind() == reflect.Slice && to.Kind() == reflect.Slice {
+		return isNamed(from.Elem(), to.Elem())
+	}
+
+	switch from.Kind() { //nolint:exhaustive // only basic kinds are decoded.
+	case reflect.Slice, reflect.Struct, reflect.Interface, reflect.Pointer, reflect.Map:
+		return false
+	default:
+		return from.Kind() == to.Kind() && from.ConvertibleTo(to)
+	}
+}
+
+// isNumberConvertible reports whether numeric from can be formatted into json.Number to,
+// or slices of them. Formatting keeps exact representation, so it doesn't require coercion.
+func isNumberConvertible(from, to reflect.Type) bool {
+	if from.Kind() == reflect.Slice && to.Kind() == reflect.Slice {
+		return isNumberConvertible(from.Elem(), to.Elem())
+	}
+
+	return isNumeric(from.Kind()) && to == numberType
+}
+
+// formatNumber formats numeric val into json.Number.
+func formatNumber(val reflect.Value) reflect.Value {
+	var s string
+
+	switch k := val.Kind(); {
+	case isInt(k):
+		s = strconv.FormatInt(val.Int(), 10)
+	case isUint(k):
+		s = strconv.FormatUint(val.Uint(), 10)
+	default:
+		s = strconv.FormatFloat(val.Float(), 'g', -1, val.Type().Bits())
+	}
+
+	return reflect.ValueOf(json.Number(s))
+}
+
+func scalarType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func isNumeric(k reflect.Kind) bool {
+	return isInt(k) || isUint(k) || isFloat(k)
+}
+
+func isInt(k reflect.Kind) bool {
+	switch k { //nolint:exhaustive // only signed integers are matched.
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isUint(k reflect.Kind) bool {
+	switch k { //nolint:exhaustive // only unsigned integers are matched.
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// isLossy reports whether converting from to to may lose precision or range.
+func isLossy(from, to reflect.Type) bool {
+	fk, tk := from.Kind(), to.Kind()
+
+	switch {
+	case from == to, to == numberType:
+		return false
+	case isFloat(fk) && isFloat(tk):
+		return to.Size() < from.Size()
+	case isFloat(fk):
+		return true
+	case isFloat(tk):
+		// float32 has 24 bits of mantissa, float64 has 53.
+		return from.Size() >= to.Size()
+	case isInt(fk) == isInt(tk):
+		return to.Size() < from.Size()
+	case isUint(fk):
+		// unsigned fits into wider signed.
+		return to.Size() <= from.Size()
+	default:
+		// signed to unsigned loses negative values.
+		return true
+	}
+}
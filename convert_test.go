@@ -0,0 +1,610 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCoerceLossyConversion(t *testing.T) {
+	type result struct {
+		Narrow  int32     `triton:"narrow"`
+		Wide    int64     `triton:"wide"`
+		Float   float32   `triton:"float"`
+		Integer float64   `triton:"integer"`
+		Array   []int16   `triton:"array"`
+		Same    []float32 `triton:"same"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "narrow", datatype: INT64, shape: []int64{1}},
+			{name: "wide", datatype: INT32, shape: []int64{1}},
+			{name: "float", datatype: FLOAT64, shape: []int64{1}},
+			{name: "integer", datatype: INT32, shape: []int64{1}},
+			{name: "array", datatype: INT64, shape: []int64{1, 2}},
+			{name: "same", datatype: FLOAT32, shape: []int64{1, 1}},
+		},
+		raw: [][]byte{
+			int64Bytes(-3),
+			int32Bytes(4),
+			binary.LittleEndian.AppendUint64(nil, math.Float64bits(0.5)),
+			int32Bytes(6),
+			int64Bytes(7, 8),
+			binary.LittleEndian.AppendUint32(nil, math.Float32bits(9)),
+		},
+	}
+
+	var (
+		res   result
+		lossy []string
+	)
+
+	err := UnmarshalWithOptions(resp, &res, Options{Coerce: true, OnLossyConversion: func(c LossyConversion) error {
+		lossy = append(lossy, c.String())
+
+		return nil
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{Narrow: -3, Wide: 4, Float: 0.5, Integer: 6, Array: []int16{7, 8}, Same: []float32{9}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+
+	wantLossy := []string{"output narrow: int64 -> int32", "output float: float64 -> float32", "output array: int64 -> int16"}
+	if !reflect.DeepEqual(lossy, wantLossy) {
+		t.Errorf("got lossy conversions %q, want %q", lossy, wantLossy)
+	}
+}
+
+func TestCoerceErrors(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	tests := []struct {
+		name     string
+		datatype string
+		raw      []byte
+		dst      any
+		opts     Options
+		want     string
+	}{
+		{
+			name:     "without coerce",
+			datatype: INT64,
+			raw:      int64Bytes(1),
+			dst: &struct {
+				V int32 `triton:"v"`
+			}{},
+			want: "types doesn't match",
+		},
+		{
+			name:     "rejected",
+			datatype: INT64,
+			raw:      int64Bytes(1),
+			dst: &struct {
+				V int32 `triton:"v"`
+			}{},
+			opts: Options{Coerce: true, OnLossyConversion: func(LossyConversion) error { return errRejected }},
+			want: "lossy conversion output v: int64 -> int32: rejected",
+		},
+		{
+			name:     "negative into unsigned",
+			datatype: INT64,
+			raw:      int64Bytes(-1),
+			dst: &struct {
+				V uint32 `triton:"v"`
+			}{},
+			opts: Options{Coerce: true},
+			want: "overflows",
+		},
+		{
+			name:     "string",
+			datatype: STRING,
+			raw:      encodeStrings("1"),
+			dst: &struct {
+				V int32 `triton:"v"`
+			}{},
+			opts: Options{Coerce: true},
+			want: "types doesn't match",
+		},
+		{
+			name:     "short",
+			datatype: INT64,
+			raw:      int32Bytes(1),
+			dst: &struct {
+				V int32 `triton:"v"`
+			}{},
+			opts: Options{Coerce: true},
+			want: "unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "v", datatype: tt.datatype, shape: []int64{1}}},
+				raw:     [][]byte{tt.raw},
+			}
+
+			err := UnmarshalWithOptions(resp, tt.dst, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoerceSign(t *testing.T) {
+	type result struct {
+		ID     int64     `triton:"id"`
+		IDs    []int64   `triton:"ids"`
+		Matrix [][]int64 `triton:"matrix"`
+		Signed uint64    `triton:"signed"`
+		Raw    uint64    `triton:"raw"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   result
+		err    string
+	}{
+		{
+			name:   "uint64 scalar",
+			output: &testOutput{name: "id", datatype: UINT64, shape: []int64{1}},
+			raw:    int64Bytes(math.MaxInt64),
+			want:   result{ID: math.MaxInt64},
+		},
+		{
+			name:   "uint64 array",
+			output: &testOutput{name: "ids", datatype: UINT64, shape: []int64{1, 2}},
+			raw:    int64Bytes(1, 2),
+			want:   result{IDs: []int64{1, 2}},
+		},
+		{
+			name:   "int64 into uint64",
+			output: &testOutput{name: "signed", datatype: INT64, shape: []int64{1}},
+			raw:    int64Bytes(3),
+			want:   result{Signed: 3},
+		},
+		{
+			name:   "uint64 without coercion",
+			output: &testOutput{name: "raw", datatype: UINT64, shape: []int64{1}},
+			raw:    int64Bytes(-1),
+			want:   result{Raw: math.MaxUint64},
+		},
+		{
+			name:   "uint64 scalar above max int64",
+			output: &testOutput{name: "id", datatype: UINT64, shape: []int64{1}},
+			raw:    int64Bytes(math.MinInt64),
+			err:    "output id: value 9223372036854775808 overflows int64",
+		},
+		{
+			name:   "uint64 array above max int64",
+			output: &testOutput{name: "ids", datatype: UINT64, shape: []int64{1, 2}},
+			raw:    int64Bytes(1, -1),
+			err:    "output ids: value 18446744073709551615 overflows int64",
+		},
+		{
+			name:   "uint64 matrix above max int64",
+			output: &testOutput{name: "matrix", datatype: UINT64, shape: []int64{2, 1}},
+			raw:    int64Bytes(1, -1),
+			err:    "output matrix: value 18446744073709551615 overflows int64",
+		},
+		{
+			name:   "negative int64 into uint64",
+			output: &testOutput{name: "signed", datatype: INT64, shape: []int64{1}},
+			raw:    int64Bytes(-1),
+			err:    "output signed: negative value -1 overflows uint64",
+		},
+		{
+			name:   "short uint64",
+			output: &testOutput{name: "ids", datatype: UINT64, shape: []int64{1, 2}},
+			raw:    int64Bytes(1)[:7],
+			err:    "not a multiple of element size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res result
+
+			resp := &testResponse{outputs: []*testOutput{tt.output}, raw: [][]byte{tt.raw}}
+
+			err := UnmarshalWithOptions(resp, &res, Options{Coerce: true})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want %q", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONNumber(t *testing.T) {
+	type result struct {
+		ID     json.Number     `triton:"id"`
+		Big    json.Number     `triton:"big"`
+		Score  json.Number     `triton:"score"`
+		Probs  []json.Number   `triton:"probs"`
+		Half   json.Number     `triton:"half"`
+		Matrix [][]json.Number `triton:"matrix"`
+		Text   []json.Number   `triton:"text"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "id", datatype: INT64, shape: []int64{1}},
+			{name: "big", datatype: UINT64, shape: []int64{1}},
+			{name: "score", datatype: FLOAT32, shape: []int64{1}},
+			{name: "probs", datatype: FLOAT64, shape: []int64{1, 2}},
+			{name: "half", datatype: FLOAT16, shape: []int64{1}},
+			{name: "matrix", datatype: INT8, shape: []int64{2, 1}},
+			{name: "text", datatype: STRING, shape: []int64{1, 1}},
+		},
+		raw: [][]byte{
+			int64Bytes(-42),
+			int64Bytes(-1),
+			float32Bytes(0.1),
+			binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(nil, math.Float64bits(0.25)), math.Float64bits(1e21)),
+			halfBytes(0x3e00),
+			{1, 0xff},
+			encodeStrings("12.50"),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		ID:     "-42",
+		Big:    "18446744073709551615",
+		Score:  "0.1",
+		Probs:  []json.Number{"0.25", "1e+21"},
+		Half:   "1.5",
+		Matrix: [][]json.Number{{"1"}, {"-1"}},
+		Text:   []json.Number{"12.50"},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestJSONNumberErrors(t *testing.T) {
+	var res struct {
+		Number  json.Number   `triton:"number"`
+		Numbers []json.Number `triton:"numbers"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "bool",
+			output: &testOutput{name: "number", datatype: BOOL, shape: []int64{1}},
+			raw:    []byte{1},
+			want:   "types doesn't match",
+		},
+		{
+			name:   "matrix into slice",
+			output: &testOutput{name: "numbers", datatype: INT32, shape: []int64{2, 1}},
+			raw:    int32Bytes(1, 2),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "numbers", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 2)[:7],
+			want:   "not a multiple of element size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+type (
+	label     int32
+	labels    []label
+	token     string
+	flag      bool
+	sentiment float32
+)
+
+func TestNamedTypes(t *testing.T) {
+	type result struct {
+		Label     label     `triton:"label"`
+		Labels    []label   `triton:"labels"`
+		Matrix    [][]label `triton:"matrix"`
+		Slice     labels    `triton:"slice"`
+		Token     token     `triton:"token"`
+		Tokens    []token   `triton:"tokens"`
+		Flags     []flag    `triton:"flags"`
+		Sentiment sentiment `triton:"sentiment"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "label", datatype: INT32, shape: []int64{1}},
+			{name: "labels", datatype: INT32, shape: []int64{1, 2}},
+			{name: "matrix", datatype: INT32, shape: []int64{2, 1}},
+			{name: "slice", datatype: INT32, shape: []int64{1, 2}},
+			{name: "token", datatype: STRING, shape: []int64{1}},
+			{name: "tokens", datatype: STRING, shape: []int64{1, 2}},
+			{name: "flags", datatype: BOOL, shape: []int64{1, 2}},
+			{name: "sentiment", datatype: FLOAT32, shape: []int64{1}},
+		},
+		raw: [][]byte{
+			int32Bytes(1),
+			int32Bytes(2, 3),
+			int32Bytes(4, 5),
+			int32Bytes(6, 7),
+			encodeStrings("a"),
+			encodeStrings("b", "c"),
+			{1, 0},
+			float32Bytes(0.5),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Label:     1,
+		Labels:    []label{2, 3},
+		Matrix:    [][]label{{4}, {5}},
+		Slice:     labels{6, 7},
+		Token:     "a",
+		Tokens:    []token{"b", "c"},
+		Flags:     []flag{true, false},
+		Sentiment: 0.5,
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestNamedTypesErrors(t *testing.T) {
+	type wide int64
+
+	var res struct {
+		Label  label   `triton:"label"`
+		Labels []label `triton:"labels"`
+		Wide   wide    `triton:"wide"`
+		Token  token   `triton:"token"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "kind",
+			output: &testOutput{name: "wide", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "float into enum",
+			output: &testOutput{name: "label", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(1),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "number into string",
+			output: &testOutput{name: "token", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "matrix into slice",
+			output: &testOutput{name: "labels", datatype: INT32, shape: []int64{2, 1}},
+			raw:    int32Bytes(1, 2),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "labels", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 2)[:7],
+			want:   "not a multiple of element size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypeMismatch(t *testing.T) {
+	type result struct {
+		Int     int       `triton:"int"`
+		Ints    []int     `triton:"ints"`
+		Matrix  [][]int16 `triton:"matrix"`
+		Float   float64   `triton:"float"`
+		Index   uint8     `triton:"index"`
+		Rounded []int32   `triton:"rounded"`
+		Same    int32     `triton:"same"`
+		Named   []label   `triton:"named"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "int", datatype: INT32, shape: []int64{1}},
+			{name: "ints", datatype: INT64, shape: []int64{1, 2}},
+			{name: "matrix", datatype: INT32, shape: []int64{2, 1}},
+			{name: "float", datatype: FLOAT32, shape: []int64{1}},
+			{name: "index", datatype: INT64, shape: []int64{1}},
+			{name: "rounded", datatype: FLOAT64, shape: []int64{1, 2}},
+			{name: "same", datatype: INT32, shape: []int64{1}},
+			{name: "named", datatype: INT32, shape: []int64{1, 1}},
+		},
+		raw: [][]byte{
+			int32Bytes(-7),
+			int64Bytes(math.MinInt32, math.MaxInt32),
+			int32Bytes(math.MaxInt16, math.MinInt16),
+			float32Bytes(0.5),
+			int64Bytes(255),
+			float64Bytes(2.9, -2.9),
+			int32Bytes(1),
+			int32Bytes(4),
+		},
+	}
+
+	var (
+		res    result
+		called []string
+	)
+
+	opts := Options{OnTypeMismatch: func(c LossyConversion) { called = append(called, c.String()) }}
+	if err := UnmarshalWithOptions(resp, &res, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Int:     -7,
+		Ints:    []int{math.MinInt32, math.MaxInt32},
+		Matrix:  [][]int16{{math.MaxInt16}, {math.MinInt16}},
+		Float:   0.5,
+		Index:   255,
+		Rounded: []int32{2, -2},
+		Same:    1,
+		Named:   []label{4},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+
+	// identical and named types aren't mismatches.
+	wantCalled := []string{
+		"output int: int32 -> int",
+		"output ints: int64 -> int",
+		"output matrix: int32 -> int16",
+		"output float: float32 -> float64",
+		"output index: int64 -> uint8",
+		"output rounded: float64 -> int32",
+	}
+	if !reflect.DeepEqual(called, wantCalled) {
+		t.Errorf("got calls %q, want %q", called, wantCalled)
+	}
+}
+
+func TestTypeMismatchErrors(t *testing.T) {
+	var res struct {
+		Int8   int8     `triton:"int8"`
+		Uint8  []uint8  `triton:"uint8"`
+		Int32  int32    `triton:"int32"`
+		Uint64 uint64   `triton:"uint64"`
+		Float  float32  `triton:"float"`
+		String string   `triton:"string"`
+		Ints   [][]int8 `triton:"ints"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "int overflow",
+			output: &testOutput{name: "int8", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(128),
+			want:   "output int8: value 128 overflows int8",
+		},
+		{
+			name:   "uint overflow",
+			output: &testOutput{name: "uint8", datatype: UINT16, shape: []int64{1, 2}},
+			raw:    []byte{1, 0, 0, 1},
+			want:   "output uint8: value 256 overflows uint8",
+		},
+		{
+			name:   "negative into unsigned",
+			output: &testOutput{name: "uint64", datatype: INT8, shape: []int64{1}},
+			raw:    []byte{0xff},
+			want:   "output uint64",
+		},
+		{
+			name:   "float overflow",
+			output: &testOutput{name: "float", datatype: FLOAT64, shape: []int64{1}},
+			raw:    float64Bytes(1e300),
+			want:   "output float: value 1e+300 overflows float32",
+		},
+		{
+			name:   "float into int overflow",
+			output: &testOutput{name: "int32", datatype: FLOAT64, shape: []int64{1}},
+			raw:    float64Bytes(1 << 31),
+			want:   "output int32: value 2.147483648e+09 overflows int32",
+		},
+		{
+			name:   "NaN into int",
+			output: &testOutput{name: "int32", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(float32(math.NaN())),
+			want:   "output int32: value NaN overflows int32",
+		},
+		{
+			name:   "matrix element overflow",
+			output: &testOutput{name: "ints", datatype: INT16, shape: []int64{2, 1}},
+			raw:    int16Bytes(1, -129),
+			want:   "output ints: value -129 overflows int8",
+		},
+		{
+			name:   "string",
+			output: &testOutput{name: "string", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "int32", datatype: INT64, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{OnTypeMismatch: func(LossyConversion) {}})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
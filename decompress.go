@@ -0,0 +1,83 @@
+package tritonparser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+//nolint:gochecknoglobals // magic bytes are constant.
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+func decompress(b []byte, opts *Options) ([]byte, error) {
+	switch opts.Decompress {
+	case CompressionNone:
+		return b, nil
+	case CompressionAuto:
+		switch {
+		case bytes.HasPrefix(b, gzipMagic):
+			return gunzip(b, opts.MaxBytes)
+		case bytes.HasPrefix(b, zstdMagic):
+			return unzstd(b, opts)
+		default:
+			return b, nil
+		}
+	case CompressionGzip:
+		return gunzip(b, opts.MaxBytes)
+	case CompressionZstd:
+		return unzstd(b, opts)
+	default:
+		return nil, fmt.Errorf("unknown compression: %d", opts.Decompress)
+	}
+}
+
+// gunzip inflates b. Contents larger than limit, if positive, are rejected.
+func gunzip(b []byte, limit int) ([]byte, error) {
+	if len(b) == 0 {
+		return b, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("gzip reader failed: %w", err)
+	}
+	defer r.Close()
+
+	var src io.Reader = r
+	if limit > 0 {
+		src = io.LimitReader(r, int64(limit)+1)
+	}
+
+	res, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("gzip read failed: %w", err)
+	}
+
+	if limit > 0 && len(res) > limit {
+		return nil, fmt.Errorf("inflated contents exceed %d bytes", limit)
+	}
+
+	return res, nil
+}
+
+func unzstd(b []byte, opts *Options) ([]byte, error) {
+	if len(b) == 0 {
+		return b, nil
+	}
+
+	if opts.ZstdDecoder == nil {
+		return nil, errors.New("zstd compressed contents require ZstdDecoder")
+	}
+
+	res, err := opts.ZstdDecoder(b)
+	if err != nil {
+		return nil, fmt.Errorf("zstd decode failed: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,124 @@
+package tritonparser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// fakeZstd "decodes" contents prefixed with zstd magic by stripping it.
+func fakeZstd(b []byte) ([]byte, error) {
+	return bytes.TrimPrefix(b, zstdMagic), nil
+}
+
+func TestDecompress(t *testing.T) {
+	plain := int32Bytes(1, 2, 3)
+	zstd := append(append([]byte{}, zstdMagic...), plain...)
+
+	tests := []struct {
+		name string
+		raw  []byte
+		opts Options
+	}{
+		{name: "none", raw: plain},
+		{name: "gzip", raw: gzipBytes(t, plain), opts: Options{Decompress: CompressionGzip}},
+		{name: "zstd", raw: zstd, opts: Options{Decompress: CompressionZstd, ZstdDecoder: fakeZstd}},
+		{name: "auto gzip", raw: gzipBytes(t, plain), opts: Options{Decompress: CompressionAuto}},
+		{name: "auto zstd", raw: zstd, opts: Options{Decompress: CompressionAuto, ZstdDecoder: fakeZstd}},
+		{name: "auto plain", raw: plain, opts: Options{Decompress: CompressionAuto}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res struct {
+				Values []int32 `triton:"values"`
+			}
+
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "values", datatype: INT32, shape: []int64{1, 3}}},
+				raw:     [][]byte{tt.raw},
+			}
+
+			if err := UnmarshalWithOptions(resp, &res, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(res.Values) != 3 || res.Values[0] != 1 || res.Values[2] != 3 {
+				t.Errorf("got %v, want [1 2 3]", res.Values)
+			}
+		})
+	}
+}
+
+func TestDecompressErrors(t *testing.T) {
+	errCorrupt := errors.New("corrupt")
+
+	tests := []struct {
+		name string
+		raw  []byte
+		opts Options
+		want string
+	}{
+		{name: "not gzip", raw: int32Bytes(1, 2, 3), opts: Options{Decompress: CompressionGzip}, want: "gzip reader failed"},
+		{
+			name: "truncated gzip",
+			raw:  gzipBytes(t, int32Bytes(1, 2, 3))[:12],
+			opts: Options{Decompress: CompressionGzip},
+			want: "gzip",
+		},
+		{
+			name: "inflated length",
+			raw:  gzipBytes(t, []byte{1, 2, 3, 4, 5}),
+			opts: Options{Decompress: CompressionAuto},
+			want: "values",
+		},
+		{name: "no zstd decoder", raw: zstdMagic, opts: Options{Decompress: CompressionZstd}, want: "require ZstdDecoder"},
+		{
+			name: "zstd decoder",
+			raw:  zstdMagic,
+			opts: Options{
+				Decompress:  CompressionZstd,
+				ZstdDecoder: func([]byte) ([]byte, error) { return nil, errCorrupt },
+			},
+			want: "corrupt",
+		},
+		{name: "unknown", raw: int32Bytes(1, 2, 3), opts: Options{Decompress: Compression(42)}, want: "unknown compression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res struct {
+				Values []int32 `triton:"values"`
+			}
+
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "values", datatype: INT32, shape: []int64{1, 3}}},
+				raw:     [][]byte{tt.raw},
+			}
+
+			err := UnmarshalWithOptions(resp, &res, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
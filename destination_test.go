@@ -0,0 +1,68 @@
+package tritonparser
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestUnsupportedDestination(t *testing.T) {
+	var (
+		nilStruct *struct{}
+		m         map[string]int32
+		ch        chan int32
+		fn        func()
+		iface     any
+		ptr       *int32
+		unsafePtr unsafe.Pointer
+		complexV  complex64
+		uintptrV  uintptr
+	)
+
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{name: "nil", v: nil, want: "v must be pointer, got invalid"},
+		{name: "struct", v: struct{}{}, want: "v must be pointer, got struct"},
+		{name: "slice", v: []int32{}, want: "v must be pointer, got slice"},
+		{name: "nil pointer", v: nilStruct, want: "v must be non-nil pointer"},
+		{name: "map", v: &m, want: "unsupported destination kind: map"},
+		{name: "chan", v: &ch, want: "unsupported destination kind: chan"},
+		{name: "func", v: &fn, want: "unsupported destination kind: func"},
+		{name: "interface", v: &iface, want: "unsupported destination kind: interface"},
+		{name: "pointer", v: &ptr, want: "unsupported destination kind: ptr"},
+		{name: "unsafe pointer", v: &unsafePtr, want: "unsupported destination kind: unsafe.Pointer"},
+		{name: "complex", v: &complexV, want: "unsupported destination kind: complex64"},
+		{name: "uintptr", v: &uintptrV, want: "unsupported destination kind: uintptr"},
+	}
+
+	resp := &testResponse{outputs: []*testOutput{{name: "v", datatype: INT32, shape: []int64{1}}}, raw: [][]byte{int32Bytes(1)}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Unmarshal(resp, tt.v); err == nil || err.Error() != tt.want {
+				t.Errorf("Unmarshal error %v, want %q", err, tt.want)
+			}
+
+			var b Binding[*testOutput]
+			if err := b.Reset(tt.v); err == nil || err.Error() != tt.want {
+				t.Errorf("Reset error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupportedDestination(t *testing.T) {
+	resp := &testResponse{outputs: []*testOutput{{name: "v", datatype: INT32, shape: []int64{1}}}, raw: [][]byte{int32Bytes(7)}}
+
+	var scalar int32
+	if err := Unmarshal(resp, &scalar); err != nil || scalar != 7 {
+		t.Errorf("got %d, %v, want 7", scalar, err)
+	}
+
+	var b Binding[*testOutput]
+	if err := b.Reset(&scalar); err == nil || err.Error() != "v must be pointer to structure, got pointer to int32" {
+		t.Errorf("Reset error %v, want pointer to structure error", err)
+	}
+}
@@ -0,0 +1,176 @@
+package tritonparser
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type emptyStringsResult struct {
+	Label  string     `triton:"label"`
+	Labels []string   `triton:"labels"`
+	Matrix [][]string `triton:"matrix"`
+}
+
+func TestEmptyStrings(t *testing.T) {
+	outputs := []*testOutput{
+		{name: "label", datatype: STRING, shape: []int64{1}},
+		{name: "labels", datatype: STRING, shape: []int64{1, 3}},
+		{name: "matrix", datatype: STRING, shape: []int64{2, 2}},
+	}
+	prev := emptyStringsResult{Label: "x", Labels: []string{"x"}, Matrix: [][]string{{"x"}}}
+
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		want    emptyStringsResult
+	}{
+		{
+			name:    "empty contents",
+			outputs: outputs,
+			raw:     [][]byte{{}, {}, {}},
+			want:    emptyStringsResult{Labels: []string{"", "", ""}, Matrix: [][]string{{"", ""}, {"", ""}}},
+		},
+		{
+			name:    "empty strings",
+			outputs: outputs,
+			raw:     [][]byte{encodeStrings(""), encodeStrings("", "", ""), encodeStrings("", "", "", "")},
+			want:    emptyStringsResult{Labels: []string{"", "", ""}, Matrix: [][]string{{"", ""}, {"", ""}}},
+		},
+		{
+			name:    "missing outputs",
+			outputs: nil,
+			want:    prev,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := prev
+			if err := Unmarshal(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmptyStringsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+	}{
+		{name: "scalar prefix", output: &testOutput{name: "label", datatype: STRING, shape: []int64{1}}, raw: []byte{0, 0}},
+		{name: "array count", output: &testOutput{name: "labels", datatype: STRING, shape: []int64{1, 3}}, raw: encodeStrings("", "")},
+		{name: "matrix count", output: &testOutput{name: "matrix", datatype: STRING, shape: []int64{2, 2}}, raw: encodeStrings("")},
+		{name: "field type", output: &testOutput{name: "label", datatype: STRING, shape: []int64{1, 2}}, raw: []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res emptyStringsResult
+			if err := unmarshalOutput(&res, tt.output, tt.raw, Options{}); err == nil {
+				t.Fatalf("got nil error, result %+v", res)
+			}
+		})
+	}
+}
+
+func TestNilRawContents(t *testing.T) {
+	type result struct {
+		Scalar   int32         `triton:"scalar"`
+		Array    []float32     `triton:"array"`
+		Matrix   [][]int64     `triton:"matrix"`
+		Label    string        `triton:"label"`
+		Nullable sql.NullInt32 `triton:"nullable"`
+		Huge     []uint8       `triton:"huge"`
+		Empty    []float32     `triton:"empty"`
+		Labels   []string      `triton:"labels"`
+	}
+
+	prev := result{
+		Scalar:   1,
+		Array:    []float32{1},
+		Matrix:   [][]int64{{1}},
+		Label:    "x",
+		Nullable: sql.NullInt32{Int32: 1, Valid: true},
+		Huge:     []uint8{1},
+		Empty:    []float32{1},
+		Labels:   []string{"x"},
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "scalar", datatype: INT32, shape: []int64{1}},
+			{name: "array", datatype: FLOAT32, shape: []int64{1, 2}},
+			{name: "matrix", datatype: INT64, shape: []int64{2, 2}},
+			{name: "label", datatype: STRING, shape: []int64{1}},
+			{name: "nullable", datatype: INT32, shape: []int64{1}},
+			{name: "huge", datatype: UINT8, shape: []int64{1 << 32, 1 << 32}},
+			{name: "empty", datatype: FLOAT32, shape: []int64{1, 0}},
+			{name: "labels", datatype: STRING, shape: []int64{1, 0}},
+		},
+		raw: [][]byte{nil, nil, nil, nil, nil, nil, nil, nil},
+	}
+
+	res := prev
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	// outputs without elements are decoded as usual.
+	want := result{Empty: []float32{}, Labels: []string{}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestNilRawContentsErrors(t *testing.T) {
+	var res struct {
+		Scalar int32     `triton:"scalar,required"`
+		Array  []float32 `triton:"array,required"`
+		Label  string    `triton:"label,required"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		want   string
+	}{
+		{
+			name:   "scalar",
+			output: &testOutput{name: "scalar", datatype: INT32, shape: []int64{1}},
+			want:   "required output scalar has no raw contents",
+		},
+		{
+			name:   "array",
+			output: &testOutput{name: "array", datatype: FLOAT32, shape: []int64{1, 3}},
+			want:   "required output array has no raw contents",
+		},
+		{
+			name:   "string",
+			output: &testOutput{name: "label", datatype: STRING, shape: []int64{1}},
+			want:   "required output label has no raw contents",
+		},
+		{
+			name:   "negative dimension",
+			output: &testOutput{name: "array", datatype: FLOAT32, shape: []int64{1, -1}},
+			want:   "invalid shape: [1 -1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal(&testResponse{outputs: []*testOutput{tt.output}, raw: [][]byte{nil}}, &res)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
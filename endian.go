@@ -0,0 +1,55 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// detectByteOrder returns byte order of response detected from Options.ByteOrderSentinel output,
+// or nil if it's not set or response has no such output.
+func detectByteOrder[T TritonModelInferResponseOutputs](outputs []T, rawBytes [][]byte, opts *Options) (binary.ByteOrder, error) {
+	if opts.ByteOrderSentinel == "" {
+		return nil, nil
+	}
+
+	magic := uint32(opts.ByteOrderMagic)
+	if bits.ReverseBytes32(magic) == magic {
+		return nil, fmt.Errorf("byte order magic %#x reads the same in both byte orders", magic)
+	}
+
+	for i, o := range outputs {
+		if o.GetName() != opts.ByteOrderSentinel {
+			continue
+		}
+
+		if o.GetDatatype() != INT32 {
+			return nil, fmt.Errorf("output %s: types doesn't match exp: %s got: %s", o.GetName(), INT32, o.GetDatatype())
+		}
+
+		raw, err := rawContents(o, i, rawBytes, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := decompress(raw, opts)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", o.GetName(), err)
+		}
+
+		if len(b) != 4 {
+			return nil, fmt.Errorf("output %s: byte order sentinel must be a single %s element, got %d bytes", o.GetName(), INT32, len(b))
+		}
+
+		switch magic {
+		case binary.LittleEndian.Uint32(b):
+			return binary.LittleEndian, nil
+		case binary.BigEndian.Uint32(b):
+			return binary.BigEndian, nil
+		default:
+			return nil, fmt.Errorf("output %s: byte order magic %#x doesn't match in either byte order", o.GetName(), magic)
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,152 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testMagic = 0x01020304
+
+type endianResult struct {
+	Magic  int32    `triton:"magic"`
+	Value  int32    `triton:"value"`
+	Values []uint16 `triton:"values"`
+	Little int32    `triton:"little,littleendian"`
+	Words  []string `triton:"words"`
+}
+
+func endianResponse(order binary.AppendByteOrder) *testResponse {
+	return &testResponse{
+		outputs: []*testOutput{
+			{name: "magic", datatype: INT32, shape: []int64{1}},
+			{name: "value", datatype: INT32, shape: []int64{1}},
+			{name: "values", datatype: UINT16, shape: []int64{1, 2}},
+			{name: "little", datatype: INT32, shape: []int64{1}},
+			{name: "words", datatype: STRING, shape: []int64{1, 1}},
+		},
+		raw: [][]byte{
+			order.AppendUint32(nil, testMagic),
+			order.AppendUint32(nil, 7),
+			order.AppendUint16(order.AppendUint16(nil, 1), 2),
+			binary.LittleEndian.AppendUint32(nil, 9),
+			append(order.AppendUint32(nil, 2), "ab"...),
+		},
+	}
+}
+
+func TestByteOrderSentinel(t *testing.T) {
+	want := endianResult{Magic: testMagic, Value: 7, Values: []uint16{1, 2}, Little: 9, Words: []string{"ab"}}
+
+	tests := []struct {
+		name  string
+		order binary.AppendByteOrder
+		opts  Options
+	}{
+		{name: "little endian", order: binary.LittleEndian},
+		{name: "big endian", order: binary.BigEndian},
+		{name: "overrides byte order", order: binary.LittleEndian, opts: Options{ByteOrder: binary.BigEndian}},
+		{
+			name:  "overrides byte order per output",
+			order: binary.BigEndian,
+			opts:  Options{ByteOrderFor: func(string) binary.ByteOrder { return binary.LittleEndian }},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.ByteOrderSentinel, opts.ByteOrderMagic = "magic", testMagic
+
+			var res endianResult
+			if err := UnmarshalWithOptions(endianResponse(tt.order), &res, opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, want) {
+				t.Errorf("got %+v, want %+v", res, want)
+			}
+		})
+	}
+}
+
+func TestByteOrderSentinelAbsent(t *testing.T) {
+	resp := endianResponse(binary.BigEndian)
+	resp.outputs, resp.raw = resp.outputs[1:], resp.raw[1:]
+
+	var res endianResult
+
+	opts := Options{ByteOrder: binary.BigEndian, ByteOrderSentinel: "magic", ByteOrderMagic: testMagic}
+	if err := UnmarshalWithOptions(resp, &res, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	want := endianResult{Value: 7, Values: []uint16{1, 2}, Little: 9, Words: []string{"ab"}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestByteOrderSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		magic  int32
+		want   string
+	}{
+		{
+			name:   "palindrome magic",
+			output: &testOutput{name: "magic", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(0x01000001),
+			magic:  0x01000001,
+			want:   "byte order magic 0x1000001 reads the same in both byte orders",
+		},
+		{
+			name:   "zero magic",
+			output: &testOutput{name: "magic", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(0),
+			want:   "byte order magic 0x0 reads the same in both byte orders",
+		},
+		{
+			name:   "datatype",
+			output: &testOutput{name: "magic", datatype: UINT32, shape: []int64{1}},
+			raw:    int32Bytes(testMagic),
+			magic:  testMagic,
+			want:   "output magic: types doesn't match exp: INT32 got: UINT32",
+		},
+		{
+			name:   "mismatch",
+			output: &testOutput{name: "magic", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(0x04030201 + 1),
+			magic:  testMagic,
+			want:   "output magic: byte order magic 0x1020304 doesn't match in either byte order",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "magic", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(testMagic)[:3],
+			magic:  testMagic,
+			want:   "output magic: byte order sentinel must be a single INT32 element, got 3 bytes",
+		},
+		{
+			name:   "several elements",
+			output: &testOutput{name: "magic", datatype: INT32, shape: []int64{2}},
+			raw:    int32Bytes(testMagic, testMagic),
+			magic:  testMagic,
+			want:   "output magic: byte order sentinel must be a single INT32 element, got 8 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res endianResult
+
+			opts := Options{ByteOrderSentinel: "magic", ByteOrderMagic: tt.magic}
+			if err := unmarshalOutput(&res, tt.output, tt.raw, opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
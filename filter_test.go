@@ -0,0 +1,79 @@
+package tritonparser
+
+import "testing"
+
+func TestOnlySkip(t *testing.T) {
+	type result struct {
+		A int32 `triton:"a"`
+		B int32 `triton:"b"`
+		C int32 `triton:"c"`
+	}
+
+	// b is malformed, so decoding fails unless it's filtered out.
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "a", datatype: INT32, shape: []int64{1}},
+			{name: "b", datatype: INT32, shape: []int64{1}},
+			{name: "c", datatype: INT32, shape: []int64{1}},
+		},
+		raw: [][]byte{int32Bytes(1), {2}, int32Bytes(3)},
+	}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		want    result
+		wantErr bool
+	}{
+		{name: "none", opts: Options{}, wantErr: true},
+		{name: "only", opts: Options{Only: []string{"a", "c"}}, want: result{A: 1, B: -1, C: 3}},
+		{name: "only one", opts: Options{Only: []string{"c"}}, want: result{A: -1, B: -1, C: 3}},
+		{name: "only unknown", opts: Options{Only: []string{"x"}}, want: result{A: -1, B: -1, C: -1}},
+		{name: "skip", opts: Options{Skip: []string{"b"}}, want: result{A: 1, B: -1, C: 3}},
+		{name: "only and skip", opts: Options{Only: []string{"a", "b"}, Skip: []string{"b"}}, want: result{A: 1, B: -1, C: -1}},
+		{name: "only malformed", opts: Options{Only: []string{"b"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := result{A: -1, B: -1, C: -1}
+
+			err := UnmarshalWithOptions(resp, &res, tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got nil error, result %+v", res)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res != tt.want {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkipRequired(t *testing.T) {
+	var res struct {
+		A int32 `triton:"a,required"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{{name: "a", datatype: INT32, shape: []int64{1}}},
+		raw:     [][]byte{int32Bytes(1)},
+	}
+
+	// skipped output is present in response, so requirement is met.
+	if err := UnmarshalWithOptions(resp, &res, Options{Skip: []string{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.A != 0 {
+		t.Errorf("got %d, want skipped output left zero", res.A)
+	}
+}
@@ -0,0 +1,183 @@
+package tritonparser
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Float16 is IEEE 754 half-precision number in its raw representation.
+// FLOAT16 outputs decoded into []Float16 fields aren't widened.
+type Float16 uint16
+
+// Float32 returns f widened to float32.
+func (f Float16) Float32() float32 {
+	return halfToFloat32(uint16(f))
+}
+
+// String returns f formatted as float32.
+func (f Float16) String() string {
+	return strconv.FormatFloat(float64(f.Float32()), 'g', -1, 32)
+}
+
+// halfToFloat32 converts IEEE 754 half-precision bits to float32.
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h) & 0x3ff
+
+	switch {
+	case exp == 0 && mant == 0:
+		return math.Float32frombits(sign)
+	case exp == 0:
+		// subnormal half is a normal float32.
+		exp = 127 - 15 + 1
+		for mant&0x400 == 0 {
+			mant <<= 1
+			exp--
+		}
+
+		mant &= 0x3ff
+	case exp == 0x1f:
+		// inf or NaN.
+		exp = 0xff
+	default:
+		exp += 127 - 15
+	}
+
+	return math.Float32frombits(sign | exp<<23 | mant<<13)
+}
+
+// float32ToHalf converts f to IEEE 754 half-precision bits, rounding half to even.
+// Values out of range of half become infinities.
+func float32ToHalf(f float32) uint16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int(b>>23&0xff) - 127 + 15
+	mant := b & 0x7fffff
+
+	switch {
+	case b&0x7fffffff > 0x7f800000:
+		// NaN.
+		return sign | 0x7e00
+	case exp >= 0x1f:
+		return sign | 0x7c00
+	case exp <= 0:
+		// subnormal half or zero.
+		if exp < -10 {
+			return sign
+		}
+
+		mant |= 0x800000
+		shift := uint(14 - exp)
+
+		return sign | uint16(roundHalfEven(mant, shift))
+	default:
+		// carry of rounding into exponent correctly yields the next power of two or infinity.
+		return sign | uint16(uint32(exp)<<10+roundHalfEven(mant, 13))
+	}
+}
+
+// roundHalfEven returns v shifted right by shift bits, rounding half to even.
+func roundHalfEven(v uint32, shift uint) uint32 {
+	res := v >> shift
+	rem, half := v&(1<<shift-1), uint32(1)<<(shift-1)
+
+	if rem > half || rem == half && res&1 == 1 {
+		res++
+	}
+
+	return res
+}
+
+// unmarshalFloat16Array decodes FLOAT16 array into []float32 or []float64 field.
+func unmarshalFloat16Array(
+	fieldMap map[string]reflect.Value,
+	resp TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+) error {
+	arrLen := resp.GetShape()[1]
+	fieldType := fieldMap[resp.GetName()].Type()
+	wide, raw := fieldType == reflect.TypeOf([]float64{}), fieldType == reflect.TypeOf([]Float16{})
+	if !wide && !raw {
+		if err := checkType(fieldMap[resp.GetName()], reflect.TypeOf([]float32{}), opts); err != nil {
+			return err
+		}
+	}
+
+	halfs, err := bytesToArray(rawBytes, make([]uint16, 0, arrLen), opts.byteOrder(resp.GetName()))
+	if err != nil {
+		return fmt.Errorf("output %s: %w", resp.GetName(), err)
+	}
+
+	var arr any
+	switch {
+	case raw:
+		res := make([]Float16, len(halfs))
+		for i, h := range halfs {
+			res[i] = Float16(h)
+		}
+
+		arr = res
+	case wide:
+		arr = convertHalfs[float64](halfs)
+	default:
+		arr = convertHalfs[float32](halfs)
+	}
+
+	if v, ok := fieldMap[resp.GetName()]; ok {
+		return setValue(v, reflect.ValueOf(arr), resp.GetName(), opts)
+	}
+
+	return nil
+}
+
+// unmarshalFloat16Value decodes single FLOAT16 value into float32, float64 or Float16 field.
+func unmarshalFloat16Value(
+	fieldMap map[string]reflect.Value,
+	resp TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+) error {
+	if len(rawBytes) < 2 {
+		return fmt.Errorf("binary read failed: %w", io.ErrUnexpectedEOF)
+	}
+
+	if len(rawBytes) != 2 {
+		return fmt.Errorf("output %s: %w", resp.GetName(), trailingBytesError(len(rawBytes)-2, 1))
+	}
+
+	h := opts.byteOrder(resp.GetName()).Uint16(rawBytes)
+
+	var val any
+	switch fieldMap[resp.GetName()].Type() {
+	case reflect.TypeFor[Float16]():
+		val = Float16(h)
+	case reflect.TypeFor[float64]():
+		val = float64(halfToFloat32(h))
+	default:
+		if err := checkType(fieldMap[resp.GetName()], reflect.TypeFor[float32](), opts); err != nil {
+			return err
+		}
+
+		val = halfToFloat32(h)
+	}
+
+	if v, ok := fieldMap[resp.GetName()]; ok {
+		return setValue(v, reflect.ValueOf(val), resp.GetName(), opts)
+	}
+
+	return nil
+}
+
+func convertHalfs[T float32 | float64](halfs []uint16) []T {
+	arr := make([]T, len(halfs))
+	for i, h := range halfs {
+		arr[i] = T(halfToFloat32(h))
+	}
+
+	return arr
+}
@@ -0,0 +1,316 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func halfBytes(hs ...uint16) []byte {
+	b := make([]byte, 0, 2*len(hs))
+	for _, h := range hs {
+		b = binary.LittleEndian.AppendUint16(b, h)
+	}
+
+	return b
+}
+
+func TestFloat16Array(t *testing.T) {
+	resp := &testResponse{
+		outputs: []*testOutput{{name: "v", datatype: FLOAT16, shape: []int64{1, 6}}},
+		raw:     [][]byte{halfBytes(0x3c00, 0xc000, 0x3800, 0x0001, 0x7c00, 0x8000)},
+	}
+
+	want := []float64{1, -2, 0.5, math.Ldexp(1, -24), math.Inf(1), math.Copysign(0, -1)}
+
+	var wide struct {
+		V []float64 `triton:"v"`
+	}
+
+	if err := Unmarshal(resp, &wide); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(wide.V, want) || !math.Signbit(wide.V[5]) {
+		t.Errorf("got %v, want %v", wide.V, want)
+	}
+
+	var narrow struct {
+		V []float32 `triton:"v"`
+	}
+
+	if err := Unmarshal(resp, &narrow); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, f := range narrow.V {
+		if float64(f) != want[i] {
+			t.Errorf("got %v, want %v", narrow.V, want)
+
+			break
+		}
+	}
+}
+
+func TestFloat16NaN(t *testing.T) {
+	if f := halfToFloat32(0x7e00); !math.IsNaN(float64(f)) {
+		t.Errorf("got %v, want NaN", f)
+	}
+}
+
+func TestFloat16ArrayErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape []int64
+		raw   []byte
+		dst   any
+	}{
+		{name: "odd length", shape: []int64{1, 2}, raw: []byte{0, 0x3c, 0}, dst: &struct {
+			V []float64 `triton:"v"`
+		}{}},
+		{name: "field type", shape: []int64{1, 1}, raw: halfBytes(0x3c00), dst: &struct {
+			V []int32 `triton:"v"`
+		}{}},
+		{name: "scalar field", shape: []int64{1, 1}, raw: halfBytes(0x3c00), dst: &struct {
+			V float64 `triton:"v"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "v", datatype: FLOAT16, shape: tt.shape}},
+				raw:     [][]byte{tt.raw},
+			}
+
+			if err := Unmarshal(resp, tt.dst); err == nil {
+				t.Fatal("got nil error")
+			}
+		})
+	}
+}
+
+func TestFloat16Type(t *testing.T) {
+	type result struct {
+		Vector []Float16 `triton:"vector"`
+		Scalar []Float16 `triton:"scalar"`
+		Empty  []Float16 `triton:"empty"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "vector", datatype: FLOAT16, shape: []int64{1, 4}},
+			{name: "scalar", datatype: FLOAT16, shape: []int64{2}},
+			{name: "empty", datatype: FLOAT16, shape: []int64{1, 0}},
+		},
+		raw: [][]byte{halfBytes(0x3c00, 0xc000, 0x7e01, 0x0001), halfBytes(0x3800, 0x7c00), {}},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	// NaN payload is kept, as values aren't widened.
+	want := result{
+		Vector: []Float16{0x3c00, 0xc000, 0x7e01, 0x0001},
+		Scalar: []Float16{0x3800, 0x7c00},
+		Empty:  []Float16{},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %#v, want %#v", res, want)
+	}
+
+	tests := []struct {
+		f    Float16
+		want float32
+		str  string
+	}{
+		{f: 0x3c00, want: 1, str: "1"},
+		{f: 0xc000, want: -2, str: "-2"},
+		{f: 0x3555, want: halfToFloat32(0x3555), str: "0.33325195"},
+		{f: 0x0001, want: float32(math.Ldexp(1, -24)), str: "5.9604645e-08"},
+		{f: 0x7c00, want: float32(math.Inf(1)), str: "+Inf"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.f.Float32(); got != tt.want {
+			t.Errorf("Float16(%#04x).Float32() = %v, want %v", uint16(tt.f), got, tt.want)
+		}
+
+		if got := tt.f.String(); got != tt.str {
+			t.Errorf("Float16(%#04x).String() = %q, want %q", uint16(tt.f), got, tt.str)
+		}
+	}
+
+	if f := Float16(0x7e00); !math.IsNaN(float64(f.Float32())) || f.String() != "NaN" {
+		t.Errorf("got %v, want NaN", f)
+	}
+}
+
+func TestFloat16TypeErrors(t *testing.T) {
+	var res struct {
+		V []Float16 `triton:"v"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "odd length",
+			output: &testOutput{name: "v", datatype: FLOAT16, shape: []int64{1, 2}},
+			raw:    []byte{0, 0x3c, 0},
+			want:   "v",
+		},
+		{
+			name:   "float32 datatype",
+			output: &testOutput{name: "v", datatype: FLOAT32, shape: []int64{1, 1}},
+			raw:    float32Bytes(1),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "matrix",
+			output: &testOutput{name: "v", datatype: FLOAT16, shape: []int64{2, 1}},
+			raw:    halfBytes(0x3c00, 0x3c00),
+			want:   "FP16 not yet supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFloat16Value(t *testing.T) {
+	type result struct {
+		Score   float32 `triton:"score"`
+		Double  float64 `triton:"double"`
+		Half    Float16 `triton:"half"`
+		Rank0   float32 `triton:"rank0"`
+		Integer int32   `triton:"integer"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		opts   Options
+		want   result
+	}{
+		{
+			name:   "float32",
+			output: &testOutput{name: "score", datatype: FLOAT16, shape: []int64{1}},
+			raw:    halfBytes(0x3800),
+			want:   result{Score: 0.5},
+		},
+		{
+			name:   "float64",
+			output: &testOutput{name: "double", datatype: FLOAT16, shape: []int64{1}},
+			raw:    halfBytes(0xc000),
+			want:   result{Double: -2},
+		},
+		{
+			name:   "Float16",
+			output: &testOutput{name: "half", datatype: FLOAT16, shape: []int64{1}},
+			raw:    halfBytes(0x7e01),
+			want:   result{Half: 0x7e01},
+		},
+		{
+			name:   "rank 0",
+			output: &testOutput{name: "rank0", datatype: FLOAT16},
+			raw:    halfBytes(0x7c00),
+			want:   result{Rank0: float32(math.Inf(1))},
+		},
+		{
+			name:   "big endian",
+			output: &testOutput{name: "score", datatype: FLOAT16, shape: []int64{1}},
+			raw:    []byte{0x3c, 0x00},
+			opts:   Options{ByteOrder: binary.BigEndian},
+			want:   result{Score: 1},
+		},
+		{
+			name:   "coerced",
+			output: &testOutput{name: "integer", datatype: FLOAT16, shape: []int64{1}},
+			raw:    halfBytes(0x4200),
+			opts:   Options{Coerce: true},
+			want:   result{Integer: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res result
+			if err := unmarshalOutput(&res, tt.output, tt.raw, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestFloat16ValueErrors(t *testing.T) {
+	var res struct {
+		Score   float32 `triton:"score"`
+		Integer int32   `triton:"integer"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "empty",
+			output: &testOutput{name: "score", datatype: FLOAT16, shape: []int64{1}},
+			raw:    []byte{},
+			want:   "binary read failed: unexpected EOF",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "score", datatype: FLOAT16, shape: []int64{1}},
+			raw:    []byte{0x3c},
+			want:   "binary read failed: unexpected EOF",
+		},
+		{
+			name:   "trailing",
+			output: &testOutput{name: "score", datatype: FLOAT16, shape: []int64{1}},
+			raw:    halfBytes(0x3c00, 0x3c00, 0),
+			want:   "output score: 4 trailing bytes after 1 elements",
+		},
+		{
+			name:   "several elements",
+			output: &testOutput{name: "score", datatype: FLOAT16, shape: []int64{2}},
+			raw:    halfBytes(0x3c00, 0x3c00),
+			want:   "output score: 2 trailing bytes after 1 elements",
+		},
+		{
+			name:   "field type",
+			output: &testOutput{name: "integer", datatype: FLOAT16, shape: []int64{1}},
+			raw:    halfBytes(0x3c00),
+			want:   "types doesn't match exp: float32 got: int32",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
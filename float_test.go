@@ -0,0 +1,60 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func fp64Response(vals []float64, order binary.ByteOrder) *testResponse {
+	raw := make([]byte, 8*len(vals))
+	for i, v := range vals {
+		order.PutUint64(raw[8*i:], math.Float64bits(v))
+	}
+
+	return &testResponse{
+		outputs: []*testOutput{{name: "embedding", datatype: FLOAT64, shape: []int64{1, int64(len(vals))}}},
+		raw:     [][]byte{raw},
+	}
+}
+
+func TestUnmarshalFP64(t *testing.T) {
+	want := []float64{0, -1.5, math.Pi, math.Inf(1), math.SmallestNonzeroFloat64}
+
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		var res struct {
+			Embedding []float64 `triton:"embedding"`
+		}
+
+		if err := UnmarshalWithOptions(fp64Response(want, order), &res, Options{ByteOrder: order}); err != nil {
+			t.Fatalf("%s: %v", order, err)
+		}
+
+		if !reflect.DeepEqual(res.Embedding, want) {
+			t.Errorf("%s: got %v, want %v", order, res.Embedding, want)
+		}
+	}
+}
+
+func BenchmarkUnmarshalFP64(b *testing.B) {
+	vals := make([]float64, 4096)
+	for i := range vals {
+		vals[i] = float64(i) / 3
+	}
+
+	resp := fp64Response(vals, binary.LittleEndian)
+
+	var res struct {
+		Embedding []float64 `triton:"embedding"`
+	}
+
+	b.SetBytes(int64(8 * len(vals)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := Unmarshal(resp, &res); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
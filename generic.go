@@ -0,0 +1,143 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Element is a type outputs are decoded into by DecodeValue, DecodeArray and DecodeMatrix.
+type Element interface {
+	bool | int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64 | float32 | float64 | string
+}
+
+// datatypeOf returns datatype decoded into E.
+func datatypeOf[E Element]() string {
+	var e E
+	switch any(e).(type) {
+	case bool:
+		return BOOL
+	case int8:
+		return INT8
+	case int16:
+		return INT16
+	case int32:
+		return INT32
+	case int64:
+		return INT64
+	case uint8:
+		return UINT8
+	case uint16:
+		return UINT16
+	case uint32:
+		return UINT32
+	case uint64:
+		return UINT64
+	case float32:
+		return FLOAT32
+	case float64:
+		return FLOAT64
+	default:
+		return STRING
+	}
+}
+
+// decodeElements decodes count little-endian elements of output of datatype matching E.
+func decodeElements[E Element](output TritonModelInferResponseOutputs, rawBytes []byte, count int) ([]E, error) {
+	if dt := datatypeOf[E](); output.GetDatatype() != dt {
+		return nil, fmt.Errorf("output %s: types doesn't match exp: %s got: %s", output.GetName(), dt, output.GetDatatype())
+	}
+
+	var (
+		arr []E
+		err error
+	)
+
+	switch {
+	case output.GetDatatype() == STRING && len(rawBytes) == 0:
+		// empty contents are empty strings, as in Unmarshal.
+		arr = make([]E, count)
+	case output.GetDatatype() == STRING:
+		var strs []string
+		strs, err = stringBytesToArray(rawBytes, count, binary.LittleEndian, &Options{})
+		arr = as[[]E](strs)
+	default:
+		// capacity comes from untrusted shape, so contents are checked against its size first.
+		var size int
+		if size, err = OutputByteSize(output.GetDatatype(), output.GetShape()); err == nil && len(rawBytes) != size {
+			err = fmt.Errorf("raw contents length %d doesn't match shape %v", len(rawBytes), output.GetShape())
+		}
+
+		if err == nil {
+			arr, err = bytesToArray(rawBytes, make([]E, 0, count), binary.LittleEndian)
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("output %s: %w", output.GetName(), err)
+	}
+
+	if len(arr) != count {
+		return nil, fmt.Errorf("output %s: %d elements don't match shape %v", output.GetName(), len(arr), output.GetShape())
+	}
+
+	return arr, nil
+}
+
+// DecodeValue decodes output of shape [1] into a single value without reflection.
+// It's the building block of code generated by tritongen.
+func DecodeValue[E Element](output TritonModelInferResponseOutputs, rawBytes []byte) (E, error) {
+	var e E
+	if shape := output.GetShape(); len(shape) != 1 || shape[0] != 1 {
+		return e, fmt.Errorf("output %s: shape [1] required, got %v", output.GetName(), shape)
+	}
+
+	arr, err := decodeElements[E](output, rawBytes, 1)
+	if err != nil {
+		return e, err
+	}
+
+	return arr[0], nil
+}
+
+// DecodeArray decodes output of shape [N] or [1, N] into a slice without reflection.
+func DecodeArray[E Element](output TritonModelInferResponseOutputs, rawBytes []byte) ([]E, error) {
+	shape := output.GetShape()
+
+	var count int64
+	switch {
+	case len(shape) == 1 && shape[0] >= 0:
+		count = shape[0]
+	case len(shape) == 2 && shape[0] == 1 && shape[1] >= 0:
+		count = shape[1]
+	default:
+		return nil, fmt.Errorf("output %s: shape [N] or [1, N] required, got %v", output.GetName(), shape)
+	}
+
+	return decodeElements[E](output, rawBytes, int(count))
+}
+
+// DecodeMatrix decodes output of shape [M, N] in row-major order into a slice of slices without reflection.
+func DecodeMatrix[E Element](output TritonModelInferResponseOutputs, rawBytes []byte) ([][]E, error) {
+	shape := output.GetShape()
+	if len(shape) != 2 || shape[0] < 0 || shape[1] < 0 {
+		return nil, fmt.Errorf("output %s: shape [M, N] required, got %v", output.GetName(), shape)
+	}
+
+	rows, cols := int(shape[0]), int(shape[1])
+	if cols != 0 && rows > math.MaxInt/cols {
+		return nil, fmt.Errorf("output %s: shape %v is too large", output.GetName(), shape)
+	}
+
+	arr, err := decodeElements[E](output, rawBytes, rows*cols)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([][]E, rows)
+	for i := range res {
+		res[i] = arr[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+
+	return res, nil
+}
@@ -0,0 +1,236 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func int32Bytes(vs ...int32) []byte {
+	b := make([]byte, 0, 4*len(vs))
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint32(b, uint32(v))
+	}
+
+	return b
+}
+
+func int64Bytes(vs ...int64) []byte {
+	b := make([]byte, 0, 8*len(vs))
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint64(b, uint64(v))
+	}
+
+	return b
+}
+
+func TestDecodeValue(t *testing.T) {
+	got, err := DecodeValue[int32](&testOutput{name: "v", datatype: INT32, shape: []int64{1}}, int32Bytes(-7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != -7 {
+		t.Errorf("got %d, want -7", got)
+	}
+
+	s, err := DecodeValue[string](&testOutput{name: "v", datatype: STRING, shape: []int64{1}}, encodeStrings("ok"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s != "ok" {
+		t.Errorf("got %q, want ok", s)
+	}
+}
+
+func TestDecodeArray(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape []int64
+		raw   []byte
+		want  []int32
+	}{
+		{name: "vector", shape: []int64{3}, raw: int32Bytes(1, 2, 3), want: []int32{1, 2, 3}},
+		{name: "row", shape: []int64{1, 2}, raw: int32Bytes(4, 5), want: []int32{4, 5}},
+		{name: "empty", shape: []int64{0}, raw: nil, want: []int32{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeArray[int32](&testOutput{name: "a", datatype: INT32, shape: tt.shape}, tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeMatrix(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape []int64
+		raw   []byte
+		want  [][]int32
+	}{
+		{name: "2x3", shape: []int64{2, 3}, raw: int32Bytes(1, 2, 3, 4, 5, 6), want: [][]int32{{1, 2, 3}, {4, 5, 6}}},
+		{name: "no columns", shape: []int64{2, 0}, raw: nil, want: [][]int32{{}, {}}},
+		{name: "no rows", shape: []int64{0, 3}, raw: nil, want: [][]int32{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeMatrix[int32](&testOutput{name: "m", datatype: INT32, shape: tt.shape}, tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	strs, err := DecodeMatrix[string](&testOutput{name: "m", datatype: STRING, shape: []int64{2, 1}}, encodeStrings("a", "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := [][]string{{"a"}, {"b"}}; !reflect.DeepEqual(strs, want) {
+		t.Errorf("got %v, want %v", strs, want)
+	}
+}
+
+func TestDecodeGenericErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		decode func(TritonModelInferResponseOutputs, []byte) error
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name: "value type",
+			decode: func(o TritonModelInferResponseOutputs, b []byte) error {
+				_, err := DecodeValue[int64](o, b)
+				return err
+			},
+			output: &testOutput{name: "v", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "types doesn't match",
+		},
+		{
+			name: "value shape",
+			decode: func(o TritonModelInferResponseOutputs, b []byte) error {
+				_, err := DecodeValue[int32](o, b)
+				return err
+			},
+			output: &testOutput{name: "v", datatype: INT32, shape: []int64{2}},
+			raw:    int32Bytes(1, 2),
+			want:   "shape [1] required",
+		},
+		{
+			name: "value short",
+			decode: func(o TritonModelInferResponseOutputs, b []byte) error {
+				_, err := DecodeValue[int32](o, b)
+				return err
+			},
+			output: &testOutput{name: "v", datatype: INT32, shape: []int64{1}},
+			raw:    []byte{1, 2},
+			want:   "doesn't match shape",
+		},
+		{
+			name: "array shape",
+			decode: func(o TritonModelInferResponseOutputs, b []byte) error {
+				_, err := DecodeArray[int32](o, b)
+				return err
+			},
+			output: &testOutput{name: "a", datatype: INT32, shape: []int64{2, 2}},
+			raw:    int32Bytes(1, 2, 3, 4),
+			want:   "shape [N] or [1, N] required",
+		},
+		{
+			name: "array negative",
+			decode: func(o TritonModelInferResponseOutputs, b []byte) error {
+				_, err := DecodeArray[int32](o, b)
+				return err
+			},
+			output: &testOutput{name: "a", datatype: INT32, shape: []int64{-1}},
+			want:   "shape [N] or [1, N] required",
+		},
+		{
+			name: "array long",
+			decode: func(o TritonModelInferResponseOutputs, b []byte) error {
+				_, err := DecodeArray[int32](o, b)
+				return err
+			},
+			output: &testOutput{name: "a", datatype: INT32, shape: []int64{2}},
+			raw:    int32Bytes(1, 2, 3),
+			want:   "doesn't match shape",
+		},
+		{
+			name: "array huge",
+			decode: func(o TritonModelInferResponseOutputs, b []byte) error {
+				_, err := DecodeArray[int32](o, b)
+				return err
+			},
+			output: &testOutput{name: "a", datatype: INT32, shape: []int64{1 << 40}},
+			raw:    int32Bytes(1),
+			want:   "doesn't match shape",
+		},
+		{
+			name: "array strings",
+			decode: func(o TritonModelInferResponseOutputs, b []byte) error {
+				_, err := DecodeArray[string](o, b)
+				return err
+			},
+			output: &testOutput{name: "a", datatype: STRING, shape: []int64{2}},
+			raw:    encodeStrings("a"),
+			want:   "a:",
+		},
+		{
+			name: "matrix shape",
+			decode: func(o TritonModelInferResponseOutputs, b []byte) error {
+				_, err := DecodeMatrix[int32](o, b)
+				return err
+			},
+			output: &testOutput{name: "m", datatype: INT32, shape: []int64{4}},
+			raw:    int32Bytes(1, 2, 3, 4),
+			want:   "shape [M, N] required",
+		},
+		{
+			name: "matrix overflow",
+			decode: func(o TritonModelInferResponseOutputs, b []byte) error {
+				_, err := DecodeMatrix[int32](o, b)
+				return err
+			},
+			output: &testOutput{name: "m", datatype: INT32, shape: []int64{math.MaxInt64, 2}},
+			want:   "too large",
+		},
+		{
+			name: "matrix huge",
+			decode: func(o TritonModelInferResponseOutputs, b []byte) error {
+				_, err := DecodeMatrix[float64](o, b)
+				return err
+			},
+			output: &testOutput{name: "m", datatype: FLOAT64, shape: []int64{1 << 20, 1 << 20}},
+			raw:    make([]byte, 8),
+			want:   "doesn't match shape",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.decode(tt.output, tt.raw)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package tritonparser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// binaryDataSize is the parameter of outputs whose data follows JSON header
+// with binary tensor data extension.
+const binaryDataSize = "binary_data_size"
+
+// httpResponse is inference response of Triton HTTP/REST API.
+type httpResponse struct {
+	ModelName    string         `json:"model_name"`
+	ModelVersion string         `json:"model_version"`
+	Parameters   map[string]any `json:"parameters"`
+	Outputs      []httpOutput   `json:"outputs"`
+	Error        string         `json:"error"`
+}
+
+// httpOutput is output of httpResponse. Data holds elements in row-major order,
+// either flat or nested by shape.
+type httpOutput struct {
+	Name       string          `json:"name"`
+	Datatype   string          `json:"datatype"`
+	Shape      []int64         `json:"shape"`
+	Parameters map[string]any  `json:"parameters"`
+	Data       json.RawMessage `json:"data"`
+}
+
+func (o *httpOutput) GetName() string {
+	return o.Name
+}
+
+func (o *httpOutput) GetDatatype() string {
+	return o.Datatype
+}
+
+func (o *httpOutput) GetShape() []int64 {
+	return o.Shape
+}
+
+func (o *httpOutput) GetParameters() map[string]any {
+	return o.Parameters
+}
+
+// UnmarshalHTTP is the same as Unmarshal, but decodes JSON inference response of Triton HTTP/REST API,
+// so the same structs serve gRPC and REST clients.
+func UnmarshalHTTP(jsonBytes []byte, v any) error {
+	return UnmarshalHTTPWithOptions(jsonBytes, v, Options{})
+}
+
+// UnmarshalHTTPWithOptions is the same as UnmarshalHTTP, but decoding is configured with opts.
+// Options and tag options concerning layout of raw contents, e.g. ByteOrder or Decompress, don't apply.
+// Outputs returned with binary tensor data extension are not supported.
+func UnmarshalHTTPWithOptions(jsonBytes []byte, v any, opts Options) error {
+	resp, err := parseHTTPResponse(jsonBytes)
+	if err != nil {
+		return err
+	}
+
+	opts.Decompress = CompressionNone
+	opts.ByteOrder, opts.ByteOrderFor, opts.fixedByteOrder = binary.LittleEndian, nil, true
+	opts.StringLengthPrefix = 0
+
+	return UnmarshalWithOptions(resp, v, opts)
+}
+
+// parseHTTPResponse adapts JSON inference response to Response,
+// encoding data of outputs into raw contents as they are sent over gRPC.
+func parseHTTPResponse(jsonBytes []byte) (*Response, error) {
+	var hr httpResponse
+	if err := json.Unmarshal(jsonBytes, &hr); err != nil {
+		return nil, fmt.Errorf("json decode failed: %w", err)
+	}
+
+	res := &Response{
+		ModelName:         hr.ModelName,
+		ModelVersion:      hr.ModelVersion,
+		Parameters:        hr.Parameters,
+		ErrorMessage:      hr.Error,
+		Outputs:           make([]TritonModelInferResponseOutputs, len(hr.Outputs)),
+		RawOutputContents: make([][]byte, len(hr.Outputs)),
+	}
+
+	for i := range hr.Outputs {
+		o := &hr.Outputs[i]
+		if _, ok := o.Parameters[binaryDataSize]; ok {
+			return nil, fmt.Errorf("output %s: binary data extension is not supported", o.Name)
+		}
+
+		raw, err := encodeHTTPData(o)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", o.Name, err)
+		}
+
+		res.Outputs[i], res.RawOutputContents[i] = o, raw
+	}
+
+	return res, nil
+}
+
+// encodeHTTPData encodes JSON data of output into little-endian raw contents.
+func encodeHTTPData(o *httpOutput) ([]byte, error) {
+	if len(o.Data) == 0 {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(o.Data))
+	dec.UseNumber()
+
+	var data any
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("json decode failed: %w", err)
+	}
+
+	elems := flattenHTTPData(data, nil)
+
+	var (
+		res     []byte
+		nibbles []byte
+	)
+
+	for _, e := range elems {
+		var err error
+
+		switch o.Datatype {
+		case INT4, UINT4:
+			var n int64
+			n, err = httpInt(e, 4, o.Datatype == INT4)
+			nibbles = append(nibbles, byte(n)&0xf)
+		default:
+			res, err = appendHTTPElement(res, o.Datatype, e)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if nibbles != nil {
+		res = make([]byte, (len(nibbles)+1)/2)
+		for i, n := range nibbles {
+			res[i/2] |= n << (4 * (i % 2))
+		}
+	}
+
+	return res, nil
+}
+
+// flattenHTTPData appends elements of possibly nested data to res in row-major order.
+func flattenHTTPData(data any, res []any) []any {
+	arr, ok := data.([]any)
+	if !ok {
+		return append(res, data)
+	}
+
+	for _, e := range arr {
+		res = flattenHTTPData(e, res)
+	}
+
+	return res
+}
+
+// appendHTTPElement appends little-endian encoding of JSON element e of datatype to b.
+func appendHTTPElement(b []byte, datatype string, e any) ([]byte, error) {
+	order := binary.LittleEndian
+
+	switch datatype {
+	case BOOL:
+		v, ok := e.(bool)
+		if !ok {
+			return nil, fmt.Errorf("types doesn't match exp: bool got: %T", e)
+		}
+
+		if v {
+			return append(b, 1), nil
+		}
+
+		return append(b, 0), nil
+	case STRING:
+		v, ok := e.(string)
+		if !ok {
+			return nil, fmt.Errorf("types doesn't match exp: string got: %T", e)
+		}
+
+		b = order.AppendUint32(b, uint32(len(v)))
+
+		return append(b, v...), nil
+	case INT8, INT16, INT32, INT64:
+		size, _ := OutputByteSize(datatype, nil)
+
+		v, err := httpInt(e, size*8, true)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(b, order.AppendUint64(nil, uint64(v))[:size]...), nil
+	case UINT8, UINT16, UINT32, UINT64:
+		size, _ := OutputByteSize(datatype, nil)
+
+		v, err := httpUint(e, size*8)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(b, order.AppendUint64(nil, v)[:size]...), nil
+	case FLOAT16, FLOAT32, FLOAT64:
+		v, err := httpFloat(e)
+		if err != nil {
+			return nil, err
+		}
+
+		switch datatype {
+		case FLOAT16:
+			return order.AppendUint16(b, float32ToHalf(float32(v))), nil
+		case FLOAT32:
+			return order.AppendUint32(b, math.Float32bits(float32(v))), nil
+		default:
+			return order.AppendUint64(b, math.Float64bits(v)), nil
+		}
+	default:
+		return nil, fmt.Errorf("unkwnow type: %s", datatype)
+	}
+}
+
+func httpNumber(e any) (json.Number, error) {
+	n, ok := e.(json.Number)
+	if !ok {
+		return "", fmt.Errorf("types doesn't match exp: number got: %T", e)
+	}
+
+	return n, nil
+}
+
+// httpInt parses JSON element e as integer of given bits. Unsigned values are parsed if signed is false.
+func httpInt(e any, bits int, signed bool) (int64, error) {
+	if !signed {
+		v, err := httpUint(e, bits)
+		return int64(v), err //nolint:gosec // v fits in bits below 64.
+	}
+
+	n, err := httpNumber(e)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := strconv.ParseInt(n.String(), 10, bits)
+	if err != nil {
+		return 0, fmt.Errorf("invalid element: %w", err)
+	}
+
+	return v, nil
+}
+
+// httpUint parses JSON element e as unsigned integer of given bits.
+func httpUint(e any, bits int) (uint64, error) {
+	n, err := httpNumber(e)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := strconv.ParseUint(n.String(), 10, bits)
+	if err != nil {
+		return 0, fmt.Errorf("invalid element: %w", err)
+	}
+
+	return v, nil
+}
+
+// httpFloat parses JSON element e as float. Triton encodes non-finite values as strings.
+func httpFloat(e any) (float64, error) {
+	s, ok := e.(string)
+	if !ok {
+		n, err := httpNumber(e)
+		if err != nil {
+			return 0, err
+		}
+
+		s = n.String()
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid element: %w", err)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,22 @@
+package tritonparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalHTTPIgnoresByteOrderTags(t *testing.T) {
+	body := []byte(`{"outputs": [{"name": "ids", "datatype": "INT32", "shape": [2], "data": [1, 2]}]}`)
+
+	var res struct {
+		IDs []int32 `triton:"ids,bigendian"`
+	}
+
+	if err := UnmarshalHTTP(body, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int32{1, 2}; !reflect.DeepEqual(res.IDs, want) {
+		t.Errorf("got %v, want %v", res.IDs, want)
+	}
+}
@@ -0,0 +1,118 @@
+package tritonparser
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// decodeImage decodes UINT8 output of shape [H, W, C] or [1, H, W, C] into image field.
+// C is 1 for grayscale, 3 for RGB and 4 for RGBA. Field is image.Image, image.Gray,
+// image.RGBA, image.NRGBA or pointer to one of them.
+func decodeImage(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte) error {
+	if output.GetDatatype() != UINT8 {
+		return fmt.Errorf("output %s: %s option requires %s datatype, got %s", output.GetName(), tagImage, UINT8, output.GetDatatype())
+	}
+
+	shape := output.GetShape()
+	if len(shape) == 4 && shape[0] == 1 {
+		shape = shape[1:]
+	}
+
+	if len(shape) != 3 || slices.ContainsFunc(shape, func(dim int64) bool { return dim <= 0 || dim > math.MaxInt32 }) {
+		return fmt.Errorf("output %s: %s option requires [H, W, C] shape, got %v", output.GetName(), tagImage, output.GetShape())
+	}
+
+	// size is checked for overflow, so hostile shape can't pass with short contents.
+	size, err := OutputByteSize(UINT8, shape)
+	if err != nil {
+		return fmt.Errorf("output %s: %w", output.GetName(), err)
+	}
+
+	if len(rawBytes) != size {
+		return fmt.Errorf("output %s: raw contents length %d doesn't match shape %v", output.GetName(), len(rawBytes), output.GetShape())
+	}
+
+	h, w, c := int(shape[0]), int(shape[1]), int(shape[2])
+
+	src, err := rawImage(rawBytes, h, w, c)
+	if err != nil {
+		return fmt.Errorf("output %s: %w", output.GetName(), err)
+	}
+
+	img, err := convertImage(src, field.Type())
+	if err != nil {
+		return fmt.Errorf("output %s: %w", output.GetName(), err)
+	}
+
+	field.Set(img)
+
+	return nil
+}
+
+func rawImage(b []byte, h, w, c int) (image.Image, error) {
+	rect := image.Rect(0, 0, w, h)
+
+	switch c {
+	case 1:
+		img := image.NewGray(rect)
+		copy(img.Pix, b)
+
+		return img, nil
+	case 3:
+		img := image.NewNRGBA(rect)
+		for i := 0; i < w*h; i++ {
+			copy(img.Pix[i*4:i*4+3], b[i*3:i*3+3])
+			img.Pix[i*4+3] = 0xff
+		}
+
+		return img, nil
+	case 4:
+		img := image.NewNRGBA(rect)
+		copy(img.Pix, b)
+
+		return img, nil
+	default:
+		return nil, fmt.Errorf("unsupported number of channels: %d", c)
+	}
+}
+
+// convertImage converts src to value of type t.
+func convertImage(src image.Image, t reflect.Type) (reflect.Value, error) {
+	if t == reflect.TypeFor[image.Image]() {
+		return reflect.ValueOf(&src).Elem(), nil
+	}
+
+	elem := t
+	if t.Kind() == reflect.Pointer {
+		elem = t.Elem()
+	}
+
+	var dst draw.Image
+
+	switch elem {
+	case reflect.TypeFor[image.Gray]():
+		dst = image.NewGray(src.Bounds())
+	case reflect.TypeFor[image.RGBA]():
+		dst = image.NewRGBA(src.Bounds())
+	case reflect.TypeFor[image.NRGBA]():
+		dst = image.NewNRGBA(src.Bounds())
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported image type: %s", t)
+	}
+
+	if reflect.TypeOf(src) == reflect.TypeOf(dst) {
+		dst, _ = src.(draw.Image)
+	} else {
+		draw.Draw(dst, dst.Bounds(), src, src.Bounds().Min, draw.Src)
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return reflect.ValueOf(dst), nil
+	}
+
+	return reflect.ValueOf(dst).Elem(), nil
+}
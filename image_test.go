@@ -0,0 +1,108 @@
+package tritonparser
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func imageResponse(datatype string, shape []int64, raw []byte) *testResponse {
+	return &testResponse{
+		outputs: []*testOutput{{name: "img", datatype: datatype, shape: shape}},
+		raw:     [][]byte{raw},
+	}
+}
+
+func TestDecodeImage(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape []int64
+		raw   []byte
+		want  color.NRGBA
+	}{
+		{name: "gray", shape: []int64{1, 2, 1}, raw: []byte{10, 20}, want: color.NRGBA{R: 20, G: 20, B: 20, A: 0xff}},
+		{name: "rgb", shape: []int64{1, 2, 3}, raw: []byte{1, 2, 3, 4, 5, 6}, want: color.NRGBA{R: 4, G: 5, B: 6, A: 0xff}},
+		{name: "rgba", shape: []int64{1, 2, 4}, raw: []byte{1, 2, 3, 4, 5, 6, 7, 8}, want: color.NRGBA{R: 5, G: 6, B: 7, A: 8}},
+		{name: "batched", shape: []int64{1, 1, 2, 1}, raw: []byte{10, 20}, want: color.NRGBA{R: 20, G: 20, B: 20, A: 0xff}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res struct {
+				Img image.Image `triton:"img,image"`
+			}
+
+			if err := Unmarshal(imageResponse(UINT8, tt.shape, tt.raw), &res); err != nil {
+				t.Fatal(err)
+			}
+
+			if b := res.Img.Bounds(); b.Dx() != 2 || b.Dy() != 1 {
+				t.Fatalf("got bounds %v, want 2x1", b)
+			}
+
+			if got := color.NRGBAModel.Convert(res.Img.At(1, 0)); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeImageConverted(t *testing.T) {
+	var res struct {
+		Gray *image.Gray `triton:"img,image"`
+		RGBA image.RGBA  `triton:"rgba,image"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "img", datatype: UINT8, shape: []int64{1, 1, 1}},
+			{name: "rgba", datatype: UINT8, shape: []int64{1, 1, 3}},
+		},
+		raw: [][]byte{{42}, {1, 2, 3}},
+	}
+
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Gray.GrayAt(0, 0).Y != 42 {
+		t.Errorf("gray: got %v, want 42", res.Gray.GrayAt(0, 0))
+	}
+
+	if got := res.RGBA.RGBAAt(0, 0); got != (color.RGBA{R: 1, G: 2, B: 3, A: 0xff}) {
+		t.Errorf("rgba: got %v", got)
+	}
+}
+
+func TestDecodeImageErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		datatype string
+		shape    []int64
+		raw      []byte
+	}{
+		{name: "datatype", datatype: INT8, shape: []int64{1, 1, 1}, raw: []byte{1}},
+		{name: "rank", datatype: UINT8, shape: []int64{2, 1}, raw: []byte{1, 2}},
+		{name: "length", datatype: UINT8, shape: []int64{2, 2, 1}, raw: []byte{1, 2, 3}},
+		{name: "channels", datatype: UINT8, shape: []int64{1, 1, 2}, raw: []byte{1, 2}},
+		{name: "zero height", datatype: UINT8, shape: []int64{0, 1, 1}, raw: []byte{}},
+		{name: "zero channels", datatype: UINT8, shape: []int64{1, 1, 0}, raw: []byte{}},
+		{name: "negative width", datatype: UINT8, shape: []int64{1, -1, 1}, raw: []byte{}},
+		{name: "huge channels", datatype: UINT8, shape: []int64{1, 1, 1 << 32}, raw: []byte{}},
+		// h*w*c overflows to 0, matching empty contents.
+		{name: "overflowing shape", datatype: UINT8, shape: []int64{1 << 32, 1 << 32, 1}, raw: []byte{}},
+		{name: "overflowing int32 shape", datatype: UINT8, shape: []int64{1 << 30, 1 << 30, 1 << 30}, raw: []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res struct {
+				Img image.Image `triton:"img,image"`
+			}
+
+			if err := Unmarshal(imageResponse(tt.datatype, tt.shape, tt.raw), &res); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
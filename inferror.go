@@ -0,0 +1,39 @@
+package tritonparser
+
+import "fmt"
+
+// TritonErrorResponse is implemented by responses that carry error of failed inference in-band,
+// e.g. generated ModelStreamInferResponse. Empty message means inference succeeded.
+type TritonErrorResponse interface {
+	GetErrorMessage() string
+}
+
+// InferenceError is returned by Unmarshal if response carries error message instead of outputs.
+type InferenceError struct {
+	// Model is the name of model, if response implements TritonModelInfo.
+	Model   string
+	Message string
+}
+
+func (e *InferenceError) Error() string {
+	if e.Model == "" {
+		return "inference failed: " + e.Message
+	}
+
+	return fmt.Sprintf("model %s: inference failed: %s", e.Model, e.Message)
+}
+
+// responseError returns InferenceError if inferResponse carries error message.
+func responseError(inferResponse any) error {
+	er, ok := inferResponse.(TritonErrorResponse)
+	if !ok || er.GetErrorMessage() == "" {
+		return nil
+	}
+
+	res := &InferenceError{Message: er.GetErrorMessage()}
+	if info, ok := inferResponse.(TritonModelInfo); ok {
+		res.Model = info.GetModelName()
+	}
+
+	return res
+}
@@ -0,0 +1,56 @@
+package tritonparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInferenceError(t *testing.T) {
+	failed := &Response{ModelName: "resnet", ErrorMessage: "out of memory"}
+
+	var res struct {
+		Score int32 `triton:"score"`
+	}
+
+	b, err := Bind[TritonModelInferResponseOutputs](&res, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var nested struct {
+		Step struct {
+			Score int32 `triton:"score"`
+		} `triton:"step,nested"`
+	}
+
+	outer := &Response{
+		Outputs:           []TritonModelInferResponseOutputs{&httpOutput{Name: "step", Datatype: STRING, Shape: []int64{1}}},
+		RawOutputContents: [][]byte{encodeStrings("step")},
+	}
+	opts := Options{NestedDecoder: func([]byte) (*Response, error) { return failed, nil }}
+
+	var single int32
+
+	tests := []struct {
+		name   string
+		decode func() error
+	}{
+		{name: "unmarshal", decode: func() error { return Unmarshal(failed, &res) }},
+		{name: "single", decode: func() error { return Unmarshal(failed, &single) }},
+		{name: "binding", decode: func() error { return b.Decode(failed) }},
+		{name: "nested", decode: func() error { return UnmarshalWithOptions(outer, &nested, opts) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ie *InferenceError
+			if err := tt.decode(); !errors.As(err, &ie) {
+				t.Fatalf("got %v, want InferenceError", err)
+			}
+
+			if ie.Model != "resnet" || ie.Message != "out of memory" {
+				t.Errorf("got %+v", ie)
+			}
+		})
+	}
+}
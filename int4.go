@@ -0,0 +1,110 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// unpackNibbles unpacks count 4-bit values packed two per byte, low nibble first.
+// Signed values are sign-extended.
+func unpackNibbles[T int8 | uint8](b []byte, count int, signed bool) ([]T, error) {
+	if len(b) != (count+1)/2 {
+		return nil, fmt.Errorf("raw contents length %d doesn't match %d packed 4-bit elements", len(b), count)
+	}
+
+	arr := make([]T, count)
+	for i := range arr {
+		n := b[i/2] >> (4 * (i % 2)) & 0x0f
+		if signed {
+			n = uint8(int8(n<<4) >> 4)
+		}
+
+		arr[i] = T(n)
+	}
+
+	return arr, nil
+}
+
+func unmarshalInt4Value[T int8 | uint8](
+	fieldMap map[string]reflect.Value,
+	resp TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+) error {
+	var val T
+	if err := checkType(fieldMap[resp.GetName()], reflect.TypeOf(val), opts); err != nil {
+		return err
+	}
+
+	arr, err := unpackNibbles[T](rawBytes, 1, resp.GetDatatype() == INT4)
+	if err != nil {
+		return fmt.Errorf("output %s: %w", resp.GetName(), err)
+	}
+
+	if v, ok := fieldMap[resp.GetName()]; ok {
+		return setValue(v, reflect.ValueOf(arr[0]), resp.GetName(), opts)
+	}
+
+	return nil
+}
+
+func unmarshalInt4Array[T int8 | uint8](
+	fieldMap map[string]reflect.Value,
+	resp TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+) error {
+	var arr []T
+	if err := checkType(fieldMap[resp.GetName()], reflect.TypeOf(arr), opts); err != nil {
+		return err
+	}
+
+	arr, err := unpackNibbles[T](rawBytes, int(resp.GetShape()[1]), resp.GetDatatype() == INT4)
+	if err != nil {
+		return fmt.Errorf("output %s: %w", resp.GetName(), err)
+	}
+
+	if v, ok := fieldMap[resp.GetName()]; ok {
+		return setValue(v, reflect.ValueOf(arr), resp.GetName(), opts)
+	}
+
+	return nil
+}
+
+func unmarshalInt4MultidimenshionalArray[T int8 | uint8](
+	fieldMap map[string]reflect.Value,
+	resp TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+) error {
+	numOfArrays := int(resp.GetShape()[0])
+	arrLen := int(resp.GetShape()[1])
+	if err := checkType(fieldMap[resp.GetName()], reflect.TypeOf([][]T{}), opts); err != nil {
+		return err
+	}
+
+	flat, err := unpackNibbles[T](rawBytes, numOfArrays*arrLen, resp.GetDatatype() == INT4)
+	if err != nil {
+		return fmt.Errorf("output %s: %w", resp.GetName(), err)
+	}
+
+	// rows are allocated once contents are known to match shape.
+	arr := make([][]T, numOfArrays)
+	for i := range arr {
+		arr[i] = make([]T, arrLen)
+	}
+
+	k := 0
+	_ = walkMultidimenshional(numOfArrays, arrLen, opts, func(i, j int) error {
+		arr[i][j] = flat[k]
+		k++
+
+		return nil
+	})
+
+	if v, ok := fieldMap[resp.GetName()]; ok {
+		return setValue(v, reflect.ValueOf(arr), resp.GetName(), opts)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package tritonparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+type int4Result struct {
+	Signed   int8     `triton:"signed"`
+	Unsigned uint8    `triton:"unsigned"`
+	Array    []int8   `triton:"array"`
+	Odd      []uint8  `triton:"odd"`
+	Matrix   [][]int8 `triton:"matrix"`
+}
+
+func TestInt4(t *testing.T) {
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "signed", datatype: INT4, shape: []int64{1}},
+			{name: "unsigned", datatype: UINT4, shape: []int64{1}},
+			{name: "array", datatype: INT4, shape: []int64{1, 4}},
+			{name: "odd", datatype: UINT4, shape: []int64{1, 3}},
+			{name: "matrix", datatype: INT4, shape: []int64{2, 2}},
+		},
+		raw: [][]byte{{0x0f}, {0x0f}, {0x21, 0x8f}, {0x21, 0x03}, {0xf1, 0x72}},
+	}
+
+	var res int4Result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := int4Result{
+		Signed:   -1,
+		Unsigned: 15,
+		Array:    []int8{1, 2, -1, -8},
+		Odd:      []uint8{1, 2, 3},
+		Matrix:   [][]int8{{1, -1}, {2, 7}},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestInt4Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+	}{
+		{name: "scalar long", output: &testOutput{name: "signed", datatype: INT4, shape: []int64{1}}, raw: []byte{1, 2}},
+		{name: "scalar empty", output: &testOutput{name: "unsigned", datatype: UINT4, shape: []int64{1}}, raw: []byte{}},
+		{name: "array short", output: &testOutput{name: "array", datatype: INT4, shape: []int64{1, 4}}, raw: []byte{1}},
+		{name: "array long", output: &testOutput{name: "odd", datatype: UINT4, shape: []int64{1, 3}}, raw: []byte{1, 2, 3}},
+		{name: "matrix short", output: &testOutput{name: "matrix", datatype: INT4, shape: []int64{2, 3}}, raw: []byte{1, 2}},
+		{name: "matrix huge", output: &testOutput{name: "matrix", datatype: INT4, shape: []int64{1 << 40, 2}}, raw: []byte{1}},
+		{name: "field type", output: &testOutput{name: "unsigned", datatype: INT4, shape: []int64{1}}, raw: []byte{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res int4Result
+			if err := Unmarshal(&testResponse{outputs: []*testOutput{tt.output}, raw: [][]byte{tt.raw}}, &res); err == nil {
+				t.Fatalf("got nil error, result %+v", res)
+			}
+		})
+	}
+}
+
+func TestInt4Elements(t *testing.T) {
+	elements, err := Elements[int8](&testOutput{name: "v", datatype: INT4, shape: []int64{1, 3}}, []byte{0x8f, 0x07})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []int8
+	for _, v := range elements {
+		got = append(got, v)
+	}
+
+	if want := []int8{-1, -8, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := Elements[int8](&testOutput{name: "v", datatype: INT4, shape: []int64{1, 3}}, []byte{0x8f}); err == nil {
+		t.Error("got nil error for short contents")
+	}
+}
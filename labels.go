@@ -0,0 +1,71 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+//nolint:gochecknoglobals // registry is shared by all decoders.
+var labelTables sync.Map
+
+// RegisterLabels registers table of labels by name, so integer outputs are decoded into labels
+// at their indices with `triton:"class,labels=name"`. Registering the same name again replaces the table.
+func RegisterLabels(name string, labels []string) {
+	labelTables.Store(name, slices.Clone(labels))
+}
+
+// decodeLabels decodes integer output of class indices into string field, or slice of them,
+// with labels of table registered by name.
+func decodeLabels(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options, name string) error {
+	table, ok := labelTables.Load(name)
+	if !ok {
+		return fmt.Errorf("output %s: labels %q are not registered", output.GetName(), name)
+	}
+
+	labels := as[[]string](table)
+
+	elem := scalarType(field.Type())
+	if elem.Kind() != reflect.String {
+		return fmt.Errorf("output %s: %s option requires string field, got %s", output.GetName(), tagLabels, field.Type())
+	}
+
+	if t, ok := elementType(output.GetDatatype()); !ok || (!isInt(t.Kind()) && !isUint(t.Kind())) {
+		return fmt.Errorf("output %s: %s option requires integer datatype, got %s", output.GetName(), tagLabels, output.GetDatatype())
+	}
+
+	if shape := output.GetShape(); len(shape) == 1 && field.Kind() == reflect.Slice {
+		output = reshaped{output, []int64{1, shape[0]}}
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	res := mapElements(val, elem, func(v reflect.Value) reflect.Value {
+		i := -1
+		switch {
+		case isInt(v.Kind()) && v.Int() >= 0 && v.Int() < int64(len(labels)):
+			i = int(v.Int())
+		case isUint(v.Kind()) && v.Uint() < uint64(len(labels)):
+			i = int(v.Uint())
+		}
+
+		if i == -1 {
+			if err == nil {
+				err = fmt.Errorf("output %s: index %v is out of range of %d labels %q", output.GetName(), v, len(labels), name)
+			}
+
+			return reflect.Zero(elem)
+		}
+
+		return reflect.ValueOf(labels[i]).Convert(elem)
+	})
+	if err != nil {
+		return err
+	}
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
@@ -0,0 +1,134 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegisterLabels(t *testing.T) {
+	type class string
+
+	type result struct {
+		Top    string     `triton:"top,labels=test_animals"`
+		Batch  []string   `triton:"batch,labels=test_animals"`
+		Matrix [][]string `triton:"matrix,labels=test_animals"`
+		Named  []class    `triton:"named,labels=test_animals"`
+		Vector []string   `triton:"vector,labels=test_animals"`
+	}
+
+	animals := []string{"cat", "dog", "fox"}
+	RegisterLabels("test_animals", animals)
+	// registered table is a copy.
+	animals[0] = "cow"
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "top", datatype: INT64, shape: []int64{1}},
+			{name: "batch", datatype: INT32, shape: []int64{1, 3}},
+			{name: "matrix", datatype: UINT8, shape: []int64{2, 1}},
+			{name: "named", datatype: INT16, shape: []int64{1, 1}},
+			{name: "vector", datatype: INT32, shape: []int64{2}},
+		},
+		raw: [][]byte{int64Bytes(2), int32Bytes(0, 1, 0), {1, 2}, int16Bytes(2), int32Bytes(1, 0)},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Top:    "fox",
+		Batch:  []string{"cat", "dog", "cat"},
+		Matrix: [][]string{{"dog"}, {"fox"}},
+		Named:  []class{"fox"},
+		Vector: []string{"dog", "cat"},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+
+	// registering the same name replaces the table.
+	RegisterLabels("test_animals", []string{"ant", "bee", "cod"})
+	t.Cleanup(func() { labelTables.Delete("test_animals") })
+
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Top != "cod" {
+		t.Errorf("got %q from replaced table, want %q", res.Top, "cod")
+	}
+}
+
+func TestRegisterLabelsErrors(t *testing.T) {
+	RegisterLabels("test_colors", []string{"red", "green"})
+	t.Cleanup(func() { labelTables.Delete("test_colors") })
+
+	var res struct {
+		Color   string   `triton:"color,labels=test_colors"`
+		Colors  []string `triton:"colors,labels=test_colors"`
+		Index   int32    `triton:"index,labels=test_colors"`
+		Unknown string   `triton:"unknown,labels=test_missing"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "unregistered",
+			output: &testOutput{name: "unknown", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(0),
+			want:   `output unknown: labels "test_missing" are not registered`,
+		},
+		{
+			name:   "out of range",
+			output: &testOutput{name: "colors", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 2),
+			want:   `output colors: index 2 is out of range of 2 labels "test_colors"`,
+		},
+		{
+			name:   "negative",
+			output: &testOutput{name: "color", datatype: INT64, shape: []int64{1}},
+			raw:    int64Bytes(-1),
+			want:   `output color: index -1 is out of range of 2 labels "test_colors"`,
+		},
+		{
+			name:   "float datatype",
+			output: &testOutput{name: "color", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(0),
+			want:   "output color: labels option requires integer datatype, got FP32",
+		},
+		{
+			name:   "integer field",
+			output: &testOutput{name: "index", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(0),
+			want:   "output index: labels option requires string field, got int32",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "colors", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 0)[:6],
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "matrix into slice",
+			output: &testOutput{name: "colors", datatype: INT32, shape: []int64{2, 1}},
+			raw:    int32Bytes(0, 1),
+			want:   "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
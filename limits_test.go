@@ -0,0 +1,124 @@
+package tritonparser
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	type result struct {
+		Scores []float32 `triton:"scores"`
+		Matrix [][]int8  `triton:"matrix"`
+		Labels []string  `triton:"labels"`
+		Emb    []float32 `triton:"emb,concat=emb_0|emb_1"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "scores", datatype: FLOAT32, shape: []int64{1, 4}},
+			{name: "matrix", datatype: INT8, shape: []int64{2, 2}},
+			{name: "labels", datatype: STRING, shape: []int64{1, 2}},
+			{name: "emb_0", datatype: FLOAT32, shape: []int64{2}},
+			{name: "emb_1", datatype: FLOAT32, shape: []int64{2}},
+		},
+		raw: [][]byte{float32Bytes(1, 2, 3, 4), {1, 2, 3, 4}, encodeStrings("ab", "cd"), float32Bytes(5, 6), float32Bytes(7, 8)},
+	}
+
+	want := result{
+		Scores: []float32{1, 2, 3, 4},
+		Matrix: [][]int8{{1, 2}, {3, 4}},
+		Labels: []string{"ab", "cd"},
+		Emb:    []float32{5, 6, 7, 8},
+	}
+
+	for _, opts := range []Options{{}, {MaxElements: 4}, {MaxBytes: 16}, {MaxElements: 4, MaxBytes: 16}} {
+		var res result
+		if err := UnmarshalWithOptions(resp, &res, opts); err != nil {
+			t.Fatalf("options %+v: %v", opts, err)
+		}
+
+		if !reflect.DeepEqual(res, want) {
+			t.Errorf("options %+v: got %+v, want %+v", opts, res, want)
+		}
+	}
+}
+
+func TestLimitsErrors(t *testing.T) {
+	var res struct {
+		Scores []float32 `triton:"scores"`
+		Labels []string  `triton:"labels"`
+		Emb    []float32 `triton:"emb,concat=emb_0|emb_1"`
+	}
+
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		opts    Options
+		want    string
+	}{
+		{
+			name:    "elements",
+			outputs: []*testOutput{{name: "scores", datatype: FLOAT32, shape: []int64{1, 5}}},
+			raw:     [][]byte{float32Bytes(1, 2, 3, 4, 5)},
+			opts:    Options{MaxElements: 4},
+			want:    "output scores: 5 elements exceed 4",
+		},
+		{
+			name:    "raw bytes",
+			outputs: []*testOutput{{name: "scores", datatype: FLOAT32, shape: []int64{1, 1}}},
+			raw:     [][]byte{float32Bytes(1, 2, 3, 4, 5)},
+			opts:    Options{MaxBytes: 16},
+			want:    "output scores: raw contents length 20 exceeds 16 bytes",
+		},
+		{
+			name:    "shape bytes",
+			outputs: []*testOutput{{name: "scores", datatype: FLOAT32, shape: []int64{1, 5}}},
+			raw:     [][]byte{float32Bytes(1)},
+			opts:    Options{MaxBytes: 16},
+			want:    "output scores: shape [1 5] requires 20 bytes, exceeding 16",
+		},
+		{
+			name:    "hostile string shape",
+			outputs: []*testOutput{{name: "labels", datatype: STRING, shape: []int64{1, 1 << 40}}},
+			raw:     [][]byte{encodeStrings("a")},
+			opts:    Options{MaxElements: 1 << 20},
+			want:    "output labels: 1099511627776 elements exceed 1048576",
+		},
+		{
+			name:    "overflowing shape",
+			outputs: []*testOutput{{name: "scores", datatype: FLOAT32, shape: []int64{math.MaxInt64, 2}}},
+			raw:     [][]byte{float32Bytes(1)},
+			opts:    Options{MaxElements: 4},
+			want:    "output scores: shape [9223372036854775807 2] is too large",
+		},
+		{
+			name:    "negative dimension",
+			outputs: []*testOutput{{name: "scores", datatype: FLOAT32, shape: []int64{1, -1}}},
+			raw:     [][]byte{float32Bytes(1)},
+			opts:    Options{MaxElements: 4},
+			want:    "invalid shape: [1 -1]",
+		},
+		{
+			name: "concat part",
+			outputs: []*testOutput{
+				{name: "emb_0", datatype: FLOAT32, shape: []int64{1 << 40}},
+				{name: "emb_1", datatype: FLOAT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{float32Bytes(1), float32Bytes(2)},
+			opts: Options{MaxElements: 4},
+			want: "output emb_0: 1099511627776 elements exceed 4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := UnmarshalWithOptions(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package tritonparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReusedMapsUntouchedOnError(t *testing.T) {
+	var res struct {
+		Counts map[string]int32 `triton:"tokens,counts"`
+		Sparse map[int64]int32  `triton:"values,indices=idx"`
+	}
+
+	valid := &Response{
+		Outputs: []TritonModelInferResponseOutputs{
+			&httpOutput{Name: "tokens", Datatype: STRING, Shape: []int64{3}},
+			&httpOutput{Name: "values", Datatype: INT32, Shape: []int64{2}},
+			&httpOutput{Name: "idx", Datatype: INT64, Shape: []int64{2}},
+		},
+		RawOutputContents: [][]byte{
+			encodeStrings("a:1", "b:2", "a:3"),
+			{5, 0, 0, 0, 6, 0, 0, 0},
+			{1, 0, 0, 0, 0, 0, 0, 0, 7, 0, 0, 0, 0, 0, 0, 0},
+		},
+	}
+
+	if err := Unmarshal(valid, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	wantCounts, wantSparse := map[string]int32{"a": 4, "b": 2}, map[int64]int32{1: 5, 7: 6}
+	if !reflect.DeepEqual(res.Counts, wantCounts) || !reflect.DeepEqual(res.Sparse, wantSparse) {
+		t.Fatalf("got %v and %v", res.Counts, res.Sparse)
+	}
+
+	badCounts := &Response{
+		Outputs:           valid.Outputs[:1],
+		RawOutputContents: [][]byte{encodeStrings("c:1", "d")},
+	}
+
+	if err := Unmarshal(badCounts, &res); err == nil {
+		t.Error("expected error for invalid count entry")
+	}
+
+	if !reflect.DeepEqual(res.Counts, wantCounts) {
+		t.Errorf("counts: got %v after error, want %v", res.Counts, wantCounts)
+	}
+
+	badSparse := &Response{
+		Outputs:           valid.Outputs[1:],
+		RawOutputContents: [][]byte{{8, 0, 0, 0, 9, 0, 0, 0}, {3, 0, 0, 0, 0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0}},
+	}
+
+	if err := Unmarshal(badSparse, &res); err == nil {
+		t.Error("expected error for duplicate index")
+	}
+
+	if !reflect.DeepEqual(res.Sparse, wantSparse) {
+		t.Errorf("sparse: got %v after error, want %v", res.Sparse, wantSparse)
+	}
+}
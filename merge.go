@@ -0,0 +1,105 @@
+package tritonparser
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Merge concatenates outputs of partial responses a and b, e.g. frames of a stream carrying chunks
+// of a logical result, into one response to decode. Raw contents of outputs with the same name are joined
+// along the first dimension, so their datatypes and other dimensions must match.
+// Outputs present in one response only are kept as is. Model name, version and parameters are taken from a, error message from a or b.
+// Raw contents must be uncompressed and in the same byte order, and outputs in shared memory aren't supported.
+func Merge[T, U TritonModelInferResponseOutputs](a TritonModelInferResponse[T], b TritonModelInferResponse[U]) (*Response, error) {
+	res := NewResponse(a)
+	res.Outputs = slices.Clone(res.Outputs)
+	res.RawOutputContents = make([][]byte, len(res.Outputs))
+
+	if er, ok := any(b).(TritonErrorResponse); ok && res.ErrorMessage == "" {
+		res.ErrorMessage = er.GetErrorMessage()
+	}
+
+	idx := make(map[string]int, len(res.Outputs))
+	for i, o := range res.Outputs {
+		raw, err := partContents(o, i, a.GetRawOutputContents())
+		if err != nil {
+			return nil, err
+		}
+
+		res.RawOutputContents[i] = slices.Clone(raw)
+
+		if _, ok := idx[o.GetName()]; !ok {
+			idx[o.GetName()] = i
+		}
+	}
+
+	for i, o := range b.GetOutputs() {
+		raw, err := partContents(o, i, b.GetRawOutputContents())
+		if err != nil {
+			return nil, err
+		}
+
+		j, ok := idx[o.GetName()]
+		if !ok {
+			idx[o.GetName()] = len(res.Outputs)
+			res.Outputs = append(res.Outputs, o)
+			res.RawOutputContents = append(res.RawOutputContents, slices.Clone(raw))
+
+			continue
+		}
+
+		shape, err := mergedShape(res.Outputs[j], o)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Outputs[j] = reshaped{res.Outputs[j], shape}
+		res.RawOutputContents[j] = append(res.RawOutputContents[j], raw...)
+	}
+
+	return res, nil
+}
+
+// partContents returns raw contents of output i of a partial response.
+func partContents(output TritonModelInferResponseOutputs, i int, rawBytes [][]byte) ([]byte, error) {
+	if params, ok := getParameters(output); ok && params[shmRegionParam] != nil {
+		return nil, fmt.Errorf("output %s: merge of outputs in shared memory is not supported", output.GetName())
+	}
+
+	if i >= len(rawBytes) {
+		return nil, fmt.Errorf("output %s: raw contents are missing", output.GetName())
+	}
+
+	return rawBytes[i], nil
+}
+
+// mergedShape returns shape of output a joined with output b along the first dimension.
+func mergedShape(a, b TritonModelInferResponseOutputs) ([]int64, error) {
+	if a.GetDatatype() != b.GetDatatype() {
+		return nil, fmt.Errorf("output %s: types doesn't match exp: %s got: %s", a.GetName(), a.GetDatatype(), b.GetDatatype())
+	}
+
+	sa, sb := a.GetShape(), b.GetShape()
+	if len(sa) == 0 || len(sa) != len(sb) || !slices.Equal(sa[1:], sb[1:]) {
+		return nil, fmt.Errorf("output %s: shape %v can't be merged with shape %v", a.GetName(), sa, sb)
+	}
+
+	if slices.Min(sa) < 0 || slices.Min(sb) < 0 {
+		return nil, fmt.Errorf("output %s: invalid shapes: %v and %v", a.GetName(), sa, sb)
+	}
+
+	count := sa[0]
+	for _, dim := range sa[1:] {
+		count *= dim
+	}
+
+	// packed elements of odd count would share a byte with elements of b.
+	if (a.GetDatatype() == INT4 || a.GetDatatype() == UINT4) && count%2 != 0 {
+		return nil, fmt.Errorf("output %s: %s output of odd number of elements can't be merged", a.GetName(), a.GetDatatype())
+	}
+
+	shape := slices.Clone(sa)
+	shape[0] += sb[0]
+
+	return shape, nil
+}
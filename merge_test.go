@@ -0,0 +1,174 @@
+package tritonparser
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	type result struct {
+		Model  string    `triton:"_model_name"`
+		Tokens []int32   `triton:"tokens"`
+		Boxes  [][]int64 `triton:"boxes"`
+		Text   []string  `triton:"text"`
+		Extra  []float32 `triton:"extra"`
+	}
+
+	aTokens := int32Bytes(1, 2)
+	a := &modelResponse{
+		testResponse: testResponse{
+			outputs: []*testOutput{
+				{name: "tokens", datatype: INT32, shape: []int64{2}},
+				{name: "boxes", datatype: INT64, shape: []int64{1, 2}},
+				{name: "text", datatype: STRING, shape: []int64{1}},
+			},
+			raw: [][]byte{aTokens, int64Bytes(1, 2), encodeStrings("he")},
+		},
+		name: "llm",
+	}
+	b := &testResponse{
+		outputs: []*testOutput{
+			{name: "text", datatype: STRING, shape: []int64{2}},
+			{name: "extra", datatype: FLOAT32, shape: []int64{1, 1}},
+			{name: "tokens", datatype: INT32, shape: []int64{1}},
+			{name: "boxes", datatype: INT64, shape: []int64{2, 2}},
+		},
+		raw: [][]byte{encodeStrings("ll", "o"), float32Bytes(0.5), int32Bytes(3), int64Bytes(3, 4, 5, 6)},
+	}
+	c := &testResponse{
+		outputs: []*testOutput{{name: "tokens", datatype: INT32, shape: []int64{0}}},
+		raw:     [][]byte{{}},
+	}
+
+	ab, err := Merge(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := Merge(ab, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var res result
+	if err := Unmarshal(merged, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Model:  "llm",
+		Tokens: []int32{1, 2, 3},
+		Boxes:  [][]int64{{1, 2}, {3, 4}, {5, 6}},
+		Text:   []string{"he", "ll", "o"},
+		Extra:  []float32{0.5},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+
+	if !bytes.Equal(aTokens, int32Bytes(1, 2)) || !reflect.DeepEqual(a.outputs[0].shape, []int64{2}) {
+		t.Errorf("partial response is modified: %v of shape %v", aTokens, a.outputs[0].shape)
+	}
+}
+
+func TestMergeErrorMessage(t *testing.T) {
+	failed := &Response{ErrorMessage: "model failed"}
+
+	merged, err := Merge(&Response{}, failed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if merged.ErrorMessage != "model failed" {
+		t.Errorf("got error message %q, want %q", merged.ErrorMessage, "model failed")
+	}
+
+	if merged, _ = Merge(&Response{ErrorMessage: "first"}, failed); merged.ErrorMessage != "first" {
+		t.Errorf("got error message %q, want %q", merged.ErrorMessage, "first")
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	// part is partial response with the only output t.
+	part := func(datatype string, shape []int64, raw []byte) *testResponse {
+		return &testResponse{outputs: []*testOutput{{name: "t", datatype: datatype, shape: shape}}, raw: [][]byte{raw}}
+	}
+
+	tests := []struct {
+		name string
+		a, b *testResponse
+		want string
+	}{
+		{
+			name: "datatype",
+			a:    part(INT32, []int64{1}, int32Bytes(1)),
+			b:    part(INT64, []int64{1}, int64Bytes(1)),
+			want: "output t: types doesn't match exp: INT32 got: INT64",
+		},
+		{
+			name: "inner dimension",
+			a:    part(INT32, []int64{1, 2}, int32Bytes(1, 2)),
+			b:    part(INT32, []int64{1, 1}, int32Bytes(1)),
+			want: "output t: shape [1 2] can't be merged with shape [1 1]",
+		},
+		{
+			name: "rank",
+			a:    part(INT32, []int64{2}, int32Bytes(1, 2)),
+			b:    part(INT32, []int64{1, 1}, int32Bytes(1)),
+			want: "output t: shape [2] can't be merged with shape [1 1]",
+		},
+		{
+			name: "scalar shape",
+			a:    part(INT32, []int64{}, int32Bytes(1)),
+			b:    part(INT32, []int64{}, int32Bytes(1)),
+			want: "output t: shape [] can't be merged with shape []",
+		},
+		{
+			name: "negative dimension",
+			a:    part(INT32, []int64{1}, int32Bytes(1)),
+			b:    part(INT32, []int64{-1}, []byte{}),
+			want: "output t: invalid shapes: [1] and [-1]",
+		},
+		{
+			name: "odd packed",
+			a:    part(INT4, []int64{3}, []byte{0x21, 0x03}),
+			b:    part(INT4, []int64{1}, []byte{0x04}),
+			want: "output t: INT4 output of odd number of elements can't be merged",
+		},
+		{
+			name: "missing raw contents of a",
+			a:    &testResponse{outputs: []*testOutput{{name: "t", datatype: INT32, shape: []int64{1}}}},
+			b:    &testResponse{},
+			want: "output t: raw contents are missing",
+		},
+		{
+			name: "missing raw contents of b",
+			a:    &testResponse{},
+			b:    &testResponse{outputs: []*testOutput{{name: "u", datatype: INT32, shape: []int64{1}}}},
+			want: "output u: raw contents are missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Merge(tt.a, tt.b)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSharedMemory(t *testing.T) {
+	output := &httpOutput{
+		Name: "t", Datatype: INT32, Shape: []int64{1},
+		Parameters: map[string]any{shmRegionParam: "region", shmByteSizeParam: float64(4)},
+	}
+
+	_, err := Merge(&Response{Outputs: []TritonModelInferResponseOutputs{output}, RawOutputContents: [][]byte{nil}}, &Response{})
+	if want := "output t: merge of outputs in shared memory is not supported"; err == nil || err.Error() != want {
+		t.Fatalf("got error %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,108 @@
+package tritonparser
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// parseSentinel parses sentinel of missing option into value of numeric type t.
+// Float sentinel may be NaN.
+func parseSentinel(t reflect.Type, s string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+
+	switch k := t.Kind(); {
+	case isInt(k):
+		n, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		v.SetInt(n)
+	case isUint(k):
+		n, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		v.SetUint(n)
+	case isFloat(k):
+		n, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		v.SetFloat(n)
+	default:
+		return reflect.Value{}, fmt.Errorf("numeric field required, got %s", t)
+	}
+
+	return v, nil
+}
+
+// isSentinel reports whether v equals sentinel. NaN sentinel matches any NaN.
+func isSentinel(v, sentinel reflect.Value) bool {
+	if isFloat(v.Kind()) && math.IsNaN(sentinel.Float()) {
+		return math.IsNaN(v.Float())
+	}
+
+	return v.Equal(sentinel)
+}
+
+// decodeMissing decodes output into slice field of pointers, leaving elements equal to sentinel nil.
+func decodeMissing(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options, sentinel string) error {
+	t, ok := pointedType(field.Type())
+	if !ok {
+		return fmt.Errorf("output %s: %s option requires scalar field or slice of pointers, got %s",
+			output.GetName(), tagMissing, field.Type())
+	}
+
+	elem := scalarType(field.Type())
+
+	s, err := parseSentinel(elem.Elem(), sentinel)
+	if err != nil {
+		return fmt.Errorf("output %s: %s option: %w", output.GetName(), tagMissing, err)
+	}
+
+	val := reflect.New(t).Elem()
+	if err := parse(map[string]reflect.Value{output.GetName(): val}, output, rawBytes, opts); err != nil {
+		return err
+	}
+
+	field.Set(mapElements(val, elem, func(v reflect.Value) reflect.Value {
+		if isSentinel(v, s) {
+			return reflect.Zero(elem)
+		}
+
+		p := reflect.New(elem.Elem())
+		p.Elem().Set(v)
+
+		return p
+	}))
+
+	return nil
+}
+
+// clearMissing zeroes scalar or atomic field equal to sentinel and reports whether it did.
+func clearMissing(field reflect.Value, output TritonModelInferResponseOutputs, sentinel string) (bool, error) {
+	v := field
+	if isAtomic(field) {
+		if v = loadAtomic(field); !v.IsValid() {
+			return false, nil
+		}
+	}
+
+	s, err := parseSentinel(v.Type(), sentinel)
+	if err != nil {
+		return false, fmt.Errorf("output %s: %s option: %w", output.GetName(), tagMissing, err)
+	}
+
+	if !isSentinel(v, s) {
+		return false, nil
+	}
+
+	zeroField(field)
+
+	return true, nil
+}
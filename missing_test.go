@@ -0,0 +1,137 @@
+package tritonparser
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestMissing(t *testing.T) {
+	var res struct {
+		IDs     []*int64      `triton:"ids,missing=-1"`
+		Matrix  [][]*int32    `triton:"matrix,missing=0"`
+		Scores  []*float32    `triton:"scores,missing=NaN"`
+		Min     []*int64      `triton:"min,missing=-9223372036854775808"`
+		Count   int64         `triton:"count,missing=-1"`
+		Present int64         `triton:"present,missing=-1"`
+		Null    sql.NullInt64 `triton:"null,missing=-1"`
+		Valid   sql.NullInt64 `triton:"valid,missing=-1"`
+	}
+
+	res.Count = 9
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "ids", datatype: INT64, shape: []int64{1, 3}},
+			{name: "matrix", datatype: INT32, shape: []int64{2, 2}},
+			{name: "scores", datatype: FLOAT32, shape: []int64{1, 2}},
+			{name: "min", datatype: INT64, shape: []int64{1, 2}},
+			{name: "count", datatype: INT64, shape: []int64{1}},
+			{name: "present", datatype: INT64, shape: []int64{1}},
+			{name: "null", datatype: INT64, shape: []int64{1}},
+			{name: "valid", datatype: INT64, shape: []int64{1}},
+		},
+		raw: [][]byte{
+			int64Bytes(5, -1, 6),
+			int32Bytes(0, 1, 2, 0),
+			float32Bytes(float32(math.NaN()), 0.5),
+			int64Bytes(math.MinInt64, 7),
+			int64Bytes(-1),
+			int64Bytes(8),
+			int64Bytes(-1),
+			int64Bytes(3),
+		},
+	}
+
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	switch {
+	case len(res.IDs) != 3 || *res.IDs[0] != 5 || res.IDs[1] != nil || *res.IDs[2] != 6:
+		t.Errorf("got ids %v", res.IDs)
+	case res.Matrix[0][0] != nil || *res.Matrix[0][1] != 1 || *res.Matrix[1][0] != 2 || res.Matrix[1][1] != nil:
+		t.Errorf("got matrix %v", res.Matrix)
+	case res.Scores[0] != nil || *res.Scores[1] != 0.5:
+		t.Errorf("got scores %v", res.Scores)
+	case res.Min[0] != nil || *res.Min[1] != 7:
+		t.Errorf("got min %v", res.Min)
+	case res.Count != 0 || res.Present != 8:
+		t.Errorf("got count %d and present %d, want 0 and 8", res.Count, res.Present)
+	case res.Null.Valid || res.Null.Int64 != 0:
+		t.Errorf("got null %+v, want invalid", res.Null)
+	case !res.Valid.Valid || res.Valid.Int64 != 3:
+		t.Errorf("got valid %+v, want 3", res.Valid)
+	}
+}
+
+func TestMissingErrors(t *testing.T) {
+	var res struct {
+		Syntax  []*int64  `triton:"syntax,missing=none"`
+		Range   []*int8   `triton:"range,missing=300"`
+		Plain   []int64   `triton:"plain,missing=-1"`
+		Strings []*string `triton:"strings,missing=-1"`
+		Scalar  int8      `triton:"scalar,missing=-1000"`
+		IDs     []*int64  `triton:"ids,missing=-1"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "syntax",
+			output: &testOutput{name: "syntax", datatype: INT64, shape: []int64{1, 1}},
+			raw:    int64Bytes(1),
+			want:   "output syntax: missing option: strconv.ParseInt",
+		},
+		{
+			name:   "range",
+			output: &testOutput{name: "range", datatype: INT8, shape: []int64{1, 1}},
+			raw:    []byte{1},
+			want:   "output range: missing option: strconv.ParseInt: parsing \"300\": value out of range",
+		},
+		{
+			name:   "slice without pointers",
+			output: &testOutput{name: "plain", datatype: INT64, shape: []int64{1, 1}},
+			raw:    int64Bytes(1),
+			want:   "output plain: missing option requires scalar field or slice of pointers, got []int64",
+		},
+		{
+			name:   "strings",
+			output: &testOutput{name: "strings", datatype: STRING, shape: []int64{1, 1}},
+			raw:    encodeStrings("-1"),
+			want:   "output strings: missing option: numeric field required, got string",
+		},
+		{
+			name:   "scalar range",
+			output: &testOutput{name: "scalar", datatype: INT8, shape: []int64{1}},
+			raw:    []byte{1},
+			want:   "output scalar: missing option: strconv.ParseInt",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "ids", datatype: INT64, shape: []int64{1, 2}},
+			raw:    int64Bytes(1, 2)[:12],
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "datatype",
+			output: &testOutput{name: "ids", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(1),
+			want:   "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
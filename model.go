@@ -0,0 +1,49 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Names of fields receiving model name and version of response.
+const (
+	modelNameField    = "_model_name"
+	modelVersionField = "_model_version"
+)
+
+// TritonModelInfo is implemented by responses that carry name and version of model,
+// e.g. generated ModelInferResponse.
+type TritonModelInfo interface {
+	GetModelName() string
+	GetModelVersion() string
+}
+
+// setModelInfo stores model name and version of inferResponse into string fields
+// tagged with _model_name and _model_version, and reports names of set fields.
+func setModelInfo(inferResponse any, fieldMap map[string]reflect.Value) ([]string, error) {
+	info, ok := inferResponse.(TritonModelInfo)
+	if !ok {
+		return nil, nil
+	}
+
+	var set []string
+	for _, f := range [...]struct{ name, val string }{
+		{modelNameField, info.GetModelName()},
+		{modelVersionField, info.GetModelVersion()},
+	} {
+		name, val := f.name, f.val
+		field, ok := fieldMap[name]
+		if !ok {
+			continue
+		}
+
+		if field.Kind() != reflect.String {
+			return nil, fmt.Errorf("%s requires string field, got %s", name, field.Type())
+		}
+
+		field.SetString(val)
+		set = append(set, name)
+	}
+
+	return set, nil
+}
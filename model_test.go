@@ -0,0 +1,113 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// modelResponse is testResponse carrying model name and version.
+type modelResponse struct {
+	testResponse
+	name, version string
+}
+
+func (r *modelResponse) GetModelName() string    { return r.name }
+func (r *modelResponse) GetModelVersion() string { return r.version }
+
+func TestModelInfo(t *testing.T) {
+	type result struct {
+		Model   string `triton:"_model_name"`
+		Version string `triton:"_model_version"`
+		Score   int32  `triton:"score"`
+	}
+
+	outputs := []*testOutput{{name: "score", datatype: INT32, shape: []int64{1}}}
+	raw := [][]byte{int32Bytes(3)}
+
+	tests := []struct {
+		name string
+		resp TritonModelInferResponse[*testOutput]
+		want result
+	}{
+		{
+			name: "model info",
+			resp: &modelResponse{testResponse: testResponse{outputs: outputs, raw: raw}, name: "resnet", version: "2"},
+			want: result{Model: "resnet", Version: "2", Score: 3},
+		},
+		{
+			name: "empty version",
+			resp: &modelResponse{testResponse: testResponse{outputs: outputs, raw: raw}, name: "resnet"},
+			want: result{Model: "resnet", Score: 3},
+		},
+		{
+			name: "without model info",
+			resp: &testResponse{outputs: outputs, raw: raw},
+			want: result{Model: "kept", Version: "kept", Score: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := result{Model: "kept", Version: "kept"}
+			if err := Unmarshal(tt.resp, &res); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResponseModelInfo(t *testing.T) {
+	resp := NewResponse[*testOutput](&modelResponse{name: "resnet", version: "2"})
+	if resp.GetModelName() != "resnet" || resp.GetModelVersion() != "2" {
+		t.Errorf("got model %q version %q, want resnet 2", resp.GetModelName(), resp.GetModelVersion())
+	}
+
+	var res struct {
+		Model string `triton:"_model_name"`
+	}
+
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Model != "resnet" {
+		t.Errorf("got model %q, want resnet", res.Model)
+	}
+}
+
+func TestModelInfoErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  any
+		want string
+	}{
+		{
+			name: "name",
+			dst: &struct {
+				Model int `triton:"_model_name"`
+			}{},
+			want: "_model_name requires string field, got int",
+		},
+		{
+			name: "version",
+			dst: &struct {
+				Version []byte `triton:"_model_version"`
+			}{},
+			want: "_model_version requires string field, got []uint8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal(&modelResponse{name: "resnet", version: "2"}, tt.dst)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
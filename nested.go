@@ -0,0 +1,81 @@
+package tritonparser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// decodeNested decodes serialized inner responses of STRING output into struct, pointer to struct
+// or slice of them with Options.NestedDecoder.
+func decodeNested(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	if opts.NestedDecoder == nil {
+		return fmt.Errorf("output %s: %s option requires NestedDecoder", output.GetName(), tagNested)
+	}
+
+	if output.GetDatatype() != STRING {
+		return fmt.Errorf("output %s: %s option requires %s datatype, got %s", output.GetName(), tagNested, STRING, output.GetDatatype())
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case val.Kind() == reflect.String && field.Kind() != reflect.Slice:
+		return unmarshalNested(field, val.String(), opts)
+	case val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.String && field.Kind() == reflect.Slice:
+		res := reflect.MakeSlice(field.Type(), val.Len(), val.Len())
+		for i := 0; i < val.Len(); i++ {
+			if err := unmarshalNested(res.Index(i), val.Index(i).String(), opts); err != nil {
+				return fmt.Errorf("output %s[%d]: %w", output.GetName(), i, err)
+			}
+		}
+
+		field.Set(res)
+
+		return nil
+	default:
+		return fmt.Errorf("output %s: can't decode %s into %s", output.GetName(), val.Type(), field.Type())
+	}
+}
+
+func unmarshalNested(field reflect.Value, raw string, opts *Options) error {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+
+		field = field.Elem()
+	}
+
+	if field.Kind() != reflect.Struct {
+		return fmt.Errorf("nested field must be struct, got %s", field.Type())
+	}
+
+	inner, err := opts.NestedDecoder([]byte(raw))
+	if err != nil {
+		return fmt.Errorf("nested decode failed: %w", err)
+	}
+
+	if inner == nil {
+		return errors.New("nested decode returned nil response")
+	}
+
+	limit := opts.MaxNestedDepth
+	if limit == 0 {
+		limit = DefaultMaxNestedDepth
+	}
+
+	if opts.depth >= limit {
+		return fmt.Errorf("nested response exceeds max depth %d", limit)
+	}
+
+	// filters, offsets and stats refer to outputs of outer response.
+	innerOpts := *opts
+	innerOpts.Only, innerOpts.Skip, innerOpts.Offsets, innerOpts.Stats = nil, nil, nil, nil
+	innerOpts.depth++
+
+	return unmarshal[TritonModelInferResponseOutputs](inner, field.Addr(), &innerOpts)
+}
@@ -0,0 +1,268 @@
+package tritonparser
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type nestedStep struct {
+	Score int32 `triton:"score"`
+}
+
+// nestedResponses returns NestedDecoder looking serialized responses up by contents.
+func nestedResponses() func(raw []byte) (*Response, error) {
+	responses := map[string]*Response{
+		"one": {
+			Outputs:           []TritonModelInferResponseOutputs{&httpOutput{Name: "score", Datatype: INT32, Shape: []int64{1}}},
+			RawOutputContents: [][]byte{int32Bytes(1)},
+		},
+		"two": {
+			Outputs:           []TritonModelInferResponseOutputs{&httpOutput{Name: "score", Datatype: INT32, Shape: []int64{1}}},
+			RawOutputContents: [][]byte{int32Bytes(2)},
+		},
+		"malformed": {
+			Outputs:           []TritonModelInferResponseOutputs{&httpOutput{Name: "score", Datatype: INT32, Shape: []int64{1}}},
+			RawOutputContents: [][]byte{{1}},
+		},
+		"nil": nil,
+	}
+
+	return func(raw []byte) (*Response, error) {
+		r, ok := responses[string(raw)]
+		if !ok {
+			return nil, errors.New("corrupt")
+		}
+
+		return r, nil
+	}
+}
+
+func TestNested(t *testing.T) {
+	type result struct {
+		Step  nestedStep    `triton:"step,nested"`
+		Ptr   *nestedStep   `triton:"ptr,nested"`
+		Steps []nestedStep  `triton:"steps,nested"`
+		Ptrs  []*nestedStep `triton:"ptrs,nested"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "step", datatype: STRING, shape: []int64{1}},
+			{name: "ptr", datatype: STRING, shape: []int64{1}},
+			{name: "steps", datatype: STRING, shape: []int64{1, 2}},
+			{name: "ptrs", datatype: STRING, shape: []int64{1, 1}},
+		},
+		raw: [][]byte{encodeStrings("one"), encodeStrings("two"), encodeStrings("two", "one"), encodeStrings("one")},
+	}
+
+	var res result
+	if err := UnmarshalWithOptions(resp, &res, Options{NestedDecoder: nestedResponses()}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Step:  nestedStep{Score: 1},
+		Ptr:   &nestedStep{Score: 2},
+		Steps: []nestedStep{{Score: 2}, {Score: 1}},
+		Ptrs:  []*nestedStep{{Score: 1}},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestNestedErrors(t *testing.T) {
+	var res struct {
+		Step  nestedStep   `triton:"step,nested"`
+		Steps []nestedStep `triton:"steps,nested"`
+		Int   int32        `triton:"int,nested"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		opts   Options
+		want   string
+	}{
+		{
+			name:   "no decoder",
+			output: &testOutput{name: "step", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("one"),
+			want:   "requires NestedDecoder",
+		},
+		{
+			name:   "datatype",
+			output: &testOutput{name: "step", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			opts:   Options{NestedDecoder: nestedResponses()},
+			want:   "requires BYTES datatype",
+		},
+		{
+			name:   "decoder",
+			output: &testOutput{name: "step", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("three"),
+			opts:   Options{NestedDecoder: nestedResponses()},
+			want:   "nested decode failed: corrupt",
+		},
+		{
+			name:   "nil response",
+			output: &testOutput{name: "step", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("nil"),
+			opts:   Options{NestedDecoder: nestedResponses()},
+			want:   "nil response",
+		},
+		{
+			name:   "inner contents",
+			output: &testOutput{name: "steps", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("one", "malformed"),
+			opts:   Options{NestedDecoder: nestedResponses()},
+			want:   "output steps[1]",
+		},
+		{
+			name:   "field type",
+			output: &testOutput{name: "int", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("one"),
+			opts:   Options{NestedDecoder: nestedResponses()},
+			want:   "nested field must be struct",
+		},
+		{
+			name:   "shape",
+			output: &testOutput{name: "step", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("one", "two"),
+			opts:   Options{NestedDecoder: nestedResponses()},
+			want:   "can't decode",
+		},
+		{
+			name:   "outer contents",
+			output: &testOutput{name: "step", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("one")[:5],
+			opts:   Options{NestedDecoder: nestedResponses()},
+			want:   "unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+type nestedNode struct {
+	Value int32       `triton:"value"`
+	Child *nestedNode `triton:"child,nested"`
+}
+
+// chainResponses returns NestedDecoder of responses whose value counts down to zero,
+// each but the last nesting the next one. Contents "loop" nest themselves.
+func chainResponses() func(raw []byte) (*Response, error) {
+	return func(raw []byte) (*Response, error) {
+		if string(raw) == "loop" {
+			return &Response{
+				Outputs: []TritonModelInferResponseOutputs{
+					&httpOutput{Name: "value", Datatype: INT32, Shape: []int64{1}},
+					&httpOutput{Name: "child", Datatype: STRING, Shape: []int64{1}},
+				},
+				RawOutputContents: [][]byte{int32Bytes(0), encodeStrings("loop")},
+			}, nil
+		}
+
+		n, err := strconv.Atoi(string(raw))
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &Response{
+			Outputs:           []TritonModelInferResponseOutputs{&httpOutput{Name: "value", Datatype: INT32, Shape: []int64{1}}},
+			RawOutputContents: [][]byte{int32Bytes(int32(n))},
+		}
+
+		if n > 0 {
+			resp.Outputs = append(resp.Outputs, &httpOutput{Name: "child", Datatype: STRING, Shape: []int64{1}})
+			resp.RawOutputContents = append(resp.RawOutputContents, encodeStrings(strconv.Itoa(n-1)))
+		}
+
+		return resp, nil
+	}
+}
+
+func TestMaxNestedDepth(t *testing.T) {
+	tests := []struct {
+		name  string
+		root  string
+		limit int
+		want  int
+	}{
+		{name: "no nesting", root: "0", want: 1},
+		{name: "default limit", root: "31", want: DefaultMaxNestedDepth},
+		{name: "configured limit", root: "2", limit: 3, want: 3},
+		{name: "above default limit", root: "40", limit: 50, want: 41},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "child", datatype: STRING, shape: []int64{1}}},
+				raw:     [][]byte{encodeStrings(tt.root)},
+			}
+
+			var res nestedNode
+
+			opts := Options{NestedDecoder: chainResponses(), MaxNestedDepth: tt.limit}
+			if err := UnmarshalWithOptions(resp, &res, opts); err != nil {
+				t.Fatal(err)
+			}
+
+			depth := 0
+			for n := res.Child; n != nil; n = n.Child {
+				if want := int32(tt.want - depth - 1); n.Value != want {
+					t.Fatalf("got value %d at depth %d, want %d", n.Value, depth, want)
+				}
+
+				depth++
+			}
+
+			if depth != tt.want {
+				t.Errorf("got depth %d, want %d", depth, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxNestedDepthErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		root  string
+		limit int
+		want  string
+	}{
+		{name: "self nesting", root: "loop", want: "nested response exceeds max depth 32"},
+		{name: "default limit", root: "32", want: "nested response exceeds max depth 32"},
+		{name: "configured limit", root: "3", limit: 3, want: "nested response exceeds max depth 3"},
+		{name: "malformed inner contents", root: "x", want: "nested decode failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "child", datatype: STRING, shape: []int64{1}}},
+				raw:     [][]byte{encodeStrings(tt.root)},
+			}
+
+			var res nestedNode
+
+			err := UnmarshalWithOptions(resp, &res, Options{NestedDecoder: chainResponses(), MaxNestedDepth: tt.limit})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package tritonparser
+
+import (
+	"reflect"
+)
+
+const validField = "Valid"
+
+// nullableFields returns value and validity fields of sql.Null-style wrapper.
+// Wrapper is a struct of two exported fields, one of them is "Valid bool",
+// e.g. sql.NullFloat64, sql.Null[T] or struct{ Value T; Valid bool }.
+func nullableFields(v reflect.Value) (reflect.Value, reflect.Value, bool) {
+	t := v.Type()
+	if t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return reflect.Value{}, reflect.Value{}, false
+	}
+
+	valid, ok := t.FieldByName(validField)
+	if !ok || valid.Type.Kind() != reflect.Bool {
+		return reflect.Value{}, reflect.Value{}, false
+	}
+
+	value := t.Field(1 - valid.Index[0])
+	if !value.IsExported() || !valid.IsExported() {
+		return reflect.Value{}, reflect.Value{}, false
+	}
+
+	return v.FieldByIndex(value.Index), v.FieldByIndex(valid.Index), true
+}
+
+// unwrapNullable replaces sql.Null-style wrappers in fieldMap with their value fields
+// and returns validity fields by output name.
+func unwrapNullable(fieldMap map[string]reflect.Value) map[string]reflect.Value {
+	res := make(map[string]reflect.Value)
+
+	for name, v := range fieldMap {
+		value, valid, ok := nullableFields(v)
+		if !ok {
+			continue
+		}
+
+		fieldMap[name] = value
+		res[name] = valid
+	}
+
+	return res
+}
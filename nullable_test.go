@@ -0,0 +1,104 @@
+package tritonparser
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type nullableLabel struct {
+	Valid bool
+	Value string
+}
+
+type nullableResult struct {
+	Score sql.NullFloat64   `triton:"score"`
+	Class sql.Null[int32]   `triton:"class"`
+	Label nullableLabel     `triton:"label"`
+	Ids   sql.Null[[]int64] `triton:"ids"`
+}
+
+func TestNullable(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		want    nullableResult
+	}{
+		{
+			name: "all present",
+			outputs: []*testOutput{
+				{name: "score", datatype: FLOAT64, shape: []int64{1}},
+				{name: "class", datatype: INT32, shape: []int64{1}},
+				{name: "label", datatype: STRING, shape: []int64{1}},
+				{name: "ids", datatype: INT64, shape: []int64{1, 2}},
+			},
+			raw: [][]byte{
+				binary.LittleEndian.AppendUint64(nil, math.Float64bits(0.25)),
+				int32Bytes(3),
+				encodeStrings("cat"),
+				int64Bytes(1, 2),
+			},
+			want: nullableResult{
+				Score: sql.NullFloat64{Float64: 0.25, Valid: true},
+				Class: sql.Null[int32]{V: 3, Valid: true},
+				Label: nullableLabel{Value: "cat", Valid: true},
+				Ids:   sql.Null[[]int64]{V: []int64{1, 2}, Valid: true},
+			},
+		},
+		{
+			name:    "missing",
+			outputs: []*testOutput{{name: "class", datatype: INT32, shape: []int64{1}}},
+			raw:     [][]byte{int32Bytes(3)},
+			want:    nullableResult{Class: sql.Null[int32]{V: 3, Valid: true}},
+		},
+		{
+			name:    "no raw contents",
+			outputs: []*testOutput{{name: "class", datatype: INT32, shape: []int64{1}}},
+			raw:     [][]byte{nil},
+			want:    nullableResult{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := nullableResult{Class: sql.Null[int32]{V: 9, Valid: true}}
+			if err := Unmarshal(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestNullableErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+	}{
+		{name: "type", output: &testOutput{name: "class", datatype: STRING, shape: []int64{1}}, raw: encodeStrings("a")},
+		{name: "length", output: &testOutput{name: "class", datatype: INT32, shape: []int64{1}}, raw: []byte{1, 2}},
+		{name: "shape", output: &testOutput{name: "ids", datatype: INT64, shape: []int64{2, 2, 2}}, raw: int64Bytes(1, 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res nullableResult
+
+			resp := &testResponse{outputs: []*testOutput{tt.output}, raw: [][]byte{tt.raw}}
+			if err := Unmarshal(resp, &res); err == nil {
+				t.Fatalf("got nil error, result %+v", res)
+			}
+
+			if res.Class.Valid || res.Ids.Valid {
+				t.Errorf("got %+v, want invalid fields", res)
+			}
+		})
+	}
+}
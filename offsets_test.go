@@ -0,0 +1,71 @@
+package tritonparser
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestOffsets(t *testing.T) {
+	outputs := []*testOutput{
+		{name: "a", datatype: INT32, shape: []int64{1}},
+		{name: "unmatched", datatype: INT32, shape: []int64{1, 2}},
+		{name: "empty", datatype: INT32, shape: []int64{1, 0}},
+		{name: "s", datatype: STRING, shape: []int64{1}},
+	}
+
+	tests := []struct {
+		name string
+		raw  [][]byte
+		want map[string][2]int
+	}{
+		{
+			name: "all",
+			raw:  [][]byte{int32Bytes(1), int32Bytes(2, 3), {}, encodeStrings("ab")},
+			want: map[string][2]int{"a": {0, 4}, "unmatched": {4, 12}, "empty": {12, 12}, "s": {12, 18}},
+		},
+		{
+			name: "fewer contents",
+			raw:  [][]byte{int32Bytes(1), int32Bytes(2, 3)},
+			want: map[string][2]int{"a": {0, 4}, "unmatched": {4, 12}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res struct {
+				A int32  `triton:"a"`
+				S string `triton:"s"`
+			}
+
+			// offsets of previous response are overwritten.
+			offsets := map[string][2]int{"a": {7, 7}}
+
+			// response with fewer contents fails, but offsets are known before decoding.
+			_ = UnmarshalWithOptions(&testResponse{outputs: outputs, raw: tt.raw}, &res, Options{Offsets: offsets})
+
+			if !maps.Equal(offsets, tt.want) {
+				t.Errorf("got %v, want %v", offsets, tt.want)
+			}
+		})
+	}
+}
+
+func TestOffsetsMalformed(t *testing.T) {
+	var res struct {
+		A int32 `triton:"a"`
+	}
+
+	offsets := make(map[string][2]int)
+	resp := &testResponse{
+		outputs: []*testOutput{{name: "a", datatype: INT32, shape: []int64{1}}},
+		raw:     [][]byte{{1, 2}},
+	}
+
+	if err := UnmarshalWithOptions(resp, &res, Options{Offsets: offsets}); err == nil {
+		t.Fatal("got nil error")
+	}
+
+	if want := [2]int{0, 2}; offsets["a"] != want {
+		t.Errorf("got %v, want %v", offsets["a"], want)
+	}
+}
@@ -0,0 +1,228 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Compression selects how raw output contents are inflated before decoding.
+type Compression int
+
+const (
+	// CompressionNone leaves raw contents as is.
+	CompressionNone Compression = iota
+	// CompressionAuto detects gzip or zstd by magic bytes and leaves other contents as is.
+	CompressionAuto
+	// CompressionGzip inflates every raw content with gzip.
+	CompressionGzip
+	// CompressionZstd inflates every raw content with Options.ZstdDecoder.
+	CompressionZstd
+)
+
+// Options configures UnmarshalWithOptions.
+// Zero value is equivalent to Unmarshal.
+type Options struct {
+	// Decompress inflates raw output contents before decoding.
+	Decompress Compression
+	// ZstdDecoder is used for zstd compressed contents, as zstd is not a part of standard library.
+	ZstdDecoder func(b []byte) ([]byte, error)
+	// ColumnMajor reads multidimensional arrays in column-major (Fortran) order.
+	// One-dimensional outputs are unaffected.
+	ColumnMajor bool
+	// Coerce allows decoding numeric outputs into fields of other numeric types,
+	// e.g. INT64 output into int32 field. Integer values that would change sign,
+	// e.g. negative INT64 into uint64 field or UINT64 above math.MaxInt64 into int64 field, are rejected.
+	Coerce bool
+	// OnLossyConversion is called for every coerced output whose destination type
+	// may not represent all values of the output datatype.
+	// Returning error aborts decoding.
+	OnLossyConversion func(c LossyConversion) error
+	// OnTypeMismatch, if not nil, allows decoding numeric outputs into fields of other numeric types
+	// as Coerce does, e.g. INT32 output into int field, but values out of range of field type are rejected.
+	// It's called for every converted output, e.g. to log a warning.
+	OnTypeMismatch func(c LossyConversion)
+	// ByteOrder of raw contents. Triton uses little-endian, which is the default.
+	ByteOrder binary.ByteOrder
+	// ByteOrderFor resolves byte order of output by its name.
+	// Nil result falls back to ByteOrder.
+	ByteOrderFor func(name string) binary.ByteOrder
+	// ByteOrderSentinel, if set, names INT32 output of shape [1] holding ByteOrderMagic,
+	// from which byte order of response is detected. Detected order replaces ByteOrder and ByteOrderFor,
+	// but not byte order tag options. Responses without sentinel output keep configured byte order.
+	ByteOrderSentinel string
+	// ByteOrderMagic is the value of ByteOrderSentinel output. Its bytes must not be a palindrome.
+	ByteOrderMagic int32
+	// StringLengthPrefix is the width in bytes of length prefix of BYTES elements, 4 or 8.
+	// Zero means 4, as used by Triton.
+	StringLengthPrefix int
+	// InternStrings makes equal elements of BYTES outputs share memory, which pays off
+	// for outputs with many repeated labels. Otherwise elements of output share one copy of its contents.
+	InternStrings bool
+	// ZeroCopyBytes makes elements of BYTES outputs decoded into [][]byte fields reference raw contents
+	// instead of copying them. Raw contents must not be modified while decoded elements are used.
+	ZeroCopyBytes bool
+	// PositionalFallback matches outputs that have no field with the same name
+	// with the field declared at the same position as output in response.
+	// Fields that are matched by name with other outputs are never used as fallback.
+	// Tag options and nullable wrappers of fallback fields apply as if they were matched by name.
+	PositionalFallback bool
+	// Only restricts decoding to outputs with listed names, if not empty.
+	Only []string
+	// Skip lists names of outputs that are not decoded.
+	Skip []string
+	// Offsets, if not nil, is filled with [start, end) offsets of every output
+	// within concatenation of raw output contents, so they may be re-sliced without parsing.
+	Offsets map[string][2]int
+	// ChunkedRawContents allows raw contents of output to be split across consecutive entries
+	// of raw output contents, e.g. by chunking transport. Entries are joined until byte size of output
+	// is reached, as given by its binary_data_size parameter or implied by its datatype and shape.
+	// Compressed contents require binary_data_size parameter. Entries are used one per output if there are
+	// no more entries than outputs.
+	ChunkedRawContents bool
+	// SharedMemoryResolver reads contents of outputs returned in shared memory region,
+	// whose shared_memory_region, shared_memory_offset and shared_memory_byte_size parameters are set.
+	// Outputs must have GetParameters method, as generated InferOutputTensor does.
+	SharedMemoryResolver func(region string, offset, size int64) ([]byte, error)
+	// NestedDecoder deserializes inner response of output tagged with nested option,
+	// e.g. serialized ModelInferResponse of ensemble step. Use NewResponse to adapt decoded message.
+	NestedDecoder func(raw []byte) (*Response, error)
+	// MaxNestedDepth limits how deep responses may be nested within outputs tagged with nested option,
+	// e.g. for struct with field of its own type. It's DefaultMaxNestedDepth if zero.
+	MaxNestedDepth int
+	// ValidateBatchConsistency requires dimension BatchAxis of all decoded outputs to be the same,
+	// so batches of outputs are aligned.
+	ValidateBatchConsistency bool
+	// MaxElements, if positive, limits number of elements of decoded output, as given by its shape.
+	MaxElements int
+	// MaxBytes, if positive, limits size of raw contents of decoded output after decompression
+	// and the size its shape implies. Both guard against allocations requested by hostile responses.
+	MaxBytes int
+	// SqueezeBatch drops batch dimension of size 1 from outputs of rank above 2,
+	// e.g. [1, M, N] output is decoded as [M, N].
+	SqueezeBatch bool
+	// SqueezeTrailing drops trailing dimension of size 1 from outputs of rank 2 and above,
+	// e.g. [N, 1] output is decoded as [N] into []float32 field.
+	SqueezeTrailing bool
+	// BatchAxis is the batch dimension of outputs of rank 2 and above, 0 by default.
+	// It's checked by ValidateBatchConsistency and dropped by SqueezeBatch. Outputs of rank 2
+	// batched along axis 1, e.g. [N, B], are decoded transposed, one row per batch.
+	// Higher rank outputs keep their layout.
+	BatchAxis int
+	// Parallelism, if above 1, is the number of outputs decoded concurrently.
+	// OnLossyConversion, OnTypeMismatch, ByteOrderFor, NestedDecoder, ZstdDecoder and Trace
+	// must be safe for concurrent use then.
+	// Errors of all failed outputs are joined.
+	Parallelism int
+	// PerOutputTimeout, if positive, is the time budget for decoding of a single output.
+	// Decoding isn't interrupted, but output that exceeded budget fails with error
+	// naming it and wrapping context.DeadlineExceeded.
+	PerOutputTimeout time.Duration
+	// Trace, if not nil, receives a line for every decoding step: matched fields,
+	// datatypes and shapes of outputs, chosen decode paths and byte counts.
+	Trace io.Writer
+	// Stats, if not nil, is filled with total bytes and elements of decoded outputs
+	// and numbers of decoded, skipped and unmatched outputs.
+	Stats *DecodeStats
+	// TagResolver, if not nil, returns effective triton tag of struct field named fieldName
+	// instead of its static tag, e.g. to remap output names per model version.
+	// Tags of Binding are resolved once, when struct is bound.
+	TagResolver func(fieldName string, field reflect.StructField) string
+
+	// depth is the number of responses options are nested within.
+	depth int
+	// fixedByteOrder makes ByteOrder override byte order tag options,
+	// as raw contents are encoded by the package itself, e.g. from JSON data.
+	fixedByteOrder bool
+}
+
+// DefaultMaxNestedDepth is the limit of nesting depth used if Options.MaxNestedDepth is zero.
+const DefaultMaxNestedDepth = 32
+
+// selected reports whether output passes Only and Skip filters.
+func (o *Options) selected(output string) bool {
+	if len(o.Only) != 0 && !slices.Contains(o.Only, output) {
+		return false
+	}
+
+	return !slices.Contains(o.Skip, output)
+}
+
+// fieldTag returns triton tag of field, resolved with TagResolver if it's set.
+func (o *Options) fieldTag(field reflect.StructField) string {
+	if o.TagResolver != nil {
+		return o.TagResolver(field.Name, field)
+	}
+
+	return field.Tag.Get(tag)
+}
+
+func (o *Options) byteOrder(output string) binary.ByteOrder {
+	if o.ByteOrderFor != nil {
+		if order := o.ByteOrderFor(output); order != nil {
+			return order
+		}
+	}
+
+	if o.ByteOrder != nil {
+		return o.ByteOrder
+	}
+
+	return binary.LittleEndian
+}
+
+// tracef writes line to Trace, if it's set. Call sites check Trace first,
+// so arguments aren't boxed on hot paths when tracing is off.
+func (o *Options) tracef(format string, args ...any) {
+	if o.Trace == nil {
+		return
+	}
+
+	fmt.Fprintf(o.Trace, format+"\n", args...)
+}
+
+// checkLimits returns error if output exceeds MaxElements or MaxBytes.
+func (o *Options) checkLimits(output TritonModelInferResponseOutputs, rawBytes []byte) error {
+	if o.MaxElements <= 0 && o.MaxBytes <= 0 {
+		return nil
+	}
+
+	if o.MaxBytes > 0 && len(rawBytes) > o.MaxBytes {
+		return fmt.Errorf("output %s: raw contents length %d exceeds %d bytes", output.GetName(), len(rawBytes), o.MaxBytes)
+	}
+
+	count := int64(1)
+	for _, dim := range output.GetShape() {
+		if dim < 0 {
+			return fmt.Errorf("invalid shape: %v", output.GetShape())
+		}
+
+		if dim != 0 && count > math.MaxInt64/dim {
+			return fmt.Errorf("output %s: shape %v is too large", output.GetName(), output.GetShape())
+		}
+
+		count *= dim
+	}
+
+	if o.MaxElements > 0 && count > int64(o.MaxElements) {
+		return fmt.Errorf("output %s: %d elements exceed %d", output.GetName(), count, o.MaxElements)
+	}
+
+	if o.MaxBytes > 0 {
+		size, err := OutputByteSize(output.GetDatatype(), output.GetShape())
+		if err != nil && !errors.Is(err, ErrVariableSize) {
+			return fmt.Errorf("output %s: %w", output.GetName(), err)
+		}
+
+		if size > o.MaxBytes {
+			return fmt.Errorf("output %s: shape %v requires %d bytes, exceeding %d", output.GetName(), output.GetShape(), size, o.MaxBytes)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package tritonparser
+
+import (
+	"errors"
+	"sync"
+)
+
+// decodeJob is output queued for decoding with its raw contents.
+type decodeJob struct {
+	output TritonModelInferResponseOutputs
+	raw    []byte
+}
+
+// decodeParallel decodes jobs using up to Options.Parallelism goroutines.
+// Every output is decoded into its own field, so no field is set concurrently.
+// Errors of all outputs are joined in order of jobs.
+func decodeParallel(fs *fieldSet, jobs []decodeJob, opts *Options) error {
+	errs := make([]error, len(jobs))
+	sem := make(chan struct{}, opts.Parallelism)
+
+	var wg sync.WaitGroup
+	for j, job := range jobs {
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			errs[j] = decodeField(fs, job.output, job.raw, opts)
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
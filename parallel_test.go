@@ -0,0 +1,111 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type parallelResult struct {
+	A      []int32    `triton:"a"`
+	B      [][]int64  `triton:"b"`
+	C      float32    `triton:"c"`
+	D      []string   `triton:"d"`
+	E      [][]string `triton:"e"`
+	Wide   int64      `triton:"wide"`
+	Ignore int32      `triton:"-"`
+}
+
+func parallelResponse() *testResponse {
+	return &testResponse{
+		outputs: []*testOutput{
+			{name: "a", datatype: INT32, shape: []int64{1, 3}},
+			{name: "b", datatype: INT64, shape: []int64{2, 1}},
+			{name: "c", datatype: FLOAT32, shape: []int64{1}},
+			{name: "d", datatype: STRING, shape: []int64{1, 2}},
+			{name: "e", datatype: STRING, shape: []int64{2, 1}},
+			{name: "wide", datatype: INT32, shape: []int64{1}},
+			{name: "unmatched", datatype: INT32, shape: []int64{1}},
+		},
+		raw: [][]byte{
+			int32Bytes(1, 2, 3),
+			int64Bytes(4, 5),
+			float32Bytes(0.5),
+			encodeStrings("x", "y"),
+			encodeStrings("p", "q"),
+			int32Bytes(6),
+			int32Bytes(7),
+		},
+	}
+}
+
+func TestParallelism(t *testing.T) {
+	want := parallelResult{
+		A:    []int32{1, 2, 3},
+		B:    [][]int64{{4}, {5}},
+		C:    0.5,
+		D:    []string{"x", "y"},
+		E:    [][]string{{"p"}, {"q"}},
+		Wide: 6,
+	}
+
+	for _, parallelism := range []int{0, 1, 2, 4, 16} {
+		t.Run(fmt.Sprint(parallelism), func(t *testing.T) {
+			var (
+				mu         sync.Mutex
+				mismatches []string
+				res        parallelResult
+			)
+
+			opts := Options{Parallelism: parallelism, OnTypeMismatch: func(c LossyConversion) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				mismatches = append(mismatches, c.String())
+			}}
+
+			if err := UnmarshalWithOptions(parallelResponse(), &res, opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, want) {
+				t.Errorf("got %+v, want %+v", res, want)
+			}
+
+			if wantMismatches := []string{"output wide: int32 -> int64"}; !reflect.DeepEqual(mismatches, wantMismatches) {
+				t.Errorf("got mismatches %q, want %q", mismatches, wantMismatches)
+			}
+		})
+	}
+}
+
+func TestParallelismErrors(t *testing.T) {
+	resp := parallelResponse()
+	resp.raw[0] = int32Bytes(1, 2, 3)[:11]
+	resp.raw[3] = encodeStrings("x")
+	resp.raw[4] = encodeStrings("p", "q")[:6]
+
+	var res parallelResult
+
+	err := UnmarshalWithOptions(resp, &res, Options{Parallelism: 3})
+	if err == nil {
+		t.Fatal("got nil error")
+	}
+
+	// a, d and e are malformed, wide needs coercion.
+	if lines := strings.Split(err.Error(), "\n"); len(lines) != 4 {
+		t.Errorf("got %d errors, want 4: %v", len(lines), err)
+	}
+
+	for _, output := range []string{"output a", "output d"} {
+		if !strings.Contains(err.Error(), output) {
+			t.Errorf("error %q lacks %q", err, output)
+		}
+	}
+
+	if res.C != 0.5 || res.Wide != 0 {
+		t.Errorf("got %+v, want other outputs decoded", res)
+	}
+}
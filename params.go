@@ -0,0 +1,89 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+const parametersMethod = "GetParameters"
+
+// getParameters returns parameters of v if it has GetParameters method returning map with string keys,
+// e.g. InferOutputTensor of generated protobuf api. Values are unwrapped with parameterValue.
+func getParameters(v any) (map[string]any, bool) {
+	m := reflect.ValueOf(v).MethodByName(parametersMethod)
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil, false
+	}
+
+	if t := m.Type().Out(0); t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return nil, false
+	}
+
+	params := m.Call(nil)[0]
+	res := make(map[string]any, params.Len())
+
+	iter := params.MapRange()
+	for iter.Next() {
+		res[iter.Key().String()] = parameterValue(iter.Value())
+	}
+
+	return res, true
+}
+
+// parameterValue unwraps InferParameter into bool, int64, uint64, float64 or string.
+// Values that are not InferParameter are returned as is.
+func parameterValue(v reflect.Value) any {
+	if m := v.MethodByName("GetParameterChoice"); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+		// choice is oneof wrapper, e.g. *InferParameter_BoolParam{BoolParam: true}.
+		choice := m.Call(nil)[0]
+		for choice.Kind() == reflect.Interface || choice.Kind() == reflect.Pointer {
+			if choice.IsNil() {
+				return nil
+			}
+
+			choice = choice.Elem()
+		}
+
+		if choice.Kind() == reflect.Struct && choice.NumField() == 1 && choice.Type().Field(0).IsExported() {
+			return choice.Field(0).Interface()
+		}
+	}
+
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	return v.Interface()
+}
+
+// setParameters stores parameters of output into map[string]any field.
+func setParameters(field reflect.Value, output TritonModelInferResponseOutputs) error {
+	if field.Type() != reflect.TypeFor[map[string]any]() {
+		return fmt.Errorf("output %s: %s option requires map[string]any field, got %s", output.GetName(), tagParameters, field.Type())
+	}
+
+	params, ok := getParameters(output)
+	if !ok {
+		return fmt.Errorf("output %s: parameters are not supported by %T", output.GetName(), output)
+	}
+
+	field.Set(reflect.ValueOf(params))
+
+	return nil
+}
+
+// setShape stores shape of output into []int64 field.
+func setShape(field reflect.Value, output TritonModelInferResponseOutputs) error {
+	if field.Type() != reflect.TypeFor[[]int64]() {
+		return fmt.Errorf("output %s: %s option requires []int64 field, got %s", output.GetName(), tagShape, field.Type())
+	}
+
+	field.Set(reflect.ValueOf(slices.Clone(output.GetShape())))
+
+	return nil
+}
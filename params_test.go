@@ -0,0 +1,126 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// protoParameter mimics InferParameter of generated protobuf api.
+type protoParameter struct {
+	choice isProtoParameterChoice
+}
+
+type isProtoParameterChoice interface{ isChoice() }
+
+type protoParameterInt64 struct{ Int64Param int64 }
+
+type protoParameterString struct{ StringParam string }
+
+func (*protoParameterInt64) isChoice()  {}
+func (*protoParameterString) isChoice() {}
+
+func (p *protoParameter) GetParameterChoice() isProtoParameterChoice {
+	return p.choice
+}
+
+// protoOutput mimics InferOutputTensor of generated protobuf api.
+type protoOutput struct {
+	testOutput
+	params map[string]*protoParameter
+}
+
+func (o *protoOutput) GetParameters() map[string]*protoParameter {
+	return o.params
+}
+
+func TestParameters(t *testing.T) {
+	type result struct {
+		Score  int32          `triton:"score"`
+		Params map[string]any `triton:"score,parameters"`
+	}
+
+	tests := []struct {
+		name   string
+		output TritonModelInferResponseOutputs
+		want   map[string]any
+	}{
+		{
+			name: "http",
+			output: &httpOutput{
+				Name: "score", Datatype: INT32, Shape: []int64{1},
+				Parameters: map[string]any{"region": "a", "size": float64(4)},
+			},
+			want: map[string]any{"region": "a", "size": float64(4)},
+		},
+		{
+			name: "proto",
+			output: &protoOutput{
+				testOutput: testOutput{name: "score", datatype: INT32, shape: []int64{1}},
+				params: map[string]*protoParameter{
+					"size":   {choice: &protoParameterInt64{Int64Param: 4}},
+					"region": {choice: &protoParameterString{StringParam: "a"}},
+					"unset":  {},
+				},
+			},
+			want: map[string]any{"size": int64(4), "region": "a", "unset": nil},
+		},
+		{
+			name:   "none",
+			output: &httpOutput{Name: "score", Datatype: INT32, Shape: []int64{1}},
+			want:   map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &Response{Outputs: []TritonModelInferResponseOutputs{tt.output}, RawOutputContents: [][]byte{int32Bytes(7)}}
+
+			var res result
+			if err := Unmarshal(resp, &res); err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Score != 7 || !reflect.DeepEqual(res.Params, tt.want) {
+				t.Errorf("got %+v, want score 7 and parameters %v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestParametersErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		dst    any
+		output TritonModelInferResponseOutputs
+		want   string
+	}{
+		{
+			name: "field type",
+			dst: &struct {
+				Params map[string]string `triton:"score,parameters"`
+			}{},
+			output: &httpOutput{Name: "score", Datatype: INT32, Shape: []int64{1}},
+			want:   "requires map[string]any field",
+		},
+		{
+			name: "unsupported output",
+			dst: &struct {
+				Params map[string]any `triton:"score,parameters"`
+			}{},
+			output: &testOutput{name: "score", datatype: INT32, shape: []int64{1}},
+			want:   "parameters are not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &Response{Outputs: []TritonModelInferResponseOutputs{tt.output}, RawOutputContents: [][]byte{int32Bytes(7)}}
+
+			err := Unmarshal(resp, tt.dst)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package tritonparser
+
+import (
+	"reflect"
+)
+
+// pointedType returns type of possibly nested slice t with its pointer elements
+// replaced by pointed values, e.g. []float32 for []*float32.
+func pointedType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Slice {
+		return nil, false
+	}
+
+	elem := t.Elem()
+	if elem.Kind() == reflect.Pointer {
+		return reflect.SliceOf(elem.Elem()), true
+	}
+
+	inner, ok := pointedType(elem)
+	if !ok {
+		return nil, false
+	}
+
+	return reflect.SliceOf(inner), true
+}
+
+// unmarshalPointers decodes output into slice field of pointers, allocating every element.
+func unmarshalPointers(field reflect.Value, t reflect.Type, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	val := reflect.New(t).Elem()
+	if err := parse(map[string]reflect.Value{output.GetName(): val}, output, rawBytes, opts); err != nil {
+		return err
+	}
+
+	elem := scalarType(field.Type())
+	field.Set(mapElements(val, elem, func(v reflect.Value) reflect.Value {
+		p := reflect.New(elem.Elem())
+		p.Elem().Set(v)
+
+		return p
+	}))
+
+	return nil
+}
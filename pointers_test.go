@@ -0,0 +1,100 @@
+package tritonparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPointers(t *testing.T) {
+	var res struct {
+		Scores []*float32 `triton:"scores"`
+		Matrix [][]*int32 `triton:"matrix"`
+		Labels []*string  `triton:"labels"`
+		Named  []*label   `triton:"named"`
+		Empty  []*float32 `triton:"empty"`
+		Double []**uint8  `triton:"double"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "scores", datatype: FLOAT32, shape: []int64{1, 2}},
+			{name: "matrix", datatype: INT32, shape: []int64{2, 1}},
+			{name: "labels", datatype: STRING, shape: []int64{2}},
+			{name: "named", datatype: INT32, shape: []int64{1, 1}},
+			{name: "empty", datatype: FLOAT32, shape: []int64{1, 0}},
+			{name: "double", datatype: UINT8, shape: []int64{1, 1}},
+		},
+		raw: [][]byte{float32Bytes(0.5, 0.25), int32Bytes(1, 2), encodeStrings("a", "b"), int32Bytes(3), {}, {4}},
+	}
+
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	deref := func(ps []*float32) []float32 {
+		res := make([]float32, len(ps))
+		for i, p := range ps {
+			res[i] = *p
+		}
+
+		return res
+	}
+
+	switch {
+	case len(res.Scores) != 2 || deref(res.Scores)[0] != 0.5 || deref(res.Scores)[1] != 0.25:
+		t.Errorf("got scores %v", deref(res.Scores))
+	case res.Scores[0] == res.Scores[1]:
+		t.Error("elements share pointer")
+	case len(res.Matrix) != 2 || *res.Matrix[0][0] != 1 || *res.Matrix[1][0] != 2:
+		t.Errorf("got matrix %v", res.Matrix)
+	case len(res.Labels) != 2 || *res.Labels[0] != "a" || *res.Labels[1] != "b":
+		t.Errorf("got labels %v", res.Labels)
+	case len(res.Named) != 1 || *res.Named[0] != 3:
+		t.Errorf("got named %v", res.Named)
+	case res.Empty == nil || len(res.Empty) != 0:
+		t.Errorf("got empty %v, want empty slice", res.Empty)
+	case len(res.Double) != 1 || **res.Double[0] != 4:
+		t.Errorf("got double %v", res.Double)
+	}
+}
+
+func TestPointersErrors(t *testing.T) {
+	var res struct {
+		Scores []*float32 `triton:"scores"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "datatype",
+			output: &testOutput{name: "scores", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(1),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "matrix into slice",
+			output: &testOutput{name: "scores", datatype: FLOAT32, shape: []int64{2, 1}},
+			raw:    float32Bytes(1, 2),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "scores", datatype: FLOAT32, shape: []int64{1, 2}},
+			raw:    float32Bytes(1, 2)[:7],
+			want:   "not a multiple of element size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package tritonparser
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestPositionalFallback(t *testing.T) {
+	type result struct {
+		Score sql.Null[int32] `triton:"score"`
+		Count int32           `triton:"count,missing=-1"`
+		Label string          `triton:"label"`
+	}
+
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		want    result
+	}{
+		{
+			name: "nullable",
+			outputs: []*testOutput{
+				{name: "x", datatype: INT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{int32Bytes(7)},
+			want: result{Score: sql.Null[int32]{V: 7, Valid: true}},
+		},
+		{
+			name: "missing sentinel",
+			outputs: []*testOutput{
+				{name: "score", datatype: INT32, shape: []int64{1}},
+				{name: "y", datatype: INT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{int32Bytes(1), int32Bytes(-1)},
+			want: result{Score: sql.Null[int32]{V: 1, Valid: true}},
+		},
+		{
+			name: "matched by name",
+			outputs: []*testOutput{
+				{name: "x", datatype: INT32, shape: []int64{1}},
+				{name: "y", datatype: INT32, shape: []int64{1}},
+				{name: "score", datatype: INT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{int32Bytes(1), int32Bytes(2), int32Bytes(3)},
+			want: result{Score: sql.Null[int32]{V: 3, Valid: true}, Count: 2},
+		},
+		{
+			name: "out of fields",
+			outputs: []*testOutput{
+				{name: "score", datatype: INT32, shape: []int64{1}},
+				{name: "count", datatype: INT32, shape: []int64{1}},
+				{name: "label", datatype: STRING, shape: []int64{1}},
+				{name: "z", datatype: INT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{int32Bytes(1), int32Bytes(2), encodeStrings("a"), int32Bytes(4)},
+			want: result{Score: sql.Null[int32]{V: 1, Valid: true}, Count: 2, Label: "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res result
+
+			resp := &testResponse{outputs: tt.outputs, raw: tt.raw}
+			if err := UnmarshalWithOptions(resp, &res, Options{PositionalFallback: true}); err != nil {
+				t.Fatal(err)
+			}
+
+			if res != tt.want {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestPositionalFallbackErrors(t *testing.T) {
+	var res struct {
+		Score int32 `triton:"score"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+	}{
+		{name: "type", output: &testOutput{name: "x", datatype: STRING, shape: []int64{1}}, raw: encodeStrings("a")},
+		{name: "length", output: &testOutput{name: "x", datatype: INT32, shape: []int64{1}}, raw: []byte{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{outputs: []*testOutput{tt.output}, raw: [][]byte{tt.raw}}
+			if err := UnmarshalWithOptions(resp, &res, Options{PositionalFallback: true}); err == nil {
+				t.Fatal("got nil error")
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+//go:build protobuf
+
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// UnmarshalProto decodes outputs of inferResponse into fields of protobuf message msg.
+// Outputs are matched with fields by proto or JSON name. Singular fields receive scalar outputs,
+// repeated fields receive array outputs flattened in row-major order.
+//
+// UnmarshalProto is available with "protobuf" build tag.
+func UnmarshalProto[T TritonModelInferResponseOutputs](
+	inferResponse TritonModelInferResponse[T],
+	msg proto.Message,
+	opts Options,
+) error {
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	outputs := inferResponse.GetOutputs()
+
+	if err := responseError(inferResponse); err != nil {
+		return err
+	}
+
+	rawBytes, err := joinChunks(outputs, inferResponse.GetRawOutputContents(), &opts)
+	if err != nil {
+		return err
+	}
+
+	for i, o := range outputs {
+		fd := fields.ByName(protoreflect.Name(o.GetName()))
+		if fd == nil {
+			fd = fields.ByJSONName(o.GetName())
+		}
+
+		if fd == nil || !opts.selected(o.GetName()) {
+			continue
+		}
+
+		raw, err := rawContents(o, i, rawBytes, &opts)
+		if err != nil {
+			return err
+		}
+
+		b, err := decompress(raw, &opts)
+		if err != nil {
+			return fmt.Errorf("output %s: %w", o.GetName(), err)
+		}
+
+		val, err := decodeNative(o, b, &opts)
+		if err != nil {
+			return err
+		}
+
+		if err := setProtoField(m, fd, val, o.GetName(), &opts); err != nil {
+			return fmt.Errorf("output %s: %w", o.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+func setProtoField(
+	m protoreflect.Message, fd protoreflect.FieldDescriptor, val reflect.Value, output string, opts *Options,
+) error {
+	if !fd.IsList() {
+		if val.Kind() == reflect.Slice {
+			return fmt.Errorf("can't decode %s into singular field %s", val.Type(), fd.FullName())
+		}
+
+		v, err := protoValue(fd, val, output, opts)
+		if err != nil {
+			return err
+		}
+
+		m.Set(fd, v)
+
+		return nil
+	}
+
+	list := m.Mutable(fd).List()
+	list.Truncate(0)
+
+	return appendProtoList(list, fd, val, output, opts)
+}
+
+func appendProtoList(
+	list protoreflect.List, fd protoreflect.FieldDescriptor, val reflect.Value, output string, opts *Options,
+) error {
+	if val.Kind() != reflect.Slice {
+		v, err := protoValue(fd, val, output, opts)
+		if err != nil {
+			return err
+		}
+
+		list.Append(v)
+
+		return nil
+	}
+
+	for i := 0; i < val.Len(); i++ {
+		if err := appendProtoList(list, fd, val.Index(i), output, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// protoValue converts decoded element into value of field kind.
+// Elements are widened to field type, narrowing requires Options.Coerce, is reported to
+// Options.OnLossyConversion and fails for values out of range of field type.
+func protoValue(fd protoreflect.FieldDescriptor, val reflect.Value, output string, opts *Options) (protoreflect.Value, error) {
+	var t reflect.Type
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		t = reflect.TypeFor[bool]()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		t = reflect.TypeFor[int32]()
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		t = reflect.TypeFor[int64]()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		t = reflect.TypeFor[uint32]()
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		t = reflect.TypeFor[uint64]()
+	case protoreflect.FloatKind:
+		t = reflect.TypeFor[float32]()
+	case protoreflect.DoubleKind:
+		t = reflect.TypeFor[float64]()
+	case protoreflect.StringKind:
+		t = reflect.TypeFor[string]()
+	case protoreflect.BytesKind:
+		if val.Kind() != reflect.String {
+			return protoreflect.Value{}, fmt.Errorf("can't decode %s into %s", val.Type(), fd.FullName())
+		}
+
+		return protoreflect.ValueOfBytes([]byte(val.String())), nil
+	case protoreflect.EnumKind, protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s of %s", fd.Kind(), fd.FullName())
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s of %s", fd.Kind(), fd.FullName())
+	}
+
+	if val.Type() != t && (!isConvertible(val.Type(), t) || (isLossy(val.Type(), t) && !opts.Coerce)) {
+		return protoreflect.Value{}, fmt.Errorf("types doesn't match exp: %s got: %s", t, val.Type())
+	}
+
+	if opts.OnLossyConversion != nil && isLossy(val.Type(), t) {
+		c := LossyConversion{Output: output, From: val.Type(), To: t}
+		if err := opts.OnLossyConversion(c); err != nil {
+			return protoreflect.Value{}, fmt.Errorf("lossy conversion %s: %w", c, err)
+		}
+	}
+
+	res, err := convert(val, t, true)
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+
+	return protoreflect.ValueOf(res.Interface()), nil
+}
@@ -0,0 +1,124 @@
+//go:build protobuf
+
+package tritonparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestUnmarshalProto(t *testing.T) {
+	var m descriptorpb.FileDescriptorProto
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "name", datatype: STRING, shape: []int64{1}},
+			{name: "dependency", datatype: STRING, shape: []int64{1, 2}},
+			{name: "public_dependency", datatype: INT32, shape: []int64{1, 3}},
+		},
+		raw: [][]byte{encodeStrings("a.proto"), encodeStrings("b", "c"), int32Bytes(1, 2, 3)},
+	}
+
+	if err := UnmarshalProto(resp, &m, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.GetName() != "a.proto" || strings.Join(m.GetDependency(), ",") != "b,c" || len(m.GetPublicDependency()) != 3 {
+		t.Errorf("got %v", &m)
+	}
+}
+
+func TestUnmarshalProtoCoerce(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   int64
+		want    int32
+		wantErr string
+	}{
+		{name: "in range", value: -5, want: -5},
+		{name: "overflow", value: 1 << 40, wantErr: "overflows"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "value", datatype: INT64, shape: []int64{1}}},
+				raw:     [][]byte{int64Bytes(tt.value)},
+			}
+
+			var (
+				m         wrapperspb.Int32Value
+				converted []LossyConversion
+			)
+
+			err := UnmarshalProto(resp, &m, Options{Coerce: true, OnLossyConversion: func(c LossyConversion) error {
+				converted = append(converted, c)
+
+				return nil
+			}})
+
+			if len(converted) != 1 || converted[0].Output != "value" {
+				t.Errorf("got lossy conversions %v, want one of output value", converted)
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if m.GetValue() != tt.want {
+				t.Errorf("got %d, want %d", m.GetValue(), tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalProtoErrors(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	tests := []struct {
+		name     string
+		datatype string
+		raw      []byte
+		opts     Options
+		want     string
+	}{
+		{name: "narrowing", datatype: INT64, raw: int64Bytes(1), want: "types doesn't match"},
+		{name: "string", datatype: STRING, raw: encodeStrings("1"), opts: Options{Coerce: true}, want: "types doesn't match"},
+		{name: "short", datatype: INT32, raw: []byte{1}, want: "unexpected EOF"},
+		{
+			name:     "rejected",
+			datatype: INT64,
+			raw:      int64Bytes(1),
+			opts:     Options{Coerce: true, OnLossyConversion: func(LossyConversion) error { return errRejected }},
+			want:     "rejected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "value", datatype: tt.datatype, shape: []int64{1}}},
+				raw:     [][]byte{tt.raw},
+			}
+
+			var m wrapperspb.Int32Value
+
+			err := UnmarshalProto(resp, &m, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
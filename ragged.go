@@ -0,0 +1,92 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// raggedField is a slice of slices field receiving flat values output split by lengths output.
+type raggedField struct {
+	name    string
+	lengths string
+	field   reflect.Value
+}
+
+// getRaggedFields returns fields tagged with lengths option in order of fields declaration.
+func getRaggedFields(rv reflect.Value, opts *Options) []raggedField {
+	fieldsNum := rv.Elem().NumField()
+	var res []raggedField
+
+	for i := 0; i < fieldsNum; i++ {
+		name, tagOpts := parseTag(opts.fieldTag(rv.Elem().Type().Field(i)))
+		if lengths, ok := tagOpts.Get(tagLengths); ok {
+			res = append(res, raggedField{name: name, lengths: lengths, field: rv.Elem().Field(i)})
+		}
+	}
+
+	return res
+}
+
+// decodeRagged decodes values output of rf split into rows by lengths output into its slice of slices field.
+// Field is untouched and false is returned if none of outputs is present.
+func decodeRagged[T TritonModelInferResponseOutputs](rf raggedField, outputs []T, rawBytes [][]byte, opts *Options) (bool, error) {
+	vi, li := -1, -1
+	for i, o := range outputs {
+		switch o.GetName() {
+		case rf.name:
+			vi = i
+		case rf.lengths:
+			li = i
+		}
+	}
+
+	switch {
+	case vi == -1 && li == -1:
+		return false, nil
+	case vi == -1:
+		return false, fmt.Errorf("output %s is missing, while its %s output %s is present", rf.name, tagLengths, rf.lengths)
+	case li == -1:
+		return false, fmt.Errorf("output %s: %s output %s is missing", rf.name, tagLengths, rf.lengths)
+	}
+
+	if t := rf.field.Type(); t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Slice {
+		return false, fmt.Errorf("output %s: %s option requires slice of slices field, got %s", rf.name, tagLengths, t)
+	}
+
+	values, err := decodeVector(outputs[vi], vi, rawBytes, opts)
+	if err != nil {
+		return false, err
+	}
+
+	lengths, err := decodeIntegers(outputs[li], li, rawBytes, opts)
+	if err != nil {
+		return false, fmt.Errorf("output %s: %s option: %w", rf.name, tagLengths, err)
+	}
+
+	res := reflect.MakeSlice(reflect.SliceOf(values.Type()), len(lengths), len(lengths))
+	offset := 0
+
+	for j, n := range lengths {
+		if n < 0 || n > int64(values.Len()-offset) {
+			return false, fmt.Errorf("output %s: length %d of row %d exceeds %d remaining values", rf.name, n, j, values.Len()-offset)
+		}
+
+		end := offset + int(n)
+		res.Index(j).Set(values.Slice3(offset, end, end))
+		offset = end
+	}
+
+	if offset != values.Len() {
+		return false, fmt.Errorf("output %s: %d values don't match sum %d of lengths of output %s", rf.name, values.Len(), offset, rf.lengths)
+	}
+
+	if opts.Trace != nil {
+		opts.tracef("output %s: %d values split into %d rows by output %s", rf.name, values.Len(), len(lengths), rf.lengths)
+	}
+
+	if err := setTransformed(rf.field, res, rf.name, opts); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,200 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRagged(t *testing.T) {
+	type result struct {
+		Rows    [][]float32 `triton:"vals,lengths=lens"`
+		Lengths []int32     `triton:"lens"`
+		Words   [][]string  `triton:"words,lengths=counts"`
+	}
+
+	tests := []struct {
+		name string
+		resp *testResponse
+		want result
+	}{
+		{
+			name: "rows",
+			resp: &testResponse{
+				outputs: []*testOutput{
+					{name: "vals", datatype: FLOAT32, shape: []int64{5}},
+					{name: "lens", datatype: INT32, shape: []int64{3}},
+				},
+				raw: [][]byte{float32Bytes(1, 2, 3, 4, 5), int32Bytes(2, 0, 3)},
+			},
+			want: result{Rows: [][]float32{{1, 2}, {}, {3, 4, 5}}, Lengths: []int32{2, 0, 3}},
+		},
+		{
+			name: "vector shapes",
+			resp: &testResponse{
+				outputs: []*testOutput{
+					{name: "lens", datatype: INT32, shape: []int64{1, 2}},
+					{name: "vals", datatype: FLOAT32, shape: []int64{1, 3}},
+				},
+				raw: [][]byte{int32Bytes(1, 2), float32Bytes(0.5, 1.5, 2.5)},
+			},
+			want: result{Rows: [][]float32{{0.5}, {1.5, 2.5}}, Lengths: []int32{1, 2}},
+		},
+		{
+			name: "strings by unsigned lengths",
+			resp: &testResponse{
+				outputs: []*testOutput{
+					{name: "words", datatype: STRING, shape: []int64{3}},
+					{name: "counts", datatype: UINT8, shape: []int64{2}},
+				},
+				raw: [][]byte{encodeStrings("a", "b", "c"), {1, 2}},
+			},
+			want: result{Words: [][]string{{"a"}, {"b", "c"}}},
+		},
+		{
+			name: "no rows",
+			resp: &testResponse{
+				outputs: []*testOutput{
+					{name: "vals", datatype: FLOAT32, shape: []int64{0}},
+					{name: "lens", datatype: INT32, shape: []int64{0}},
+				},
+				raw: [][]byte{nil, nil},
+			},
+			want: result{Rows: [][]float32{}, Lengths: []int32{}},
+		},
+		{
+			name: "absent",
+			resp: &testResponse{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res result
+			if err := Unmarshal(tt.resp, &res); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestRaggedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		want    string
+	}{
+		{
+			name: "sum below values count",
+			outputs: []*testOutput{
+				{name: "vals", datatype: FLOAT32, shape: []int64{3}},
+				{name: "lens", datatype: INT32, shape: []int64{2}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2, 3), int32Bytes(1, 1)},
+			want: "output vals: 3 values don't match sum 2 of lengths of output lens",
+		},
+		{
+			name: "sum above values count",
+			outputs: []*testOutput{
+				{name: "vals", datatype: FLOAT32, shape: []int64{3}},
+				{name: "lens", datatype: INT32, shape: []int64{2}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2, 3), int32Bytes(2, 2)},
+			want: "output vals: length 2 of row 1 exceeds 1 remaining values",
+		},
+		{
+			name: "negative length",
+			outputs: []*testOutput{
+				{name: "vals", datatype: FLOAT32, shape: []int64{1}},
+				{name: "lens", datatype: INT32, shape: []int64{2}},
+			},
+			raw:  [][]byte{float32Bytes(1), int32Bytes(-1, 2)},
+			want: "output vals: length -1 of row 0 exceeds 1 remaining values",
+		},
+		{
+			name: "float lengths",
+			outputs: []*testOutput{
+				{name: "vals", datatype: FLOAT32, shape: []int64{1}},
+				{name: "lens", datatype: FLOAT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{float32Bytes(1), float32Bytes(1)},
+			want: "output vals: lengths option: output lens: integer datatype required, got FP32",
+		},
+		{
+			name:    "missing lengths",
+			outputs: []*testOutput{{name: "vals", datatype: FLOAT32, shape: []int64{1}}},
+			raw:     [][]byte{float32Bytes(1)},
+			want:    "output vals: lengths output lens is missing",
+		},
+		{
+			name:    "missing values",
+			outputs: []*testOutput{{name: "lens", datatype: INT32, shape: []int64{1}}},
+			raw:     [][]byte{int32Bytes(0)},
+			want:    "output vals is missing, while its lengths output lens is present",
+		},
+		{
+			name: "matrix values",
+			outputs: []*testOutput{
+				{name: "vals", datatype: FLOAT32, shape: []int64{2, 1}},
+				{name: "lens", datatype: INT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2), int32Bytes(2)},
+			want: "output vals: one-dimensional shape required, got [2 1]",
+		},
+		{
+			name: "short values",
+			outputs: []*testOutput{
+				{name: "vals", datatype: FLOAT32, shape: []int64{2}},
+				{name: "lens", datatype: INT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2)[:6], int32Bytes(2)},
+			want: "not a multiple of element size",
+		},
+		{
+			name: "short lengths",
+			outputs: []*testOutput{
+				{name: "vals", datatype: FLOAT32, shape: []int64{2}},
+				{name: "lens", datatype: INT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2), int32Bytes(2)[:3]},
+			want: "not a multiple of element size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res struct {
+				Rows [][]float32 `triton:"vals,lengths=lens"`
+			}
+
+			err := Unmarshal(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRaggedFieldType(t *testing.T) {
+	var res struct {
+		Flat []float32 `triton:"vals,lengths=lens"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "vals", datatype: FLOAT32, shape: []int64{1}},
+			{name: "lens", datatype: INT32, shape: []int64{1}},
+		},
+		raw: [][]byte{float32Bytes(1), int32Bytes(1)},
+	}
+
+	want := "output vals: lengths option requires slice of slices field, got []float32"
+	if err := Unmarshal(resp, &res); err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("got error %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,126 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// getRemainingField returns field tagged with remaining option, if any.
+func getRemainingField(rv reflect.Value, opts *Options) reflect.Value {
+	fieldsNum := rv.Elem().NumField()
+
+	for i := 0; i < fieldsNum; i++ {
+		_, tagOpts := parseTag(opts.fieldTag(rv.Elem().Type().Field(i)))
+		if tagOpts.Contains(tagRemaining) {
+			return rv.Elem().Field(i)
+		}
+	}
+
+	return reflect.Value{}
+}
+
+// referenced reports whether output is a part of concat field, values or indices of sparse field,
+// values or lengths of ragged field, or column of zip field.
+func (fs *fieldSet) referenced(output string, opts *Options) bool {
+	for _, cf := range fs.concat {
+		if slices.Contains(cf.parts, output) {
+			return true
+		}
+	}
+
+	for _, zf := range fs.zip {
+		columns, _ := zf.columns(opts)
+		for _, c := range columns {
+			if c.output == output {
+				return true
+			}
+		}
+	}
+
+	for _, sf := range fs.sparse {
+		if sf.name == output || sf.indices == output {
+			return true
+		}
+	}
+
+	for _, rf := range fs.ragged {
+		if rf.name == output || rf.lengths == output {
+			return true
+		}
+	}
+
+	return false
+}
+
+// decodeRemaining decodes output i without field into map[string]any field under its name.
+// Values are of native type of output datatype. One-dimensional outputs of other length than 1
+// are decoded into slices and outputs of higher rank, as well as empty ones, are flattened.
+func decodeRemaining[T TritonModelInferResponseOutputs](field reflect.Value, o T, i int, rawBytes [][]byte, opts *Options) error {
+	if field.Type() != reflect.TypeFor[map[string]any]() {
+		return fmt.Errorf("%s option requires map[string]any field, got %s", tagRemaining, field.Type())
+	}
+
+	if !opts.selected(o.GetName()) {
+		if opts.Trace != nil {
+			opts.tracef("output %s: skipped", o.GetName())
+		}
+
+		opts.Stats.skip(1)
+
+		return nil
+	}
+
+	raw, err := rawContents(o, i, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	opts.Stats.add(o, raw)
+
+	b, err := decompress(raw, opts)
+	if err != nil {
+		return fmt.Errorf("output %s: %w", o.GetName(), err)
+	}
+
+	var output TritonModelInferResponseOutputs = o
+
+	shape := o.GetShape()
+	count := int64(1)
+	for _, dim := range shape {
+		if dim < 0 {
+			return fmt.Errorf("invalid shape: %v", shape)
+		}
+
+		count *= dim
+	}
+
+	switch {
+	case len(shape) == 0:
+		// rank 0 output is a single value, as of shape [1].
+		output = reshaped{o, []int64{1}}
+	case count == 0 || len(shape) == 1 && shape[0] != 1 || len(shape) > 2:
+		output = reshaped{o, []int64{1, count}}
+	}
+
+	if err := opts.checkLimits(output, b); err != nil {
+		return err
+	}
+
+	val, err := decodeNative(output, b, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.Trace != nil {
+		opts.tracef("output %s: decoded into remaining outputs as %s", o.GetName(), val.Type())
+	}
+
+	if field.IsNil() {
+		field.Set(reflect.MakeMap(field.Type()))
+	}
+
+	field.SetMapIndex(reflect.ValueOf(o.GetName()), val)
+
+	return nil
+}
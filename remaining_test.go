@@ -0,0 +1,112 @@
+package tritonparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeRemaining(t *testing.T) {
+	tests := []struct {
+		name     string
+		datatype string
+		shape    []int64
+		raw      []byte
+		want     any
+	}{
+		{name: "single", datatype: INT32, shape: []int64{1}, raw: []byte{7, 0, 0, 0}, want: int32(7)},
+		{name: "scalar", datatype: INT32, shape: []int64{}, raw: []byte{7, 0, 0, 0}, want: int32(7)},
+		{name: "vector", datatype: UINT8, shape: []int64{3}, raw: []byte{1, 2, 3}, want: []uint8{1, 2, 3}},
+		{name: "row", datatype: UINT8, shape: []int64{1, 2}, raw: []byte{1, 2}, want: []uint8{1, 2}},
+		{name: "matrix", datatype: UINT8, shape: []int64{2, 2}, raw: []byte{1, 2, 3, 4}, want: [][]uint8{{1, 2}, {3, 4}}},
+		{name: "rank 3", datatype: UINT8, shape: []int64{2, 1, 2}, raw: []byte{1, 2, 3, 4}, want: []uint8{1, 2, 3, 4}},
+		{name: "empty vector", datatype: INT32, shape: []int64{0}, raw: []byte{}, want: []int32{}},
+		{name: "empty batch", datatype: INT32, shape: []int64{0, 3}, raw: []byte{}, want: []int32{}},
+		{name: "empty rank 3", datatype: INT32, shape: []int64{2, 0, 3}, raw: []byte{}, want: []int32{}},
+		{name: "strings", datatype: STRING, shape: []int64{2}, raw: encodeStrings("a", "b"), want: []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res struct {
+				Known int32          `triton:"known"`
+				Rest  map[string]any `triton:",remaining"`
+			}
+
+			resp := &testResponse{
+				outputs: []*testOutput{
+					{name: "known", datatype: INT32, shape: []int64{1}},
+					{name: "extra", datatype: tt.datatype, shape: tt.shape},
+				},
+				raw: [][]byte{{1, 0, 0, 0}, tt.raw},
+			}
+
+			if err := Unmarshal(resp, &res); err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Known != 1 || len(res.Rest) != 1 {
+				t.Fatalf("got %+v, want known output in its field and extra one in remaining", res)
+			}
+
+			if !reflect.DeepEqual(res.Rest["extra"], tt.want) {
+				t.Errorf("got %#v, want %#v", res.Rest["extra"], tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeRemainingErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		field any
+		shape []int64
+		raw   []byte
+	}{
+		{name: "field type", field: &struct {
+			Rest map[string]int32 `triton:",remaining"`
+		}{}, shape: []int64{1}, raw: []byte{1, 0, 0, 0}},
+		{name: "misaligned length", field: &struct {
+			Rest map[string]any `triton:",remaining"`
+		}{}, shape: []int64{2}, raw: []byte{1, 0, 0}},
+		{name: "negative dimension", field: &struct {
+			Rest map[string]any `triton:",remaining"`
+		}{}, shape: []int64{-1, 0}, raw: []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "extra", datatype: INT32, shape: tt.shape}},
+				raw:     [][]byte{tt.raw},
+			}
+
+			if err := Unmarshal(resp, tt.field); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestDecodeRemainingSkipsReferenced(t *testing.T) {
+	var res struct {
+		All  []int32        `triton:"all,concat=a|b"`
+		Rest map[string]any `triton:",remaining"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "a", datatype: INT32, shape: []int64{1}},
+			{name: "b", datatype: INT32, shape: []int64{1}},
+			{name: "c", datatype: INT32, shape: []int64{1}},
+		},
+		raw: [][]byte{{1, 0, 0, 0}, {2, 0, 0, 0}, {3, 0, 0, 0}},
+	}
+
+	if err := UnmarshalWithOptions(resp, &res, Options{Skip: []string{"c"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res.All, []int32{1, 2}) || len(res.Rest) != 0 {
+		t.Errorf("got %+v, want concatenated parts and no remaining outputs", res)
+	}
+}
@@ -0,0 +1,79 @@
+package tritonparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequired(t *testing.T) {
+	type result struct {
+		Logits []float32 `triton:"logits,required"`
+		Extra  int32     `triton:"extra"`
+	}
+
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		want    string
+	}{
+		{
+			name:    "present",
+			outputs: []*testOutput{{name: "logits", datatype: FLOAT32, shape: []int64{1, 1}}},
+			raw:     [][]byte{{0, 0, 0x80, 0x3f}},
+		},
+		{
+			name:    "empty",
+			outputs: []*testOutput{{name: "logits", datatype: FLOAT32, shape: []int64{1, 0}}},
+			raw:     [][]byte{{}},
+		},
+		{
+			name:    "missing",
+			outputs: []*testOutput{{name: "extra", datatype: INT32, shape: []int64{1}}},
+			raw:     [][]byte{int32Bytes(1)},
+			want:    "required output logits is missing",
+		},
+		{
+			name:    "no outputs",
+			outputs: nil,
+			want:    "required output logits is missing",
+		},
+		{
+			name:    "no raw contents",
+			outputs: []*testOutput{{name: "logits", datatype: FLOAT32, shape: []int64{1, 1}}},
+			raw:     [][]byte{nil},
+			want:    "required output logits has no raw contents",
+		},
+		{
+			name:    "raw contents missing",
+			outputs: []*testOutput{{name: "logits", datatype: FLOAT32, shape: []int64{1, 1}}},
+			raw:     nil,
+			want:    "raw contents are missing",
+		},
+		{
+			name:    "malformed",
+			outputs: []*testOutput{{name: "logits", datatype: FLOAT32, shape: []int64{1, 1}}},
+			raw:     [][]byte{{0, 0}},
+			want:    "not a multiple of element size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res result
+
+			err := Unmarshal(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
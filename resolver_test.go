@@ -0,0 +1,156 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type resolverResult struct {
+	Scores []float32 `triton:"scores"`
+	Label  string    `triton:"label"`
+	Count  int32
+}
+
+// versionResolver remaps output names of fields to names used by version 2 of model.
+func versionResolver(renames map[string]string) func(string, reflect.StructField) string {
+	return func(fieldName string, field reflect.StructField) string {
+		if tag, ok := renames[fieldName]; ok {
+			return tag
+		}
+
+		return field.Tag.Get("triton")
+	}
+}
+
+func TestTagResolver(t *testing.T) {
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "probs_v2", datatype: FLOAT32, shape: []int64{1, 2}},
+			{name: "label", datatype: STRING, shape: []int64{1}},
+			{name: "count", datatype: INT32, shape: []int64{1}},
+		},
+		raw: [][]byte{float32Bytes(0.25, 0.75), encodeStrings("cat"), int32Bytes(3)},
+	}
+
+	tests := []struct {
+		name     string
+		resolver func(string, reflect.StructField) string
+		want     resolverResult
+	}{
+		{
+			name: "static tags",
+			want: resolverResult{Label: "cat"},
+		},
+		{
+			name:     "renamed output",
+			resolver: versionResolver(map[string]string{"Scores": "probs_v2"}),
+			want:     resolverResult{Scores: []float32{0.25, 0.75}, Label: "cat"},
+		},
+		{
+			name:     "untagged field",
+			resolver: versionResolver(map[string]string{"Count": "count"}),
+			want:     resolverResult{Label: "cat", Count: 3},
+		},
+		{
+			name:     "ignored field",
+			resolver: versionResolver(map[string]string{"Label": "", "Scores": "probs_v2"}),
+			want:     resolverResult{Scores: []float32{0.25, 0.75}},
+		},
+		{
+			name:     "tag options",
+			resolver: versionResolver(map[string]string{"Scores": "probs_v2,reverse"}),
+			want:     resolverResult{Scores: []float32{0.75, 0.25}, Label: "cat"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res resolverResult
+			if err := UnmarshalWithOptions(resp, &res, Options{TagResolver: tt.resolver}); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagResolverBinding(t *testing.T) {
+	renames := map[string]string{"Scores": "probs_v2"}
+
+	var res resolverResult
+
+	b, err := Bind[*testOutput](&res, Options{TagResolver: versionResolver(renames)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// tags are resolved when struct is bound, so later changes of mapping don't apply.
+	renames["Scores"] = "scores"
+
+	resp := &testResponse{
+		outputs: []*testOutput{{name: "probs_v2", datatype: FLOAT32, shape: []int64{1, 1}}},
+		raw:     [][]byte{float32Bytes(0.5)},
+	}
+
+	if err := b.Decode(resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res.Scores, []float32{0.5}) {
+		t.Errorf("got %v, want [0.5]", res.Scores)
+	}
+}
+
+func TestTagResolverErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		renames map[string]string
+		outputs []*testOutput
+		raw     [][]byte
+		want    string
+	}{
+		{
+			name:    "required",
+			renames: map[string]string{"Scores": "probs_v2,required"},
+			want:    "required output probs_v2 is missing",
+		},
+		{
+			name:    "type mismatch",
+			renames: map[string]string{"Count": "label"},
+			outputs: []*testOutput{{name: "label", datatype: STRING, shape: []int64{1}}},
+			raw:     [][]byte{encodeStrings("cat")},
+			want:    "types doesn't match",
+		},
+		{
+			name:    "short contents",
+			renames: map[string]string{"Count": "count"},
+			outputs: []*testOutput{{name: "count", datatype: INT32, shape: []int64{1}}},
+			raw:     [][]byte{{1, 0}},
+			want:    "binary read failed",
+		},
+		{
+			name:    "invalid option value",
+			renames: map[string]string{"Scores": "probs_v2,round=up"},
+			outputs: []*testOutput{{name: "probs_v2", datatype: FLOAT32, shape: []int64{1, 1}}},
+			raw:     [][]byte{float32Bytes(0.5)},
+			want:    "probs_v2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res resolverResult
+
+			opts := Options{TagResolver: versionResolver(tt.renames)}
+
+			err := UnmarshalWithOptions(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res, opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package tritonparser
+
+// Response is a TritonModelInferResponse with outputs of interface type.
+// It adapts responses with concrete output types, e.g. generated protobuf messages,
+// where response of any output type is required.
+type Response struct {
+	ModelName         string
+	ModelVersion      string
+	Parameters        map[string]any
+	ErrorMessage      string
+	Outputs           []TritonModelInferResponseOutputs
+	RawOutputContents [][]byte
+}
+
+// NewResponse adapts inferResponse to Response.
+func NewResponse[T TritonModelInferResponseOutputs](inferResponse TritonModelInferResponse[T]) *Response {
+	outputs := inferResponse.GetOutputs()
+	res := &Response{
+		Outputs:           make([]TritonModelInferResponseOutputs, len(outputs)),
+		RawOutputContents: inferResponse.GetRawOutputContents(),
+	}
+
+	for i, o := range outputs {
+		res.Outputs[i] = o
+	}
+
+	if info, ok := any(inferResponse).(TritonModelInfo); ok {
+		res.ModelName, res.ModelVersion = info.GetModelName(), info.GetModelVersion()
+	}
+
+	res.Parameters, _ = getParameters(inferResponse)
+
+	if er, ok := any(inferResponse).(TritonErrorResponse); ok {
+		res.ErrorMessage = er.GetErrorMessage()
+	}
+
+	return res
+}
+
+func (r *Response) GetOutputs() []TritonModelInferResponseOutputs {
+	return r.Outputs
+}
+
+func (r *Response) GetRawOutputContents() [][]byte {
+	return r.RawOutputContents
+}
+
+func (r *Response) GetModelName() string {
+	return r.ModelName
+}
+
+func (r *Response) GetModelVersion() string {
+	return r.ModelVersion
+}
+
+// GetParameters returns parameters of response, unwrapped the same way as parameters of outputs.
+func (r *Response) GetParameters() map[string]any {
+	return r.Parameters
+}
+
+// GetErrorMessage returns error message of failed inference, if any.
+func (r *Response) GetErrorMessage() string {
+	return r.ErrorMessage
+}
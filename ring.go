@@ -0,0 +1,164 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// RingBuffer keeps the last Cap elements pushed into it, so memory stays bounded
+// when outputs of streaming responses are decoded into the same field.
+// Zero value has no capacity; use NewRingBuffer or ring tag option to set it.
+type RingBuffer[T any] struct {
+	buf   []T
+	start int
+	n     int
+}
+
+// NewRingBuffer returns RingBuffer keeping the last capacity elements.
+func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
+	return &RingBuffer[T]{buf: make([]T, capacity)}
+}
+
+// Push appends vals, overwriting the oldest elements when buffer is full.
+func (r *RingBuffer[T]) Push(vals ...T) {
+	if len(r.buf) == 0 {
+		return
+	}
+
+	if len(vals) > len(r.buf) {
+		vals = vals[len(vals)-len(r.buf):]
+	}
+
+	for _, v := range vals {
+		r.buf[(r.start+r.n)%len(r.buf)] = v
+		if r.n < len(r.buf) {
+			r.n++
+		} else {
+			r.start = (r.start + 1) % len(r.buf)
+		}
+	}
+}
+
+// Len returns number of elements in buffer.
+func (r *RingBuffer[T]) Len() int {
+	return r.n
+}
+
+// Cap returns capacity of buffer.
+func (r *RingBuffer[T]) Cap() int {
+	return len(r.buf)
+}
+
+// At returns i-th element counting from the oldest one. It panics if i is out of range.
+func (r *RingBuffer[T]) At(i int) T {
+	if i < 0 || i >= r.n {
+		panic(fmt.Sprintf("tritonparser: index %d is out of range [0, %d)", i, r.n))
+	}
+
+	return r.buf[(r.start+i)%len(r.buf)]
+}
+
+// Slice returns copy of elements from the oldest to the newest one.
+func (r *RingBuffer[T]) Slice() []T {
+	res := make([]T, r.n)
+	for i := range res {
+		res[i] = r.buf[(r.start+i)%len(r.buf)]
+	}
+
+	return res
+}
+
+// Reset removes all elements keeping capacity.
+func (r *RingBuffer[T]) Reset() {
+	r.start, r.n = 0, 0
+}
+
+// ring is implemented by pointers to RingBuffer of any element type.
+type ring interface {
+	Cap() int
+	elemType() reflect.Type
+	push(vals reflect.Value)
+	grow(capacity int)
+}
+
+func (r *RingBuffer[T]) elemType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (r *RingBuffer[T]) push(vals reflect.Value) {
+	r.Push(as[[]T](vals.Interface())...)
+}
+
+func (r *RingBuffer[T]) grow(capacity int) {
+	if len(r.buf) == 0 {
+		r.buf = make([]T, capacity)
+	}
+}
+
+// getRing returns ring of RingBuffer field or pointer to it. Nil pointer fields are allocated.
+func getRing(field reflect.Value) (ring, bool) {
+	if field.Kind() == reflect.Pointer {
+		if _, ok := reflect.Zero(field.Type()).Interface().(ring); !ok {
+			return nil, false
+		}
+
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+
+		return as[ring](field.Interface()), true
+	}
+
+	if !field.CanAddr() {
+		return nil, false
+	}
+
+	r, ok := field.Addr().Interface().(ring)
+
+	return r, ok
+}
+
+// unmarshalRing decodes elements of output of any rank in row-major order and pushes them into r.
+func unmarshalRing(r ring, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	if r.Cap() == 0 {
+		return fmt.Errorf("output %s: ring buffer has no capacity", output.GetName())
+	}
+
+	count := int64(1)
+	for _, dim := range output.GetShape() {
+		if dim < 0 {
+			return fmt.Errorf("invalid shape: %v", output.GetShape())
+		}
+
+		count *= dim
+	}
+
+	vals := reflect.New(reflect.SliceOf(r.elemType())).Elem()
+	flat := reshaped{output, []int64{1, count}}
+	if err := parseToArray(map[string]reflect.Value{output.GetName(): vals}, flat, rawBytes, opts); err != nil {
+		return err
+	}
+
+	r.push(vals)
+
+	return nil
+}
+
+// decodeRing decodes output into RingBuffer field, allocating it with capacity of ring option
+// if it has no capacity yet.
+func decodeRing(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options, capacity string) error {
+	r, ok := getRing(field)
+	if !ok {
+		return fmt.Errorf("output %s: %s option requires RingBuffer field, got %s", output.GetName(), tagRing, field.Type())
+	}
+
+	c, err := strconv.Atoi(capacity)
+	if err != nil || c <= 0 {
+		return fmt.Errorf("output %s: %s option requires positive capacity, got %q", output.GetName(), tagRing, capacity)
+	}
+
+	r.grow(c)
+
+	return unmarshalRing(r, output, rawBytes, opts)
+}
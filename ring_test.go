@@ -0,0 +1,182 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	tests := []struct {
+		name   string
+		cap    int
+		pushes [][]int
+		want   []int
+	}{
+		{name: "empty", cap: 3, want: []int{}},
+		{name: "partial", cap: 3, pushes: [][]int{{1}, {2}}, want: []int{1, 2}},
+		{name: "full", cap: 3, pushes: [][]int{{1, 2}, {3}}, want: []int{1, 2, 3}},
+		{name: "wrapped", cap: 3, pushes: [][]int{{1, 2}, {3, 4}, {5}}, want: []int{3, 4, 5}},
+		{name: "longer than capacity", cap: 3, pushes: [][]int{{1}, {2, 3, 4, 5, 6}}, want: []int{4, 5, 6}},
+		{name: "no capacity", cap: 0, pushes: [][]int{{1, 2}}, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRingBuffer[int](tt.cap)
+			for _, vals := range tt.pushes {
+				r.Push(vals...)
+			}
+
+			if got := r.Slice(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Slice() = %v, want %v", got, tt.want)
+			}
+
+			if r.Len() != len(tt.want) || r.Cap() != tt.cap {
+				t.Errorf("Len(), Cap() = %d, %d, want %d, %d", r.Len(), r.Cap(), len(tt.want), tt.cap)
+			}
+
+			for i, want := range tt.want {
+				if got := r.At(i); got != want {
+					t.Errorf("At(%d) = %d, want %d", i, got, want)
+				}
+			}
+
+			r.Reset()
+			if r.Len() != 0 || r.Cap() != tt.cap {
+				t.Errorf("after Reset Len(), Cap() = %d, %d, want 0, %d", r.Len(), r.Cap(), tt.cap)
+			}
+		})
+	}
+}
+
+func TestRingBufferAtPanics(t *testing.T) {
+	r := NewRingBuffer[int](2)
+	r.Push(1)
+
+	for _, i := range []int{-1, 1, 2} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("At(%d) didn't panic", i)
+				}
+			}()
+
+			r.At(i)
+		}()
+	}
+}
+
+func TestRingBufferDecode(t *testing.T) {
+	var res struct {
+		Samples RingBuffer[int16]   `triton:"samples,ring=4"`
+		Labels  *RingBuffer[string] `triton:"labels"`
+	}
+
+	res.Labels = NewRingBuffer[string](2)
+
+	responses := []*testResponse{
+		{
+			outputs: []*testOutput{
+				{name: "samples", datatype: INT16, shape: []int64{1, 3}},
+				{name: "labels", datatype: STRING, shape: []int64{1}},
+			},
+			raw: [][]byte{int16Bytes(1, 2, 3), encodeStrings("a")},
+		},
+		{
+			outputs: []*testOutput{
+				{name: "samples", datatype: INT16, shape: []int64{2, 1}},
+				{name: "labels", datatype: STRING, shape: []int64{1, 2}},
+			},
+			raw: [][]byte{int16Bytes(4, 5), encodeStrings("b", "c")},
+		},
+	}
+
+	for _, resp := range responses {
+		if err := Unmarshal(resp, &res); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := res.Samples.Slice(); !reflect.DeepEqual(got, []int16{2, 3, 4, 5}) {
+		t.Errorf("got samples %v, want [2 3 4 5]", got)
+	}
+
+	if got := res.Labels.Slice(); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("got labels %q, want [b c]", got)
+	}
+}
+
+func TestRingBufferDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		dst    any
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name: "no capacity",
+			dst: &struct {
+				R RingBuffer[int32] `triton:"r"`
+			}{},
+			output: &testOutput{name: "r", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "output r: ring buffer has no capacity",
+		},
+		{
+			name: "capacity",
+			dst: &struct {
+				R RingBuffer[int32] `triton:"r,ring=0"`
+			}{},
+			output: &testOutput{name: "r", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   `output r: ring option requires positive capacity, got "0"`,
+		},
+		{
+			name: "field",
+			dst: &struct {
+				R []int32 `triton:"r,ring=2"`
+			}{},
+			output: &testOutput{name: "r", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "output r: ring option requires RingBuffer field, got []int32",
+		},
+		{
+			name: "datatype",
+			dst: &struct {
+				R RingBuffer[int32] `triton:"r,ring=2"`
+			}{},
+			output: &testOutput{name: "r", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(1),
+			want:   "types doesn't match",
+		},
+		{
+			name: "short",
+			dst: &struct {
+				R RingBuffer[int32] `triton:"r,ring=2"`
+			}{},
+			output: &testOutput{name: "r", datatype: INT32, shape: []int64{2}},
+			raw:    int32Bytes(1, 2)[:6],
+			want:   "not a multiple of element size",
+		},
+		{
+			name: "negative dimension",
+			dst: &struct {
+				R RingBuffer[int32] `triton:"r,ring=2"`
+			}{},
+			output: &testOutput{name: "r", datatype: INT32, shape: []int64{1, -1}},
+			raw:    int32Bytes(1),
+			want:   "invalid shape: [1 -1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(tt.dst, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package tritonparser
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Names of fields receiving sequence parameters of response of stateful model.
+const (
+	sequenceIDField    = "_sequence_id"
+	sequenceStartField = "_sequence_start"
+	sequenceEndField   = "_sequence_end"
+)
+
+// setSequenceInfo stores sequence_id, sequence_start and sequence_end parameters of inferResponse
+// into fields tagged with _sequence_id, _sequence_start and _sequence_end, and reports names of set fields.
+// Response must have GetParameters method, as generated ModelInferResponse does.
+func setSequenceInfo(inferResponse any, fieldMap map[string]reflect.Value) ([]string, error) {
+	params, ok := getParameters(inferResponse)
+	if !ok {
+		return nil, nil
+	}
+
+	var set []string
+	for _, f := range [...]struct{ name, param string }{
+		{sequenceIDField, "sequence_id"},
+		{sequenceStartField, "sequence_start"},
+		{sequenceEndField, "sequence_end"},
+	} {
+		field, ok := fieldMap[f.name]
+		if !ok {
+			continue
+		}
+
+		val, ok := params[f.param]
+		if !ok {
+			continue
+		}
+
+		var err error
+		if f.name == sequenceIDField {
+			err = setSequenceID(field, val)
+		} else {
+			err = setSequenceFlag(field, val)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+
+		set = append(set, f.name)
+	}
+
+	return set, nil
+}
+
+// setSequenceID stores integer or string sequence id into integer or string field.
+func setSequenceID(field reflect.Value, val any) error {
+	if s, ok := val.(string); ok {
+		if field.Kind() != reflect.String {
+			return fmt.Errorf("string sequence id requires string field, got %s", field.Type())
+		}
+
+		field.SetString(s)
+
+		return nil
+	}
+
+	var v reflect.Value
+	switch n := val.(type) {
+	case int64, uint64:
+		v = reflect.ValueOf(n)
+	case float64:
+		// numbers of JSON parameters are floats.
+		if n != math.Trunc(n) || n < 0 || n >= math.MaxUint64 {
+			return fmt.Errorf("invalid sequence id: %v", n)
+		}
+
+		v = reflect.ValueOf(uint64(n))
+	default:
+		return fmt.Errorf("invalid sequence id of type %T", val)
+	}
+
+	k := field.Kind()
+	if !isInt(k) && !isUint(k) {
+		return fmt.Errorf("integer sequence id requires integer field, got %s", field.Type())
+	}
+
+	if err := checkSign(v, field.Type()); err != nil {
+		return err
+	}
+
+	// sign is checked, so value is truncated if it doesn't survive round trip.
+	res := v.Convert(field.Type())
+	if res.Convert(v.Type()).Interface() != v.Interface() {
+		return fmt.Errorf("sequence id %v overflows %s", v, field.Type())
+	}
+
+	field.Set(res)
+
+	return nil
+}
+
+// setSequenceFlag stores bool sequence flag into bool field.
+func setSequenceFlag(field reflect.Value, val any) error {
+	b, ok := val.(bool)
+	if !ok {
+		return fmt.Errorf("invalid sequence flag of type %T", val)
+	}
+
+	if field.Kind() != reflect.Bool {
+		return fmt.Errorf("bool field required, got %s", field.Type())
+	}
+
+	field.SetBool(b)
+
+	return nil
+}
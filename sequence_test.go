@@ -0,0 +1,208 @@
+package tritonparser
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type protoParameterBool struct{ BoolParam bool }
+
+func (*protoParameterBool) isChoice() {}
+
+// sequenceResponse is testResponse carrying parameters, as generated ModelInferResponse does.
+type sequenceResponse struct {
+	testResponse
+	params map[string]*protoParameter
+}
+
+func (r *sequenceResponse) GetParameters() map[string]*protoParameter { return r.params }
+
+func TestSequence(t *testing.T) {
+	type result struct {
+		ID    uint64 `triton:"_sequence_id"`
+		Start bool   `triton:"_sequence_start"`
+		End   bool   `triton:"_sequence_end"`
+		Score int32  `triton:"score"`
+	}
+
+	outputs := []*testOutput{{name: "score", datatype: INT32, shape: []int64{1}}}
+	raw := [][]byte{int32Bytes(3)}
+
+	tests := []struct {
+		name string
+		resp TritonModelInferResponse[*testOutput]
+		want result
+	}{
+		{
+			name: "proto parameters",
+			resp: &sequenceResponse{
+				testResponse: testResponse{outputs: outputs, raw: raw},
+				params: map[string]*protoParameter{
+					"sequence_id":    {choice: &protoParameterUint64{Uint64Param: math.MaxUint64}},
+					"sequence_start": {choice: &protoParameterBool{BoolParam: true}},
+					"sequence_end":   {choice: &protoParameterBool{BoolParam: false}},
+				},
+			},
+			want: result{ID: math.MaxUint64, Start: true, Score: 3},
+		},
+		{
+			name: "int64 id",
+			resp: &sequenceResponse{
+				testResponse: testResponse{outputs: outputs, raw: raw},
+				params: map[string]*protoParameter{
+					"sequence_id":  {choice: &protoParameterInt64{Int64Param: 42}},
+					"sequence_end": {choice: &protoParameterBool{BoolParam: true}},
+				},
+			},
+			want: result{ID: 42, End: true, Score: 3},
+		},
+		{
+			name: "without parameters",
+			resp: &sequenceResponse{testResponse: testResponse{outputs: outputs, raw: raw}},
+			want: result{ID: 7, Score: 3},
+		},
+		{
+			name: "without parameters method",
+			resp: &testResponse{outputs: outputs, raw: raw},
+			want: result{ID: 7, Score: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := result{ID: 7}
+			if err := Unmarshal(tt.resp, &res); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestSequenceHTTP(t *testing.T) {
+	var res struct {
+		ID    int32  `triton:"_sequence_id"`
+		Name  string `triton:"_model_name"`
+		Start bool   `triton:"_sequence_start"`
+	}
+
+	body := `{"model_name":"lstm","parameters":{"sequence_id":12,"sequence_start":true},"outputs":[]}`
+	if err := UnmarshalHTTP([]byte(body), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.ID != 12 || res.Name != "lstm" || !res.Start {
+		t.Errorf("got %+v, want {ID:12 Name:lstm Start:true}", res)
+	}
+
+	var str struct {
+		ID string `triton:"_sequence_id"`
+	}
+
+	if err := UnmarshalHTTP([]byte(`{"parameters":{"sequence_id":"session-1"},"outputs":[]}`), &str); err != nil {
+		t.Fatal(err)
+	}
+
+	if str.ID != "session-1" {
+		t.Errorf("got %q, want %q", str.ID, "session-1")
+	}
+}
+
+func TestSequenceErrors(t *testing.T) {
+	type (
+		uint64ID struct {
+			ID uint64 `triton:"_sequence_id"`
+		}
+		int8ID struct {
+			ID int8 `triton:"_sequence_id"`
+		}
+		int64ID struct {
+			ID int64 `triton:"_sequence_id"`
+		}
+		stringID struct {
+			ID string `triton:"_sequence_id"`
+		}
+		boolStart struct {
+			Start bool `triton:"_sequence_start"`
+		}
+		int8End struct {
+			End int8 `triton:"_sequence_end"`
+		}
+	)
+
+	tests := []struct {
+		name   string
+		params map[string]any
+		dst    any
+		want   string
+	}{
+		{
+			name:   "negative id into unsigned",
+			params: map[string]any{"sequence_id": int64(-1)},
+			dst:    &uint64ID{},
+			want:   "_sequence_id",
+		},
+		{
+			name:   "id overflow",
+			params: map[string]any{"sequence_id": int64(300)},
+			dst:    &int8ID{},
+			want:   "_sequence_id: sequence id 300 overflows int8",
+		},
+		{
+			name:   "fractional JSON id",
+			params: map[string]any{"sequence_id": 1.5},
+			dst:    &int64ID{},
+			want:   "_sequence_id: invalid sequence id: 1.5",
+		},
+		{
+			name:   "negative JSON id",
+			params: map[string]any{"sequence_id": -2.0},
+			dst:    &int64ID{},
+			want:   "_sequence_id: invalid sequence id: -2",
+		},
+		{
+			name:   "string id into integer",
+			params: map[string]any{"sequence_id": "s"},
+			dst:    &int64ID{},
+			want:   "_sequence_id: string sequence id requires string field, got int64",
+		},
+		{
+			name:   "integer id into string",
+			params: map[string]any{"sequence_id": int64(1)},
+			dst:    &stringID{},
+			want:   "_sequence_id: integer sequence id requires integer field, got string",
+		},
+		{
+			name:   "bool id",
+			params: map[string]any{"sequence_id": true},
+			dst:    &int64ID{},
+			want:   "_sequence_id: invalid sequence id of type bool",
+		},
+		{
+			name:   "integer flag",
+			params: map[string]any{"sequence_start": int64(1)},
+			dst:    &boolStart{},
+			want:   "_sequence_start: invalid sequence flag of type int64",
+		},
+		{
+			name:   "flag into integer",
+			params: map[string]any{"sequence_end": true},
+			dst:    &int8End{},
+			want:   "_sequence_end: bool field required, got int8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal(&Response{Parameters: tt.params}, tt.dst)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
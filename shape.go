@@ -0,0 +1,147 @@
+package tritonparser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ShapeKind is the kind of destination an output of given shape is decoded into.
+type ShapeKind int
+
+const (
+	// ShapeUnknown is returned for shapes that can't be classified.
+	ShapeUnknown ShapeKind = iota
+	// ShapeScalar is a one-dimensional shape decoded into a single value.
+	ShapeScalar
+	// ShapeVector is a [1, N] shape decoded into a slice.
+	ShapeVector
+	// ShapeMatrix is a [M, N] shape with M > 1 decoded into a slice of slices.
+	ShapeMatrix
+	// ShapeHigherRank is a shape with more than two dimensions.
+	ShapeHigherRank
+)
+
+func (k ShapeKind) String() string {
+	switch k {
+	case ShapeUnknown:
+		return "unknown"
+	case ShapeScalar:
+		return "scalar"
+	case ShapeVector:
+		return "vector"
+	case ShapeMatrix:
+		return "matrix"
+	case ShapeHigherRank:
+		return "higher rank"
+	default:
+		return fmt.Sprintf("ShapeKind(%d)", int(k))
+	}
+}
+
+// ClassifyShape reports the kind of destination an output of given shape is decoded into.
+// Error is returned for shapes that are invalid or not yet supported.
+func ClassifyShape(shape []int64) (ShapeKind, error) {
+	for _, dim := range shape {
+		if dim < 0 {
+			return ShapeUnknown, fmt.Errorf("invalid shape: %v", shape)
+		}
+	}
+
+	switch {
+	case len(shape) > 2:
+		return ShapeHigherRank, errors.New("len(shape) > 2 is not yet supported")
+	case len(shape) == 1:
+		return ShapeScalar, nil
+	case len(shape) == 2 && shape[0] == 1:
+		return ShapeVector, nil
+	case len(shape) == 2 && shape[0] > 1:
+		return ShapeMatrix, nil
+	default:
+		return ShapeUnknown, fmt.Errorf("unknown shape: %v", shape)
+	}
+}
+
+// reshaped is output with shape replaced.
+type reshaped struct {
+	TritonModelInferResponseOutputs
+	shape []int64
+}
+
+func (r reshaped) GetShape() []int64 {
+	return r.shape
+}
+
+// batchSize is the batch dimension shared by outputs.
+type batchSize struct {
+	first string
+	size  int64
+}
+
+// check returns error if dimension axis of output differs from one of previously checked outputs.
+// Scalars pass, as do one-dimensional outputs unless axis is 0.
+func (b *batchSize) check(output TritonModelInferResponseOutputs, axis int) error {
+	shape := output.GetShape()
+	if len(shape) < 2 && axis != 0 || len(shape) == 0 {
+		return nil
+	}
+
+	if axis >= len(shape) {
+		return fmt.Errorf("output %s: batch axis %d is out of range of shape %v", output.GetName(), axis, shape)
+	}
+
+	if b.first == "" {
+		b.first, b.size = output.GetName(), shape[axis]
+
+		return nil
+	}
+
+	if shape[axis] != b.size {
+		return fmt.Errorf("output %s: batch size %d doesn't match batch size %d of output %s",
+			output.GetName(), shape[axis], b.size, b.first)
+	}
+
+	return nil
+}
+
+// batchLeading returns output reshaped for batch dimension at opts.BatchAxis and options to read it.
+// Rank 2 output batched along the last axis is decoded transposed, as its batches are columns.
+// Higher rank outputs keep their layout, as axes option moves their dimensions,
+// but batch dimension of size 1 is dropped if opts.SqueezeBatch is set.
+func batchLeading(output TritonModelInferResponseOutputs, opts *Options) (TritonModelInferResponseOutputs, *Options, error) {
+	shape, axis := output.GetShape(), opts.BatchAxis
+	if axis < 0 {
+		return nil, nil, fmt.Errorf("invalid batch axis: %d", axis)
+	}
+
+	if len(shape) < 2 || axis == 0 && !opts.SqueezeBatch {
+		return output, opts, nil
+	}
+
+	if axis >= len(shape) {
+		return nil, nil, fmt.Errorf("output %s: batch axis %d is out of range of shape %v", output.GetName(), axis, shape)
+	}
+
+	switch {
+	case len(shape) > 2 && opts.SqueezeBatch && shape[axis] == 1:
+		return reshaped{output, slices.Delete(slices.Clone(shape), axis, axis+1)}, opts, nil
+	case len(shape) == 2 && axis == 1:
+		transposed := *opts
+		transposed.ColumnMajor = !opts.ColumnMajor
+
+		return reshaped{output, []int64{shape[1], shape[0]}}, &transposed, nil
+	default:
+		return output, opts, nil
+	}
+}
+
+// squeezeTrailing returns output with trailing dimension of size 1 dropped, if opts.SqueezeTrailing is set
+// and output is of rank 2 and above.
+func squeezeTrailing(output TritonModelInferResponseOutputs, opts *Options) TritonModelInferResponseOutputs {
+	shape := output.GetShape()
+	if !opts.SqueezeTrailing || len(shape) < 2 || shape[len(shape)-1] != 1 {
+		return output
+	}
+
+	return reshaped{output, shape[:len(shape)-1]}
+}
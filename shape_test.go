@@ -0,0 +1,561 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClassifyShape(t *testing.T) {
+	tests := []struct {
+		shape   []int64
+		want    ShapeKind
+		wantErr bool
+	}{
+		{shape: []int64{1}, want: ShapeScalar},
+		{shape: []int64{5}, want: ShapeScalar},
+		{shape: []int64{1, 3}, want: ShapeVector},
+		{shape: []int64{1, 0}, want: ShapeVector},
+		{shape: []int64{2, 3}, want: ShapeMatrix},
+		{shape: []int64{2, 3, 4}, want: ShapeHigherRank, wantErr: true},
+		{shape: nil, want: ShapeUnknown, wantErr: true},
+		{shape: []int64{0, 3}, want: ShapeUnknown, wantErr: true},
+		{shape: []int64{-1}, want: ShapeUnknown, wantErr: true},
+		{shape: []int64{1, -3}, want: ShapeUnknown, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ClassifyShape(tt.shape)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ClassifyShape(%v) = %s, %v, want %s, error %t", tt.shape, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestShapeKindString(t *testing.T) {
+	tests := map[ShapeKind]string{
+		ShapeUnknown:    "unknown",
+		ShapeScalar:     "scalar",
+		ShapeVector:     "vector",
+		ShapeMatrix:     "matrix",
+		ShapeHigherRank: "higher rank",
+		ShapeKind(42):   "ShapeKind(42)",
+	}
+
+	for k, want := range tests {
+		if got := k.String(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestOneDimensionalSlice(t *testing.T) {
+	type result struct {
+		Score   float32   `triton:"score"`
+		Scores  []float32 `triton:"scores"`
+		IDs     []int32   `triton:"ids"`
+		Labels  []string  `triton:"labels"`
+		Missing []int32   `triton:"missing"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "score", datatype: FLOAT32, shape: []int64{1}},
+			{name: "scores", datatype: FLOAT32, shape: []int64{1}},
+			{name: "ids", datatype: INT32, shape: []int64{3}},
+			{name: "labels", datatype: STRING, shape: []int64{3}},
+			{name: "missing", datatype: INT32, shape: []int64{0}},
+		},
+		raw: [][]byte{
+			float32Bytes(0.5),
+			float32Bytes(0.25),
+			int32Bytes(1, 2, 3),
+			encodeStrings("a", "b", "c"),
+			{},
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Score:   0.5,
+		Scores:  []float32{0.25},
+		IDs:     []int32{1, 2, 3},
+		Labels:  []string{"a", "b", "c"},
+		Missing: []int32{},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestOneDimensionalSliceErrors(t *testing.T) {
+	var res struct {
+		Score  float32   `triton:"score"`
+		Scores []float32 `triton:"scores"`
+		Labels []string  `triton:"labels"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "several into scalar",
+			output: &testOutput{name: "score", datatype: FLOAT32, shape: []int64{2}},
+			raw:    float32Bytes(1, 2),
+			want:   "trailing bytes",
+		},
+		{
+			name:   "short slice",
+			output: &testOutput{name: "scores", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(1)[:3],
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "missing string",
+			output: &testOutput{name: "labels", datatype: STRING, shape: []int64{3}},
+			raw:    encodeStrings("a", "b"),
+			want:   "output labels",
+		},
+		{
+			name:   "type mismatch",
+			output: &testOutput{name: "scores", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal(&testResponse{outputs: []*testOutput{tt.output}, raw: [][]byte{tt.raw}}, &res)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSqueezeBatch(t *testing.T) {
+	type result struct {
+		Matrix  [][]int32  `triton:"matrix"`
+		Vector  []int32    `triton:"vector"`
+		Strings [][]string `triton:"strings"`
+		Axis    [][]int32  `triton:"axis"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "matrix", datatype: INT32, shape: []int64{1, 2, 2}},
+			{name: "vector", datatype: INT32, shape: []int64{1, 1, 3}},
+			{name: "strings", datatype: STRING, shape: []int64{1, 2, 1}},
+			{name: "axis", datatype: INT32, shape: []int64{2, 1, 2}},
+		},
+		raw: [][]byte{int32Bytes(1, 2, 3, 4), int32Bytes(5, 6, 7), encodeStrings("a", "b"), int32Bytes(8, 9, 10, 11)},
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want result
+	}{
+		{
+			name: "leading",
+			opts: Options{SqueezeBatch: true, Skip: []string{"axis"}},
+			want: result{Matrix: [][]int32{{1, 2}, {3, 4}}, Vector: []int32{5, 6, 7}, Strings: [][]string{{"a"}, {"b"}}},
+		},
+		{
+			name: "batch axis",
+			opts: Options{SqueezeBatch: true, BatchAxis: 1, Only: []string{"axis"}},
+			want: result{Axis: [][]int32{{8, 9}, {10, 11}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res result
+			if err := UnmarshalWithOptions(resp, &res, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestSqueezeBatchErrors(t *testing.T) {
+	var res struct {
+		Matrix [][]int32 `triton:"matrix"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		opts   Options
+		want   string
+	}{
+		{
+			name:   "without option",
+			output: &testOutput{name: "matrix", datatype: INT32, shape: []int64{1, 2, 2}},
+			raw:    int32Bytes(1, 2, 3, 4),
+			want:   "len(shape) > 2 is not yet supported",
+		},
+		{
+			name:   "batch above 1",
+			output: &testOutput{name: "matrix", datatype: INT32, shape: []int64{2, 1, 2}},
+			raw:    int32Bytes(1, 2, 3, 4),
+			opts:   Options{SqueezeBatch: true},
+			want:   "len(shape) > 2 is not yet supported",
+		},
+		{
+			name:   "rank 4",
+			output: &testOutput{name: "matrix", datatype: INT32, shape: []int64{1, 1, 2, 2}},
+			raw:    int32Bytes(1, 2, 3, 4),
+			opts:   Options{SqueezeBatch: true},
+			want:   "len(shape) > 2 is not yet supported",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "matrix", datatype: INT32, shape: []int64{1, 2, 2}},
+			raw:    int32Bytes(1, 2, 3),
+			opts:   Options{SqueezeBatch: true},
+			want:   "binary read failed",
+		},
+		{
+			name:   "batch axis out of range",
+			output: &testOutput{name: "matrix", datatype: INT32, shape: []int64{1, 2, 2}},
+			raw:    int32Bytes(1, 2, 3, 4),
+			opts:   Options{SqueezeBatch: true, BatchAxis: 3},
+			want:   "batch axis 3 is out of range of shape [1 2 2]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSqueezeTrailing(t *testing.T) {
+	type result struct {
+		Scores  []float32 `triton:"scores"`
+		Matrix  [][]int32 `triton:"matrix"`
+		Labels  []string  `triton:"labels"`
+		Single  float32   `triton:"single"`
+		Batched []int64   `triton:"batched"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "scores", datatype: FLOAT32, shape: []int64{3, 1}},
+			{name: "matrix", datatype: INT32, shape: []int64{2, 2, 1}},
+			{name: "labels", datatype: STRING, shape: []int64{2, 1}},
+			{name: "single", datatype: FLOAT32, shape: []int64{1, 1}},
+			{name: "batched", datatype: INT64, shape: []int64{1, 2, 1}},
+		},
+		raw: [][]byte{
+			float32Bytes(0.5, 1.5, 2.5),
+			int32Bytes(1, 2, 3, 4),
+			encodeStrings("a", "b"),
+			float32Bytes(4),
+			int64Bytes(5, 6),
+		},
+	}
+
+	var res result
+	if err := UnmarshalWithOptions(resp, &res, Options{SqueezeTrailing: true, SqueezeBatch: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Scores:  []float32{0.5, 1.5, 2.5},
+		Matrix:  [][]int32{{1, 2}, {3, 4}},
+		Labels:  []string{"a", "b"},
+		Single:  4,
+		Batched: []int64{5, 6},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestSqueezeTrailingErrors(t *testing.T) {
+	var res struct {
+		Scores []float32 `triton:"scores"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		opts   Options
+		want   string
+	}{
+		{
+			name:   "without option",
+			output: &testOutput{name: "scores", datatype: FLOAT32, shape: []int64{2, 1}},
+			raw:    float32Bytes(1, 2),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "trailing above 1",
+			output: &testOutput{name: "scores", datatype: FLOAT32, shape: []int64{2, 2}},
+			raw:    float32Bytes(1, 2, 3, 4),
+			opts:   Options{SqueezeTrailing: true},
+			want:   "types doesn't match",
+		},
+		{
+			name:   "rank 4",
+			output: &testOutput{name: "scores", datatype: FLOAT32, shape: []int64{2, 2, 2, 1}},
+			raw:    float32Bytes(1, 2, 3, 4, 5, 6, 7, 8),
+			opts:   Options{SqueezeTrailing: true},
+			want:   "len(shape) > 2 is not yet supported",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "scores", datatype: FLOAT32, shape: []int64{3, 1}},
+			raw:    float32Bytes(1, 2, 3)[:10],
+			opts:   Options{SqueezeTrailing: true},
+			want:   "not a multiple of element size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBatchConsistency(t *testing.T) {
+	type result struct {
+		Boxes  [][]float32 `triton:"boxes"`
+		Scores [][]float32 `triton:"scores"`
+		Probs  []float32   `triton:"probs"`
+		Labels []string    `triton:"labels"`
+	}
+
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		opts    Options
+		want    string
+	}{
+		{
+			name: "consistent",
+			outputs: []*testOutput{
+				{name: "boxes", datatype: FLOAT32, shape: []int64{2, 2}},
+				{name: "scores", datatype: FLOAT32, shape: []int64{2, 1}},
+				{name: "labels", datatype: STRING, shape: []int64{2}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2, 3, 4), float32Bytes(5, 6), encodeStrings("a", "b")},
+			opts: Options{ValidateBatchConsistency: true},
+		},
+		{
+			name: "unmatched outputs are ignored",
+			outputs: []*testOutput{
+				{name: "boxes", datatype: FLOAT32, shape: []int64{2, 2}},
+				{name: "other", datatype: FLOAT32, shape: []int64{3, 1}},
+				{name: "scores", datatype: FLOAT32, shape: []int64{3, 1}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2, 3, 4), float32Bytes(5, 6, 7), float32Bytes(5, 6, 7)},
+			opts: Options{ValidateBatchConsistency: true, Skip: []string{"scores"}},
+		},
+		{
+			name: "inconsistent without validation",
+			outputs: []*testOutput{
+				{name: "boxes", datatype: FLOAT32, shape: []int64{2, 1}},
+				{name: "scores", datatype: FLOAT32, shape: []int64{3, 1}},
+			},
+			raw: [][]byte{float32Bytes(1, 2), float32Bytes(3, 4, 5)},
+		},
+		{
+			name: "inconsistent matrix",
+			outputs: []*testOutput{
+				{name: "boxes", datatype: FLOAT32, shape: []int64{2, 2}},
+				{name: "scores", datatype: FLOAT32, shape: []int64{3, 1}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2, 3, 4), float32Bytes(5, 6, 7)},
+			opts: Options{ValidateBatchConsistency: true},
+			want: "output scores: batch size 3 doesn't match batch size 2 of output boxes",
+		},
+		{
+			name: "inconsistent vector",
+			outputs: []*testOutput{
+				{name: "probs", datatype: FLOAT32, shape: []int64{1, 2}},
+				{name: "labels", datatype: STRING, shape: []int64{2}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2), encodeStrings("a", "b")},
+			opts: Options{ValidateBatchConsistency: true},
+			want: "output labels: batch size 2 doesn't match batch size 1 of output probs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res result
+
+			err := UnmarshalWithOptions(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res, tt.opts)
+			if tt.want == "" && err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchAxis(t *testing.T) {
+	type result struct {
+		Columns [][]int32  `triton:"columns"`
+		Labels  [][]string `triton:"labels"`
+		Vector  []int32    `triton:"vector"`
+	}
+
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		opts    Options
+		want    result
+	}{
+		{
+			name: "leading",
+			outputs: []*testOutput{
+				{name: "columns", datatype: INT32, shape: []int64{2, 3}},
+			},
+			raw:  [][]byte{int32Bytes(1, 2, 3, 4, 5, 6)},
+			want: result{Columns: [][]int32{{1, 2, 3}, {4, 5, 6}}},
+		},
+		{
+			name: "trailing",
+			outputs: []*testOutput{
+				{name: "columns", datatype: INT32, shape: []int64{3, 2}},
+				{name: "labels", datatype: STRING, shape: []int64{2, 2}},
+			},
+			raw:  [][]byte{int32Bytes(1, 2, 3, 4, 5, 6), encodeStrings("a", "b", "c", "d")},
+			opts: Options{BatchAxis: 1},
+			want: result{Columns: [][]int32{{1, 3, 5}, {2, 4, 6}}, Labels: [][]string{{"a", "c"}, {"b", "d"}}},
+		},
+		{
+			name: "trailing column-major",
+			outputs: []*testOutput{
+				{name: "columns", datatype: INT32, shape: []int64{3, 2}},
+			},
+			raw:  [][]byte{int32Bytes(1, 2, 3, 4, 5, 6)},
+			opts: Options{BatchAxis: 1, ColumnMajor: true},
+			want: result{Columns: [][]int32{{1, 2, 3}, {4, 5, 6}}},
+		},
+		{
+			name: "one-dimensional output",
+			outputs: []*testOutput{
+				{name: "vector", datatype: INT32, shape: []int64{3}},
+			},
+			raw:  [][]byte{int32Bytes(1, 2, 3)},
+			opts: Options{BatchAxis: 1},
+			want: result{Vector: []int32{1, 2, 3}},
+		},
+		{
+			name: "consistent trailing batches",
+			outputs: []*testOutput{
+				{name: "columns", datatype: INT32, shape: []int64{3, 2}},
+				{name: "labels", datatype: STRING, shape: []int64{1, 2}},
+				{name: "vector", datatype: INT32, shape: []int64{3}},
+			},
+			raw:  [][]byte{int32Bytes(1, 2, 3, 4, 5, 6), encodeStrings("a", "b"), int32Bytes(7, 8, 9)},
+			opts: Options{BatchAxis: 1, ValidateBatchConsistency: true},
+			want: result{
+				Columns: [][]int32{{1, 3, 5}, {2, 4, 6}},
+				Labels:  [][]string{{"a"}, {"b"}},
+				Vector:  []int32{7, 8, 9},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res result
+			if err := UnmarshalWithOptions(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchAxisErrors(t *testing.T) {
+	var res struct {
+		Columns [][]int32 `triton:"columns"`
+		Other   [][]int32 `triton:"other"`
+	}
+
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		opts    Options
+		want    string
+	}{
+		{
+			name:    "negative",
+			outputs: []*testOutput{{name: "columns", datatype: INT32, shape: []int64{2, 2}}},
+			raw:     [][]byte{int32Bytes(1, 2, 3, 4)},
+			opts:    Options{BatchAxis: -1},
+			want:    "invalid batch axis: -1",
+		},
+		{
+			name:    "out of range",
+			outputs: []*testOutput{{name: "columns", datatype: INT32, shape: []int64{2, 2}}},
+			raw:     [][]byte{int32Bytes(1, 2, 3, 4)},
+			opts:    Options{BatchAxis: 2},
+			want:    "output columns: batch axis 2 is out of range of shape [2 2]",
+		},
+		{
+			name: "inconsistent",
+			outputs: []*testOutput{
+				{name: "columns", datatype: INT32, shape: []int64{1, 2}},
+				{name: "other", datatype: INT32, shape: []int64{2, 3}},
+			},
+			raw:  [][]byte{int32Bytes(1, 2), int32Bytes(1, 2, 3, 4, 5, 6)},
+			opts: Options{BatchAxis: 1, ValidateBatchConsistency: true},
+			want: "output other: batch size 3 doesn't match batch size 2 of output columns",
+		},
+		{
+			name:    "short",
+			outputs: []*testOutput{{name: "columns", datatype: INT32, shape: []int64{3, 2}}},
+			raw:     [][]byte{int32Bytes(1, 2, 3, 4, 5)},
+			opts:    Options{BatchAxis: 1},
+			want:    "binary read failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := UnmarshalWithOptions(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package tritonparser
+
+import (
+	"fmt"
+	"math"
+)
+
+// Parameters of outputs returned in shared memory region.
+const (
+	shmRegionParam   = "shared_memory_region"
+	shmOffsetParam   = "shared_memory_offset"
+	shmByteSizeParam = "shared_memory_byte_size"
+)
+
+// rawContents returns raw contents of output i. Contents of outputs whose parameters
+// refer to shared memory region are read with Options.SharedMemoryResolver.
+func rawContents[T TritonModelInferResponseOutputs](output T, i int, rawBytes [][]byte, opts *Options) ([]byte, error) {
+	if opts.SharedMemoryResolver != nil {
+		if params, ok := getParameters(output); ok {
+			if region, ok := params[shmRegionParam].(string); ok {
+				return resolveSharedMemory(output, region, params, opts)
+			}
+		}
+	}
+
+	if i >= len(rawBytes) {
+		return nil, fmt.Errorf("output %s: raw contents are missing", output.GetName())
+	}
+
+	return rawBytes[i], nil
+}
+
+func resolveSharedMemory(output TritonModelInferResponseOutputs, region string, params map[string]any, opts *Options) ([]byte, error) {
+	offset, ok := intParameter(params[shmOffsetParam])
+	if !ok && params[shmOffsetParam] != nil {
+		return nil, fmt.Errorf("output %s: invalid %s: %v", output.GetName(), shmOffsetParam, params[shmOffsetParam])
+	}
+
+	size, ok := intParameter(params[shmByteSizeParam])
+	if !ok {
+		return nil, fmt.Errorf("output %s: invalid %s: %v", output.GetName(), shmByteSizeParam, params[shmByteSizeParam])
+	}
+
+	if opts.Trace != nil {
+		opts.tracef("output %s: reading %d bytes at %d of shared memory region %s", output.GetName(), size, offset, region)
+	}
+
+	b, err := opts.SharedMemoryResolver(region, offset, size)
+	if err != nil {
+		return nil, fmt.Errorf("output %s: shared memory region %s: %w", output.GetName(), region, err)
+	}
+
+	return b, nil
+}
+
+// intParameter returns value of integer parameter, which is int64 or uint64 in InferParameter.
+func intParameter(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, n >= 0
+	case uint64:
+		return int64(n), n <= math.MaxInt64
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,155 @@
+package tritonparser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type protoParameterUint64 struct{ Uint64Param uint64 }
+
+func (*protoParameterUint64) isChoice() {}
+
+// shmOutput returns output whose contents are size bytes at offset of shared memory region.
+func shmOutput(name, datatype string, shape []int64, region string, offset, size int64) *protoOutput {
+	params := map[string]*protoParameter{
+		shmRegionParam:   {choice: &protoParameterString{StringParam: region}},
+		shmByteSizeParam: {choice: &protoParameterInt64{Int64Param: size}},
+	}
+	if offset >= 0 {
+		params[shmOffsetParam] = &protoParameter{choice: &protoParameterUint64{Uint64Param: uint64(offset)}}
+	}
+
+	return &protoOutput{testOutput: testOutput{name: name, datatype: datatype, shape: shape}, params: params}
+}
+
+// shmResolver resolves regions of memory.
+func shmResolver(memory map[string][]byte) func(region string, offset, size int64) ([]byte, error) {
+	return func(region string, offset, size int64) ([]byte, error) {
+		b, ok := memory[region]
+		if !ok {
+			return nil, errors.New("unknown region")
+		}
+
+		if offset+size > int64(len(b)) {
+			return nil, fmt.Errorf("%d bytes at %d exceed region of %d bytes", size, offset, len(b))
+		}
+
+		return b[offset : offset+size], nil
+	}
+}
+
+func TestSharedMemory(t *testing.T) {
+	var res struct {
+		Scores []float32 `triton:"scores"`
+		Labels []string  `triton:"labels"`
+		Inline int32     `triton:"inline"`
+	}
+
+	labels := encodeStrings("a", "b")
+	memory := map[string][]byte{
+		"floats":  append([]byte{0xff, 0xff}, float32Bytes(1, 2)...),
+		"strings": labels,
+	}
+
+	resp := &Response{
+		Outputs: []TritonModelInferResponseOutputs{
+			shmOutput("scores", FLOAT32, []int64{1, 2}, "floats", 2, 8),
+			shmOutput("labels", STRING, []int64{1, 2}, "strings", -1, int64(len(labels))),
+			&protoOutput{testOutput: testOutput{name: "inline", datatype: INT32, shape: []int64{1}}},
+		},
+		RawOutputContents: [][]byte{nil, nil, int32Bytes(3)},
+	}
+
+	if err := UnmarshalWithOptions(resp, &res, Options{SharedMemoryResolver: shmResolver(memory)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res.Scores, []float32{1, 2}) || !reflect.DeepEqual(res.Labels, []string{"a", "b"}) || res.Inline != 3 {
+		t.Errorf("got %+v, want scores and labels from shared memory and inline output", res)
+	}
+}
+
+func TestSharedMemoryErrors(t *testing.T) {
+	var res struct {
+		Scores []float32 `triton:"scores"`
+	}
+
+	memory := map[string][]byte{"floats": float32Bytes(1, 2)}
+
+	withParam := func(name string, param *protoParameter) *protoOutput {
+		o := shmOutput("scores", FLOAT32, []int64{1, 2}, "floats", 0, 8)
+		o.params[name] = param
+
+		return o
+	}
+
+	tests := []struct {
+		name   string
+		output *protoOutput
+		want   string
+	}{
+		{
+			name:   "unknown region",
+			output: shmOutput("scores", FLOAT32, []int64{1, 2}, "ints", 0, 8),
+			want:   "output scores: shared memory region ints: unknown region",
+		},
+		{
+			name:   "out of region",
+			output: shmOutput("scores", FLOAT32, []int64{1, 2}, "floats", 4, 8),
+			want:   "output scores: shared memory region floats: 8 bytes at 4 exceed region of 8 bytes",
+		},
+		{
+			name:   "short",
+			output: shmOutput("scores", FLOAT32, []int64{1, 2}, "floats", 0, 7),
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "missing size",
+			output: withParam(shmByteSizeParam, &protoParameter{}),
+			want:   "output scores: invalid shared_memory_byte_size: <nil>",
+		},
+		{
+			name:   "negative size",
+			output: withParam(shmByteSizeParam, &protoParameter{choice: &protoParameterInt64{Int64Param: -1}}),
+			want:   "output scores: invalid shared_memory_byte_size: -1",
+		},
+		{
+			name:   "offset above max int64",
+			output: withParam(shmOffsetParam, &protoParameter{choice: &protoParameterUint64{Uint64Param: math.MaxUint64}}),
+			want:   "output scores: invalid shared_memory_offset: 18446744073709551615",
+		},
+		{
+			name:   "string offset",
+			output: withParam(shmOffsetParam, &protoParameter{choice: &protoParameterString{StringParam: "0"}}),
+			want:   "output scores: invalid shared_memory_offset: 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &Response{Outputs: []TritonModelInferResponseOutputs{tt.output}}
+
+			err := UnmarshalWithOptions(resp, &res, Options{SharedMemoryResolver: shmResolver(memory)})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSharedMemoryWithoutResolver(t *testing.T) {
+	var res struct {
+		Scores []float32 `triton:"scores"`
+	}
+
+	resp := &Response{Outputs: []TritonModelInferResponseOutputs{shmOutput("scores", FLOAT32, []int64{1, 2}, "floats", 0, 8)}}
+
+	err := Unmarshal(resp, &res)
+	if err == nil || !strings.Contains(err.Error(), "output scores: raw contents are missing") {
+		t.Fatalf("got error %v, want missing raw contents", err)
+	}
+}
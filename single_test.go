@@ -0,0 +1,97 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalSingle(t *testing.T) {
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "probs", datatype: FLOAT32, shape: []int64{1, 2}},
+			{name: "ids", datatype: INT32, shape: []int64{2, 2}},
+		},
+		raw: [][]byte{float32Bytes(0.25, 0.75), int32Bytes(1, 2, 3, 4)},
+	}
+
+	var probs []float32
+	if err := UnmarshalWithOptions(resp, &probs, Options{Only: []string{"probs"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []float32{0.25, 0.75}; !reflect.DeepEqual(probs, want) {
+		t.Errorf("got %v, want %v", probs, want)
+	}
+
+	var ids [][]int32
+	if err := UnmarshalWithOptions(resp, &ids, Options{Skip: []string{"probs"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := [][]int32{{1, 2}, {3, 4}}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got %v, want %v", ids, want)
+	}
+
+	var label string
+
+	single := &testResponse{
+		outputs: []*testOutput{{name: "label", datatype: STRING, shape: []int64{1}}},
+		raw:     [][]byte{encodeStrings("cat")},
+	}
+
+	if err := Unmarshal(single, &label); err != nil {
+		t.Fatal(err)
+	}
+
+	if label != "cat" {
+		t.Errorf("got %q, want cat", label)
+	}
+}
+
+func TestUnmarshalSingleErrors(t *testing.T) {
+	two := &testResponse{
+		outputs: []*testOutput{
+			{name: "a", datatype: INT32, shape: []int64{1, 1}},
+			{name: "b", datatype: INT32, shape: []int64{1, 1}},
+		},
+		raw: [][]byte{int32Bytes(1), int32Bytes(2)},
+	}
+
+	tests := []struct {
+		name string
+		resp *testResponse
+		opts Options
+		want string
+	}{
+		{name: "no outputs", resp: &testResponse{}, want: "exactly one output, got none"},
+		{name: "two outputs", resp: two, want: "exactly one output, got outputs a and b"},
+		{name: "filtered out", resp: two, opts: Options{Only: []string{"c"}}, want: "got none"},
+		{
+			name: "type",
+			resp: &testResponse{outputs: []*testOutput{{name: "a", datatype: INT64, shape: []int64{1, 1}}}, raw: [][]byte{int64Bytes(1)}},
+			want: "types doesn't match",
+		},
+		{
+			name: "length",
+			resp: &testResponse{outputs: []*testOutput{{name: "a", datatype: INT32, shape: []int64{1, 2}}}, raw: [][]byte{{1, 2, 3}}},
+			want: "output a",
+		},
+		{
+			name: "missing contents",
+			resp: &testResponse{outputs: []*testOutput{{name: "a", datatype: INT32, shape: []int64{1, 1}}}},
+			want: "raw contents are missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res []int32
+
+			err := UnmarshalWithOptions(tt.resp, &res, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
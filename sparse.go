@@ -0,0 +1,170 @@
+package tritonparser
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// Sparse is a sparse vector of values at given indices.
+type Sparse[T any] struct {
+	Indices []int64
+	Values  []T
+}
+
+// sparse is implemented by *Sparse of any element type.
+type sparse interface {
+	values() reflect.Value
+	setIndices(indices []int64)
+}
+
+func (s *Sparse[T]) values() reflect.Value {
+	return reflect.ValueOf(&s.Values).Elem()
+}
+
+func (s *Sparse[T]) setIndices(indices []int64) {
+	s.Indices = indices
+}
+
+// getSparse returns sparse of field if it's a Sparse.
+func getSparse(field reflect.Value) (sparse, bool) {
+	if !field.CanAddr() {
+		return nil, false
+	}
+
+	s, ok := field.Addr().Interface().(sparse)
+
+	return s, ok
+}
+
+// sparseField is a field receiving values output paired with indices output.
+type sparseField struct {
+	name    string
+	indices string
+	field   reflect.Value
+}
+
+// getSparseFields returns fields tagged with indices option in order of fields declaration.
+func getSparseFields(rv reflect.Value, opts *Options) []sparseField {
+	fieldsNum := rv.Elem().NumField()
+	var res []sparseField
+
+	for i := 0; i < fieldsNum; i++ {
+		name, tagOpts := parseTag(opts.fieldTag(rv.Elem().Type().Field(i)))
+		if indices, ok := tagOpts.Get(tagIndices); ok {
+			res = append(res, sparseField{name: name, indices: indices, field: rv.Elem().Field(i)})
+		}
+	}
+
+	return res
+}
+
+// decodeSparse decodes values and indices outputs of sf into its Sparse or map field with int64 keys.
+// Field is untouched and false is returned if none of outputs is present.
+func decodeSparse[T TritonModelInferResponseOutputs](sf sparseField, outputs []T, rawBytes [][]byte, opts *Options) (bool, error) {
+	vi, ii := -1, -1
+	for i, o := range outputs {
+		switch o.GetName() {
+		case sf.name:
+			vi = i
+		case sf.indices:
+			ii = i
+		}
+	}
+
+	switch {
+	case vi == -1 && ii == -1:
+		return false, nil
+	case vi == -1:
+		return false, fmt.Errorf("output %s is missing, while its %s output %s is present", sf.name, tagIndices, sf.indices)
+	case ii == -1:
+		return false, fmt.Errorf("output %s: %s output %s is missing", sf.name, tagIndices, sf.indices)
+	}
+
+	values, err := decodeVector(outputs[vi], vi, rawBytes, opts)
+	if err != nil {
+		return false, err
+	}
+
+	indices, err := decodeIntegers(outputs[ii], ii, rawBytes, opts)
+	if err != nil {
+		return false, fmt.Errorf("output %s: %s option: %w", sf.name, tagIndices, err)
+	}
+
+	if len(indices) != values.Len() {
+		return false, fmt.Errorf("output %s: %d values don't match %d indices of output %s", sf.name, values.Len(), len(indices), sf.indices)
+	}
+
+	if opts.Trace != nil {
+		opts.tracef("output %s: %d sparse values at indices of output %s", sf.name, len(indices), sf.indices)
+	}
+
+	if s, ok := getSparse(sf.field); ok {
+		if err := setTransformed(s.values(), values, sf.name, opts); err != nil {
+			return false, err
+		}
+
+		s.setIndices(indices)
+
+		return true, nil
+	}
+
+	t := sf.field.Type()
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.Int64 {
+		return false, fmt.Errorf("output %s: %s option requires Sparse or map field with int64 keys, got %s", sf.name, tagIndices, t)
+	}
+
+	vals := reflect.New(reflect.SliceOf(t.Elem())).Elem()
+	if err := setTransformed(vals, values, sf.name, opts); err != nil {
+		return false, err
+	}
+
+	// indices are validated before field is touched, so it's left as is on error.
+	sorted := slices.Sorted(slices.Values(indices))
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] == sorted[i-1] {
+			return false, fmt.Errorf("output %s: duplicate index %d", sf.name, sorted[i])
+		}
+	}
+
+	res := reuseMap(sf.field, len(indices))
+	for i, idx := range indices {
+		res.SetMapIndex(reflect.ValueOf(idx).Convert(t.Key()), vals.Index(i))
+	}
+
+	sf.field.Set(res)
+
+	return true, nil
+}
+
+// decodeIntegers decodes one-dimensional integer output i into int64 values, e.g. indices.
+func decodeIntegers[T TritonModelInferResponseOutputs](o T, i int, rawBytes [][]byte, opts *Options) ([]int64, error) {
+	val, err := decodeVector(o, i, rawBytes, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	k := val.Type().Elem().Kind()
+	if !isInt(k) && !isUint(k) {
+		return nil, fmt.Errorf("output %s: integer datatype required, got %s", o.GetName(), o.GetDatatype())
+	}
+
+	res := make([]int64, val.Len())
+	for j := range res {
+		v := val.Index(j)
+		if isInt(k) {
+			res[j] = v.Int()
+
+			continue
+		}
+
+		if v.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("output %s: value %d overflows int64", o.GetName(), v.Uint())
+		}
+
+		res[j] = int64(v.Uint())
+	}
+
+	return res, nil
+}
@@ -0,0 +1,156 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSparse(t *testing.T) {
+	type result struct {
+		Map    map[int64]float32 `triton:"feat,indices=idx"`
+		Sparse Sparse[float32]   `triton:"vals,indices=pos"`
+	}
+
+	tests := []struct {
+		name string
+		resp *testResponse
+		want result
+	}{
+		{
+			name: "int64 indices",
+			resp: &testResponse{
+				outputs: []*testOutput{
+					{name: "feat", datatype: FLOAT32, shape: []int64{1, 2}},
+					{name: "idx", datatype: INT64, shape: []int64{1, 2}},
+					{name: "vals", datatype: FLOAT32, shape: []int64{2}},
+					{name: "pos", datatype: INT64, shape: []int64{2}},
+				},
+				raw: [][]byte{float32Bytes(0.5, 1.5), int64Bytes(3, 7), float32Bytes(2, 4), int64Bytes(1, 0)},
+			},
+			want: result{
+				Map:    map[int64]float32{3: 0.5, 7: 1.5},
+				Sparse: Sparse[float32]{Indices: []int64{1, 0}, Values: []float32{2, 4}},
+			},
+		},
+		{
+			name: "int32 indices",
+			resp: &testResponse{
+				outputs: []*testOutput{
+					{name: "feat", datatype: FLOAT32, shape: []int64{1}},
+					{name: "idx", datatype: INT32, shape: []int64{1}},
+				},
+				raw: [][]byte{float32Bytes(0.25), int32Bytes(9)},
+			},
+			want: result{Map: map[int64]float32{9: 0.25}},
+		},
+		{
+			name: "empty",
+			resp: &testResponse{
+				outputs: []*testOutput{
+					{name: "vals", datatype: FLOAT32, shape: []int64{0}},
+					{name: "pos", datatype: INT64, shape: []int64{0}},
+				},
+				raw: [][]byte{nil, nil},
+			},
+			want: result{Sparse: Sparse[float32]{Indices: []int64{}, Values: []float32{}}},
+		},
+		{
+			name: "absent",
+			resp: &testResponse{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res result
+			if err := Unmarshal(tt.resp, &res); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestSparseErrors(t *testing.T) {
+	var res struct {
+		Map map[int64]float32 `triton:"feat,indices=idx"`
+	}
+
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		want    string
+	}{
+		{
+			name: "length mismatch",
+			outputs: []*testOutput{
+				{name: "feat", datatype: FLOAT32, shape: []int64{2}},
+				{name: "idx", datatype: INT64, shape: []int64{3}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2), int64Bytes(0, 1, 2)},
+			want: "2 values don't match 3 indices",
+		},
+		{
+			name: "float indices",
+			outputs: []*testOutput{
+				{name: "feat", datatype: FLOAT32, shape: []int64{1}},
+				{name: "idx", datatype: FLOAT32, shape: []int64{1}},
+			},
+			raw:  [][]byte{float32Bytes(1), float32Bytes(0)},
+			want: "integer datatype required",
+		},
+		{
+			name: "duplicate index",
+			outputs: []*testOutput{
+				{name: "feat", datatype: FLOAT32, shape: []int64{2}},
+				{name: "idx", datatype: INT64, shape: []int64{2}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2), int64Bytes(4, 4)},
+			want: "duplicate index 4",
+		},
+		{
+			name:    "missing indices",
+			outputs: []*testOutput{{name: "feat", datatype: FLOAT32, shape: []int64{1}}},
+			raw:     [][]byte{float32Bytes(1)},
+			want:    "indices output idx is missing",
+		},
+		{
+			name:    "missing values",
+			outputs: []*testOutput{{name: "idx", datatype: INT64, shape: []int64{1}}},
+			raw:     [][]byte{int64Bytes(0)},
+			want:    "output feat is missing",
+		},
+		{
+			name: "short values",
+			outputs: []*testOutput{
+				{name: "feat", datatype: FLOAT32, shape: []int64{2}},
+				{name: "idx", datatype: INT64, shape: []int64{2}},
+			},
+			raw:  [][]byte{float32Bytes(1)[:3], int64Bytes(0, 1)},
+			want: "feat",
+		},
+		{
+			name: "matrix indices",
+			outputs: []*testOutput{
+				{name: "feat", datatype: FLOAT32, shape: []int64{2}},
+				{name: "idx", datatype: INT64, shape: []int64{2, 1}},
+			},
+			raw:  [][]byte{float32Bytes(1, 2), int64Bytes(0, 1)},
+			want: "idx",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
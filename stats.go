@@ -0,0 +1,53 @@
+package tritonparser
+
+// DecodeStats is filled with aggregate statistics of decoding, if set in Options.
+// It's reset at the start of every decoding.
+type DecodeStats struct {
+	// Bytes is the total length of raw contents of decoded outputs, before decompression.
+	Bytes int
+	// Elements is the total number of elements of decoded outputs, as given by their shapes.
+	Elements int
+	// Decoded is the number of outputs decoded into fields, including parts of concat and indices options.
+	Decoded int
+	// Skipped is the number of outputs matching fields, but filtered out by Options.Only or Options.Skip.
+	Skipped int
+	// Unmatched is the number of outputs without field.
+	Unmatched int
+}
+
+// reset zeroes s, if it's not nil.
+func (s *DecodeStats) reset() {
+	if s != nil {
+		*s = DecodeStats{}
+	}
+}
+
+// add accounts decoded output with raw contents to s, if it's not nil.
+func (s *DecodeStats) add(output TritonModelInferResponseOutputs, raw []byte) {
+	if s == nil {
+		return
+	}
+
+	count := 1
+	for _, dim := range output.GetShape() {
+		count *= int(max(dim, 0))
+	}
+
+	s.Bytes += len(raw)
+	s.Elements += count
+	s.Decoded++
+}
+
+// skip accounts n outputs filtered out by options to s, if it's not nil.
+func (s *DecodeStats) skip(n int) {
+	if s != nil {
+		s.Skipped += n
+	}
+}
+
+// finish accounts outputs that were neither decoded nor skipped as unmatched.
+func (s *DecodeStats) finish(outputs int) {
+	if s != nil {
+		s.Unmatched = max(outputs-s.Decoded-s.Skipped, 0)
+	}
+}
@@ -0,0 +1,72 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+type statsInner struct {
+	Y int32 `triton:"y"`
+}
+
+func nestedStatsResponse(nested ...string) *Response {
+	outputs := []TritonModelInferResponseOutputs{&httpOutput{Name: "x", Datatype: INT32, Shape: []int64{2}}}
+	raw := [][]byte{binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint32(nil, 1), 2)}
+
+	for _, name := range nested {
+		outputs = append(outputs, &httpOutput{Name: name, Datatype: STRING, Shape: []int64{1}})
+		raw = append(raw, encodeStrings("inner"))
+	}
+
+	outputs = append(outputs, &httpOutput{Name: "extra", Datatype: INT32, Shape: []int64{1}})
+	raw = append(raw, []byte{3, 0, 0, 0})
+
+	return &Response{Outputs: outputs, RawOutputContents: raw}
+}
+
+func decodeInner([]byte) (*Response, error) {
+	return &Response{
+		Outputs:           []TritonModelInferResponseOutputs{&httpOutput{Name: "y", Datatype: INT32, Shape: []int64{1}}, &httpOutput{Name: "z", Datatype: INT32, Shape: []int64{1}}},
+		RawOutputContents: [][]byte{{7, 0, 0, 0}, {8, 0, 0, 0}},
+	}, nil
+}
+
+func TestStatsNested(t *testing.T) {
+	var res struct {
+		X []int32    `triton:"x"`
+		N statsInner `triton:"n,nested"`
+	}
+
+	var stats DecodeStats
+	if err := UnmarshalWithOptions(nestedStatsResponse("n"), &res, Options{NestedDecoder: decodeInner, Stats: &stats}); err != nil {
+		t.Fatal(err)
+	}
+
+	// inner outputs are not accounted to stats of outer response.
+	want := DecodeStats{Bytes: 17, Elements: 3, Decoded: 2, Unmatched: 1}
+	if stats != want {
+		t.Errorf("got %+v, want %+v", stats, want)
+	}
+
+	if res.N.Y != 7 {
+		t.Errorf("nested field: got %d, want 7", res.N.Y)
+	}
+}
+
+func TestStatsNestedParallel(t *testing.T) {
+	var res struct {
+		X []int32    `triton:"x"`
+		A statsInner `triton:"a,nested"`
+		B statsInner `triton:"b,nested"`
+	}
+
+	var stats DecodeStats
+	opts := Options{NestedDecoder: decodeInner, Stats: &stats, Parallelism: 2}
+	if err := UnmarshalWithOptions(nestedStatsResponse("a", "b"), &res, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Decoded != 3 || stats.Unmatched != 1 {
+		t.Errorf("got %+v, want 3 decoded and 1 unmatched", stats)
+	}
+}
@@ -0,0 +1,127 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// DecodeToChannel decodes elements of output in row-major order and sends them to ch one by one,
+// so consumer may start processing before whole output is decoded.
+// T must match datatype of output, e.g. float32 for FP32 or FP16 and string for BYTES.
+// ch is closed when decoding is finished or failed.
+func DecodeToChannel[T any](output TritonModelInferResponseOutputs, raw []byte, ch chan<- T) error {
+	defer close(ch)
+
+	elements, err := Elements[T](output, raw)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range elements {
+		ch <- v
+	}
+
+	return nil
+}
+
+// Elements returns iterator over index and value of elements of output in row-major order,
+// decoding them lazily, so whole output is never allocated.
+// T must match datatype of output the same way as in DecodeToChannel. Raw contents are little-endian.
+// They are validated before iterator is returned, so iteration can't fail.
+func Elements[T any](output TritonModelInferResponseOutputs, raw []byte) (iter.Seq2[int, T], error) {
+	var t T
+	exp, ok := elementType(output.GetDatatype())
+	if !ok {
+		return nil, fmt.Errorf("unkwnow type: %s", output.GetDatatype())
+	}
+
+	if exp != reflect.TypeOf(t) {
+		return nil, fmt.Errorf("types doesn't match exp: %s got: %T", exp.String(), t)
+	}
+
+	size := int(exp.Size())
+	count := 1
+
+	switch output.GetDatatype() {
+	case STRING:
+		for offset := 0; offset < len(raw); {
+			_, end, err := stringBounds(raw, offset, binary.LittleEndian, 4)
+			if err != nil {
+				return nil, fmt.Errorf("output %s: %w", output.GetName(), err)
+			}
+
+			offset = end
+		}
+	case INT4, UINT4:
+		for _, dim := range output.GetShape() {
+			if dim < 0 {
+				return nil, fmt.Errorf("invalid shape: %v", output.GetShape())
+			}
+
+			count *= int(dim)
+		}
+
+		if len(raw) != (count+1)/2 {
+			return nil, fmt.Errorf("output %s: raw contents length %d doesn't match %d packed 4-bit elements", output.GetName(), len(raw), count)
+		}
+	default:
+		if output.GetDatatype() == FLOAT16 {
+			size = 2
+		}
+
+		if len(raw)%size != 0 {
+			return nil, fmt.Errorf("output %s: raw contents length %d is not a multiple of element size %d", output.GetName(), len(raw), size)
+		}
+	}
+
+	return func(yield func(int, T) bool) {
+		switch output.GetDatatype() {
+		case STRING:
+			for i, offset := 0, 0; offset < len(raw); i++ {
+				start, end, _ := stringBounds(raw, offset, binary.LittleEndian, 4)
+				if !yield(i, as[T](string(raw[start:end]))) {
+					return
+				}
+
+				offset = end
+			}
+		case FLOAT16:
+			for i := 0; i < len(raw)/2; i++ {
+				if !yield(i, as[T](halfToFloat32(binary.LittleEndian.Uint16(raw[2*i:])))) {
+					return
+				}
+			}
+		case INT4, UINT4:
+			for i := 0; i < count; i++ {
+				n := raw[i/2] >> (4 * (i % 2)) & 0x0f
+				v := as[T](n)
+				if output.GetDatatype() == INT4 {
+					v = as[T](int8(n<<4) >> 4)
+				}
+
+				if !yield(i, v) {
+					return
+				}
+			}
+		default:
+			for i := 0; i < len(raw)/size; i++ {
+				var v T
+				// contents are validated, so element always fits.
+				_, _ = binary.Decode(raw[i*size:(i+1)*size], binary.LittleEndian, &v)
+
+				if !yield(i, v) {
+					return
+				}
+			}
+		}
+	}, nil
+}
+
+// as converts v to T, which is known to be the dynamic type of v.
+func as[T any](v any) T {
+	t, _ := v.(T)
+
+	return t
+}
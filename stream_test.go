@@ -0,0 +1,248 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func collect[T any](t *testing.T, output *testOutput, raw []byte) ([]T, error) {
+	t.Helper()
+
+	ch := make(chan T, 16)
+	err := DecodeToChannel(output, raw, ch)
+
+	var res []T
+	for v := range ch {
+		res = append(res, v)
+	}
+
+	return res, err
+}
+
+func TestDecodeToChannel(t *testing.T) {
+	ints, err := collect[int32](t, &testOutput{name: "v", datatype: INT32, shape: []int64{2, 2}}, int32Bytes(1, 2, 3, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int32{1, 2, 3, 4}; !reflect.DeepEqual(ints, want) {
+		t.Errorf("got %v, want %v", ints, want)
+	}
+
+	strs, err := collect[string](t, &testOutput{name: "v", datatype: STRING, shape: []int64{1, 2}}, encodeStrings("a", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"a", ""}; !reflect.DeepEqual(strs, want) {
+		t.Errorf("got %q, want %q", strs, want)
+	}
+
+	halfs, err := collect[float32](t, &testOutput{name: "v", datatype: FLOAT16, shape: []int64{1, 2}}, halfBytes(0x3c00, 0xc000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []float32{1, -2}; !reflect.DeepEqual(halfs, want) {
+		t.Errorf("got %v, want %v", halfs, want)
+	}
+
+	empty, err := collect[int32](t, &testOutput{name: "v", datatype: INT32, shape: []int64{1, 0}}, nil)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("got %v, %v, want no elements", empty, err)
+	}
+}
+
+func TestDecodeToChannelErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+	}{
+		{name: "type", output: &testOutput{name: "v", datatype: INT64, shape: []int64{1, 1}}, raw: int64Bytes(1)},
+		{name: "unknown datatype", output: &testOutput{name: "v", datatype: "COMPLEX", shape: []int64{1, 1}}, raw: int32Bytes(1)},
+		{name: "misaligned", output: &testOutput{name: "v", datatype: INT32, shape: []int64{1, 2}}, raw: int32Bytes(1, 2)[:7]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := collect[int32](t, tt.output, tt.raw)
+			if err == nil || len(got) != 0 {
+				t.Fatalf("got %v, %v, want error and no elements", got, err)
+			}
+		})
+	}
+
+	got, err := collect[string](t, &testOutput{name: "v", datatype: STRING, shape: []int64{1, 2}}, encodeStrings("a", "bc")[:9])
+	if err == nil || len(got) != 0 {
+		t.Fatalf("got %q, %v, want error and no elements", got, err)
+	}
+}
+
+// elements collects values of Elements of output until stop elements are yielded, if stop is positive.
+func elements[T any](t *testing.T, output *testOutput, raw []byte, stop int) []T {
+	t.Helper()
+
+	seq, err := Elements[T](output, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var res []T
+	for i, v := range seq {
+		if i != len(res) {
+			t.Fatalf("got index %d, want %d", i, len(res))
+		}
+
+		if res = append(res, v); len(res) == stop {
+			break
+		}
+	}
+
+	return res
+}
+
+func TestElements(t *testing.T) {
+	tests := []struct {
+		name string
+		got  func(t *testing.T) any
+		want any
+	}{
+		{
+			name: "int32",
+			got: func(t *testing.T) any {
+				return elements[int32](t, &testOutput{name: "v", datatype: INT32, shape: []int64{2, 2}}, int32Bytes(1, 2, 3, -4), 0)
+			},
+			want: []int32{1, 2, 3, -4},
+		},
+		{
+			name: "early break",
+			got: func(t *testing.T) any {
+				return elements[int32](t, &testOutput{name: "v", datatype: INT32, shape: []int64{1, 4}}, int32Bytes(1, 2, 3, 4), 2)
+			},
+			want: []int32{1, 2},
+		},
+		{
+			name: "strings",
+			got: func(t *testing.T) any {
+				return elements[string](t, &testOutput{name: "v", datatype: STRING, shape: []int64{3}}, encodeStrings("a", "", "bc"), 0)
+			},
+			want: []string{"a", "", "bc"},
+		},
+		{
+			name: "strings early break",
+			got: func(t *testing.T) any {
+				return elements[string](t, &testOutput{name: "v", datatype: STRING, shape: []int64{3}}, encodeStrings("a", "", "bc"), 1)
+			},
+			want: []string{"a"},
+		},
+		{
+			name: "float16",
+			got: func(t *testing.T) any {
+				return elements[float32](t, &testOutput{name: "v", datatype: FLOAT16, shape: []int64{2}}, halfBytes(0x3c00, 0xc000), 0)
+			},
+			want: []float32{1, -2},
+		},
+		{
+			name: "int4",
+			got: func(t *testing.T) any {
+				return elements[int8](t, &testOutput{name: "v", datatype: INT4, shape: []int64{3}}, []byte{0xf7, 0x08}, 0)
+			},
+			want: []int8{7, -1, -8},
+		},
+		{
+			name: "uint4",
+			got: func(t *testing.T) any {
+				return elements[uint8](t, &testOutput{name: "v", datatype: UINT4, shape: []int64{1, 2}}, []byte{0xf7}, 0)
+			},
+			want: []uint8{7, 15},
+		},
+		{
+			name: "empty",
+			got: func(t *testing.T) any {
+				return elements[float32](t, &testOutput{name: "v", datatype: FLOAT32, shape: []int64{0}}, nil, 0)
+			},
+			want: []float32(nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(t); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestElementsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "type",
+			output: &testOutput{name: "v", datatype: INT64, shape: []int64{1}},
+			raw:    int64Bytes(1),
+			want:   "types doesn't match exp: int64 got: int32",
+		},
+		{
+			name:   "unknown datatype",
+			output: &testOutput{name: "v", datatype: "COMPLEX", shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "COMPLEX",
+		},
+		{
+			name:   "misaligned",
+			output: &testOutput{name: "v", datatype: INT32, shape: []int64{2}},
+			raw:    int32Bytes(1, 2)[:7],
+			want:   "output v: raw contents length 7 is not a multiple of element size 4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Elements[int32](tt.output, tt.raw)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	packed := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "packed length",
+			output: &testOutput{name: "v", datatype: INT4, shape: []int64{3}},
+			raw:    []byte{0x21},
+			want:   "output v: raw contents length 1 doesn't match 3 packed 4-bit elements",
+		},
+		{
+			name:   "packed negative dimension",
+			output: &testOutput{name: "v", datatype: INT4, shape: []int64{-2}},
+			raw:    []byte{0x21},
+			want:   "invalid shape: [-2]",
+		},
+	}
+
+	for _, tt := range packed {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Elements[int8](tt.output, tt.raw)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	_, err := Elements[string](&testOutput{name: "v", datatype: STRING, shape: []int64{2}}, encodeStrings("a", "bc")[:9])
+	if err == nil || !strings.Contains(err.Error(), "output v") {
+		t.Fatalf("got error %v, want error of output v", err)
+	}
+}
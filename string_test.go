@@ -0,0 +1,579 @@
+package tritonparser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// testOutput is output of testResponse.
+type testOutput struct {
+	name     string
+	datatype string
+	shape    []int64
+}
+
+func (o *testOutput) GetName() string     { return o.name }
+func (o *testOutput) GetDatatype() string { return o.datatype }
+func (o *testOutput) GetShape() []int64   { return o.shape }
+
+// testResponse is a minimal inference response for tests.
+type testResponse struct {
+	outputs []*testOutput
+	raw     [][]byte
+}
+
+func (r *testResponse) GetOutputs() []*testOutput      { return r.outputs }
+func (r *testResponse) GetRawOutputContents() [][]byte { return r.raw }
+
+// encodeStrings encodes ss as BYTES raw contents, each element prefixed with its 4-byte little-endian length.
+func encodeStrings(ss ...string) []byte {
+	var b []byte
+	for _, s := range ss {
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+		b = append(b, s...)
+	}
+
+	return b
+}
+
+func TestStringBytesToArrayRoundTrip(t *testing.T) {
+	tests := [][]string{
+		{},
+		{""},
+		{"cat"},
+		{"cat", "", "dog"},
+		{"привет", "\x00\xff", string(make([]byte, 300))},
+	}
+
+	for _, want := range tests {
+		got, err := stringBytesToArray(encodeStrings(want...), len(want), binary.LittleEndian, &Options{})
+		if err != nil {
+			t.Fatalf("%q: %v", want, err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestStringBytesToArrayErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []byte
+		size int
+	}{
+		{name: "short prefix", raw: []byte{1, 0}, size: 1},
+		{name: "short string", raw: encodeStrings("cat")[:5], size: 1},
+		{name: "huge length", raw: []byte{0xff, 0xff, 0xff, 0xff, 'a'}, size: 1},
+		{name: "missing element", raw: encodeStrings("cat"), size: 2},
+		{name: "trailing bytes", raw: append(encodeStrings("cat"), 0), size: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := stringBytesToArray(tt.raw, tt.size, binary.LittleEndian, &Options{}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestUnmarshalStringsRoundTrip(t *testing.T) {
+	want := []string{"cat", "", "dog"}
+	resp := &testResponse{
+		outputs: []*testOutput{{name: "labels", datatype: STRING, shape: []int64{1, 3}}},
+		raw:     [][]byte{encodeStrings(want...)},
+	}
+
+	var res struct {
+		Labels []string `triton:"labels"`
+	}
+
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res.Labels, want) {
+		t.Errorf("got %q, want %q", res.Labels, want)
+	}
+}
+
+func TestUnmarshalStringScalarRoundTrip(t *testing.T) {
+	resp := &testResponse{
+		outputs: []*testOutput{{name: "label", datatype: STRING, shape: []int64{1}}},
+		raw:     [][]byte{encodeStrings("cat")},
+	}
+
+	var res struct {
+		Label string `triton:"label"`
+	}
+
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Label != "cat" {
+		t.Errorf("got %q, want %q", res.Label, "cat")
+	}
+}
+
+func TestUnmarshalStringMatrixRoundTrip(t *testing.T) {
+	want := [][]string{{"a", "bc"}, {"", "def"}}
+	resp := &testResponse{
+		outputs: []*testOutput{{name: "labels", datatype: STRING, shape: []int64{2, 2}}},
+		raw:     [][]byte{encodeStrings("a", "bc", "", "def")},
+	}
+
+	var res struct {
+		Labels [][]string `triton:"labels"`
+	}
+
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res.Labels, want) {
+		t.Errorf("got %q, want %q", res.Labels, want)
+	}
+}
+
+func FuzzStringBytesToArray(f *testing.F) {
+	f.Add(encodeStrings("cat", "dog"), uint8(2))
+	f.Add(encodeStrings(""), uint8(1))
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff}, uint8(1))
+	f.Add([]byte{3, 0, 0}, uint8(4))
+
+	f.Fuzz(func(t *testing.T, raw []byte, size uint8) {
+		arr, err := stringBytesToArray(raw, int(size), binary.LittleEndian, &Options{})
+		if err == nil {
+			if len(arr) != int(size) {
+				t.Fatalf("got %d elements, want %d", len(arr), size)
+			}
+
+			// decoded elements are encoded back into the same contents, as trailing bytes are rejected.
+			if !bytes.Equal(raw, encodeStrings(arr...)) {
+				t.Fatalf("round trip of %q differs", raw)
+			}
+		}
+
+		// the same contents decoded by the multidimensional variant must not panic either.
+		resp := &testResponse{
+			outputs: []*testOutput{{name: "labels", datatype: STRING, shape: []int64{2, int64(size)}}},
+			raw:     [][]byte{raw},
+		}
+
+		var res struct {
+			Labels [][]string `triton:"labels"`
+		}
+
+		_ = Unmarshal(resp, &res)
+	})
+}
+
+// encodeStrings64 encodes ss as BYTES raw contents with 8-byte length prefixes of order.
+func encodeStrings64(order binary.AppendByteOrder, ss ...string) []byte {
+	var b []byte
+	for _, s := range ss {
+		b = order.AppendUint64(b, uint64(len(s)))
+		b = append(b, s...)
+	}
+
+	return b
+}
+
+func TestStringLengthPrefix(t *testing.T) {
+	type result struct {
+		Label  string     `triton:"label"`
+		Labels []string   `triton:"labels"`
+		Matrix [][]string `triton:"matrix"`
+		Bytes  [][]byte   `triton:"bytes"`
+	}
+
+	tests := []struct {
+		name  string
+		order binary.AppendByteOrder
+		opts  Options
+	}{
+		{name: "little endian", order: binary.LittleEndian, opts: Options{StringLengthPrefix: 8}},
+		{name: "big endian", order: binary.BigEndian, opts: Options{StringLengthPrefix: 8, ByteOrder: binary.BigEndian}},
+		{name: "interned", order: binary.LittleEndian, opts: Options{StringLengthPrefix: 8, InternStrings: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{
+					{name: "label", datatype: STRING, shape: []int64{1}},
+					{name: "labels", datatype: STRING, shape: []int64{1, 3}},
+					{name: "matrix", datatype: STRING, shape: []int64{2, 1}},
+					{name: "bytes", datatype: STRING, shape: []int64{1, 2}},
+				},
+				raw: [][]byte{
+					encodeStrings64(tt.order, "cat"),
+					encodeStrings64(tt.order, "a", "", "bc"),
+					encodeStrings64(tt.order, "x", "y"),
+					encodeStrings64(tt.order, "\x00\x01", "z"),
+				},
+			}
+
+			var res result
+			if err := UnmarshalWithOptions(resp, &res, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			want := result{
+				Label:  "cat",
+				Labels: []string{"a", "", "bc"},
+				Matrix: [][]string{{"x"}, {"y"}},
+				Bytes:  [][]byte{{0, 1}, []byte("z")},
+			}
+			if !reflect.DeepEqual(res, want) {
+				t.Errorf("got %+v, want %+v", res, want)
+			}
+		})
+	}
+}
+
+func TestStringLengthPrefixErrors(t *testing.T) {
+	var res struct {
+		Labels []string `triton:"labels"`
+	}
+
+	tests := []struct {
+		name   string
+		raw    []byte
+		prefix int
+		want   string
+	}{
+		{name: "unsupported width", raw: encodeStrings("a"), prefix: 2, want: "unsupported string length prefix width: 2"},
+		{name: "short prefix", raw: encodeStrings("a")[:4], prefix: 8, want: "string length at offset 0"},
+		{name: "short element", raw: encodeStrings64(binary.LittleEndian, "ab")[:9], prefix: 8, want: "string of length 2 at offset 8"},
+		{name: "4-byte prefixes", raw: encodeStrings("ab", "cd"), prefix: 8, want: "output labels"},
+		{
+			name:   "huge length",
+			raw:    append(binary.LittleEndian.AppendUint64(nil, 1<<63), 'a'),
+			prefix: 8,
+			want:   "string of length 9223372036854775808 at offset 8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "labels", datatype: STRING, shape: []int64{1, 1}}},
+				raw:     [][]byte{tt.raw},
+			}
+
+			err := UnmarshalWithOptions(resp, &res, Options{StringLengthPrefix: tt.prefix})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestInternStrings(t *testing.T) {
+	type result struct {
+		Labels []string   `triton:"labels"`
+		Matrix [][]string `triton:"matrix"`
+	}
+
+	for _, intern := range []bool{false, true} {
+		t.Run(fmt.Sprintf("intern %t", intern), func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{
+					{name: "labels", datatype: STRING, shape: []int64{1, 4}},
+					{name: "matrix", datatype: STRING, shape: []int64{2, 2}},
+				},
+				raw: [][]byte{encodeStrings("cat", "dog", "cat", ""), encodeStrings("x", "y", "y", "x")},
+			}
+
+			var res result
+			if err := UnmarshalWithOptions(resp, &res, Options{InternStrings: intern}); err != nil {
+				t.Fatal(err)
+			}
+
+			want := result{Labels: []string{"cat", "dog", "cat", ""}, Matrix: [][]string{{"x", "y"}, {"y", "x"}}}
+			if !reflect.DeepEqual(res, want) {
+				t.Errorf("got %q, want %q", res, want)
+			}
+
+			shared := unsafe.StringData(res.Labels[0]) == unsafe.StringData(res.Labels[2]) &&
+				unsafe.StringData(res.Matrix[0][0]) == unsafe.StringData(res.Matrix[1][1])
+			if shared != intern {
+				t.Errorf("equal elements share memory: %t, want %t", shared, intern)
+			}
+
+			// elements don't reference raw contents.
+			for _, raw := range resp.raw {
+				clear(raw)
+			}
+
+			if !reflect.DeepEqual(res, want) {
+				t.Errorf("got %q after raw contents are cleared, want %q", res, want)
+			}
+		})
+	}
+}
+
+func TestInternStringsErrors(t *testing.T) {
+	var res struct {
+		Labels []string `triton:"labels"`
+	}
+
+	tests := []struct {
+		name string
+		raw  []byte
+		want string
+	}{
+		{name: "missing element", raw: encodeStrings("cat"), want: "string length at offset 7"},
+		{name: "short element", raw: encodeStrings("cat", "dog")[:12], want: "string of length 3 at offset 11"},
+		{name: "trailing bytes", raw: append(encodeStrings("cat", "cat"), 0), want: "1 trailing bytes after 2 elements"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "labels", datatype: STRING, shape: []int64{1, 2}}},
+				raw:     [][]byte{tt.raw},
+			}
+
+			err := UnmarshalWithOptions(resp, &res, Options{InternStrings: true})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringAllocations(t *testing.T) {
+	allocs := func(n int, opts Options) float64 {
+		labels := make([]string, n)
+		for i := range labels {
+			labels[i] = []string{"cat", "dog"}[i%2]
+		}
+
+		resp := &testResponse{
+			outputs: []*testOutput{{name: "labels", datatype: STRING, shape: []int64{1, int64(n)}}},
+			raw:     [][]byte{encodeStrings(labels...)},
+		}
+
+		var res struct {
+			Labels []string `triton:"labels"`
+		}
+
+		return testing.AllocsPerRun(10, func() {
+			if err := UnmarshalWithOptions(resp, &res, opts); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	for _, opts := range []Options{{}, {InternStrings: true}} {
+		if few, many := allocs(10, opts), allocs(1000, opts); many > few {
+			t.Errorf("InternStrings %t: %v allocations for 1000 elements, %v for 10", opts.InternStrings, many, few)
+		}
+	}
+}
+
+func TestBytesSlice(t *testing.T) {
+	type result struct {
+		Scalar [][]byte   `triton:"scalar"`
+		Vector [][]byte   `triton:"vector"`
+		Matrix [][][]byte `triton:"matrix"`
+		Empty  [][]byte   `triton:"empty"`
+	}
+
+	for _, zeroCopy := range []bool{false, true} {
+		t.Run(fmt.Sprintf("zero copy %t", zeroCopy), func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{
+					{name: "scalar", datatype: STRING, shape: []int64{2}},
+					{name: "vector", datatype: STRING, shape: []int64{1, 3}},
+					{name: "matrix", datatype: STRING, shape: []int64{2, 1}},
+					{name: "empty", datatype: STRING, shape: []int64{1, 2}},
+				},
+				raw: [][]byte{encodeStrings("ab", ""), encodeStrings("x", "yz", "\x00"), encodeStrings("p", "q"), {}},
+			}
+
+			var res result
+			if err := UnmarshalWithOptions(resp, &res, Options{ZeroCopyBytes: zeroCopy}); err != nil {
+				t.Fatal(err)
+			}
+
+			want := result{
+				Scalar: [][]byte{[]byte("ab"), {}},
+				Vector: [][]byte{[]byte("x"), []byte("yz"), {0}},
+				Matrix: [][][]byte{{[]byte("p")}, {[]byte("q")}},
+				Empty:  [][]byte{nil, nil},
+			}
+			if !reflect.DeepEqual(res, want) {
+				t.Errorf("got %q, want %q", res, want)
+			}
+
+			if shared := &res.Vector[1][0] == &resp.raw[1][9]; shared != zeroCopy {
+				t.Errorf("element references raw contents: %t, want %t", shared, zeroCopy)
+			}
+
+			// appending to element doesn't overwrite the next one.
+			_ = append(res.Vector[0], '!')
+			if !bytes.Equal(res.Vector[1], []byte("yz")) {
+				t.Errorf("got %q after append to previous element, want %q", res.Vector[1], "yz")
+			}
+		})
+	}
+}
+
+func TestBytesSliceErrors(t *testing.T) {
+	var res struct {
+		Vector [][]byte   `triton:"vector"`
+		Matrix [][][]byte `triton:"matrix"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "missing element",
+			output: &testOutput{name: "vector", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("a"),
+			want:   "output vector",
+		},
+		{
+			name:   "short element",
+			output: &testOutput{name: "vector", datatype: STRING, shape: []int64{1, 1}},
+			raw:    encodeStrings("abc")[:6],
+			want:   "output vector",
+		},
+		{
+			name:   "trailing bytes",
+			output: &testOutput{name: "vector", datatype: STRING, shape: []int64{1, 1}},
+			raw:    encodeStrings("a", "b"),
+			want:   "output vector: 5 trailing bytes after 1 elements",
+		},
+		{
+			name:   "matrix into vector",
+			output: &testOutput{name: "vector", datatype: STRING, shape: []int64{2, 1}},
+			raw:    encodeStrings("a", "b"),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "vector into matrix",
+			output: &testOutput{name: "matrix", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("a", "b"),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "higher rank",
+			output: &testOutput{name: "matrix", datatype: STRING, shape: []int64{1, 1, 1}},
+			raw:    encodeStrings("a"),
+			want:   "len(shape) > 2 is not yet supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{ZeroCopyBytes: true})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBytesValue(t *testing.T) {
+	type result struct {
+		Blob   []byte `triton:"blob"`
+		Empty  []byte `triton:"empty"`
+		String string `triton:"string"`
+	}
+
+	raw := encodeStrings("\x00\xff\x01")
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "blob", datatype: STRING, shape: []int64{1}},
+			{name: "empty", datatype: STRING, shape: []int64{1}},
+			{name: "string", datatype: STRING, shape: []int64{1}},
+		},
+		raw: [][]byte{raw, {}, encodeStrings("ab")},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{Blob: []byte{0, 0xff, 1}, Empty: []byte{}, String: "ab"}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %q, want %q", res, want)
+	}
+
+	// contents are copied.
+	clear(raw)
+
+	if !bytes.Equal(res.Blob, want.Blob) {
+		t.Errorf("got %q after raw contents are cleared, want %q", res.Blob, want.Blob)
+	}
+}
+
+func TestBytesValueErrors(t *testing.T) {
+	var res struct {
+		Blob []byte `triton:"blob"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "short element",
+			output: &testOutput{name: "blob", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("abc")[:5],
+			want:   "string of length 3 at offset 4",
+		},
+		{
+			name:   "short length",
+			output: &testOutput{name: "blob", datatype: STRING, shape: []int64{1}},
+			raw:    []byte{1, 0},
+			want:   "string length at offset 0",
+		},
+		{
+			name:   "trailing bytes",
+			output: &testOutput{name: "blob", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("a", "b"),
+			want:   "output blob: 5 trailing bytes after 1 elements",
+		},
+		{
+			name:   "two elements",
+			output: &testOutput{name: "blob", datatype: STRING, shape: []int64{2}},
+			raw:    encodeStrings("a", "b"),
+			want:   "output blob",
+		},
+		{
+			name:   "matrix",
+			output: &testOutput{name: "blob", datatype: STRING, shape: []int64{1, 1}},
+			raw:    encodeStrings("a"),
+			want:   "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package tritonparser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// stringReader reads length-prefixed elements of BYTES output.
+// Contents are converted to string once and elements are its substrings,
+// or elements are interned if Options.InternStrings is set, so both avoid allocation per element.
+type stringReader struct {
+	b      []byte
+	s      string
+	order  binary.ByteOrder
+	prefix int
+	intern map[string]string
+}
+
+func newStringReader(b []byte, order binary.ByteOrder, opts *Options) *stringReader {
+	r := &stringReader{b: b, order: order, prefix: opts.StringLengthPrefix}
+	if opts.InternStrings {
+		r.intern = make(map[string]string)
+	} else {
+		r.s = string(b)
+	}
+
+	return r
+}
+
+// read returns string at offset and offset of the next one.
+func (r *stringReader) read(offset int) (string, int, error) {
+	start, end, err := stringBounds(r.b, offset, r.order, r.prefix)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if r.intern == nil {
+		return r.s[start:end], end, nil
+	}
+
+	// lookup by converted key doesn't allocate.
+	if s, ok := r.intern[string(r.b[start:end])]; ok {
+		return s, end, nil
+	}
+
+	s := string(r.b[start:end])
+	r.intern[s] = s
+
+	return s, end, nil
+}
+
+// bytesType is the type of BYTES element decoded without conversion to string.
+var bytesType = reflect.TypeFor[[]byte]() //nolint:gochecknoglobals // type is constant.
+
+// isBytesSlice reports whether field receives BYTES elements as byte slices, i.e. it's [][]byte or [][][]byte.
+func isBytesSlice(field reflect.Value) bool {
+	t := field.Type()
+
+	return t.Kind() == reflect.Slice && (t.Elem() == bytesType ||
+		t.Elem().Kind() == reflect.Slice && t.Elem().Elem() == bytesType)
+}
+
+// unmarshalBytesSlice decodes elements of BYTES output into [][]byte field, or [][][]byte for multidimensional output.
+// Elements reference rawBytes if Options.ZeroCopyBytes is set, otherwise they are copies.
+func unmarshalBytesSlice(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	kind, err := ClassifyShape(output.GetShape())
+	if err != nil {
+		return err
+	}
+
+	rows, cols := 1, 1
+	switch kind {
+	case ShapeScalar:
+		cols = int(output.GetShape()[0])
+	case ShapeVector, ShapeMatrix:
+		rows, cols = int(output.GetShape()[0]), int(output.GetShape()[1])
+	case ShapeUnknown, ShapeHigherRank:
+		return fmt.Errorf("unknown shape: %v", output.GetShape())
+	}
+
+	exp := reflect.TypeFor[[][]byte]()
+	if kind == ShapeMatrix {
+		exp = reflect.SliceOf(exp)
+	}
+
+	if err := checkType(field, exp, opts); err != nil {
+		return err
+	}
+
+	res := make([][][]byte, rows)
+	for i := range res {
+		res[i] = make([][]byte, cols)
+	}
+
+	order := opts.byteOrder(output.GetName())
+	offset := 0
+
+	err = walkMultidimenshional(rows, cols, opts, func(i, j int) error {
+		// empty contents are empty elements, the same as for strings.
+		if len(rawBytes) == 0 {
+			return nil
+		}
+
+		start, end, err := stringBounds(rawBytes, offset, order, opts.StringLengthPrefix)
+		if err != nil {
+			return err
+		}
+
+		if opts.ZeroCopyBytes {
+			res[i][j] = rawBytes[start:end:end]
+		} else {
+			res[i][j] = bytes.Clone(rawBytes[start:end])
+		}
+
+		offset = end
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("output %s: %w", output.GetName(), err)
+	}
+
+	if offset != len(rawBytes) {
+		return fmt.Errorf("output %s: %w", output.GetName(), trailingBytesError(len(rawBytes)-offset, rows*cols))
+	}
+
+	if kind == ShapeMatrix {
+		return setValue(field, reflect.ValueOf(res), output.GetName(), opts)
+	}
+
+	return setValue(field, reflect.ValueOf(res[0]), output.GetName(), opts)
+}
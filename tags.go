@@ -0,0 +1,178 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	tagRequired   = "required"
+	tagPCM16      = "pcm16"
+	tagRound      = "round"
+	tagNested     = "nested"
+	tagParameters = "parameters"
+	tagArgmax     = "argmax"
+	tagBigEndian  = "bigendian"
+	tagLitEndian  = "littleendian"
+	tagImage      = "image"
+	tagScale      = "scale"
+	tagParseStr   = "parsestring"
+	tagRunes      = "runes"
+	tagConcat     = "concat"
+	tagReverse    = "reverse"
+	tagRing       = "ring"
+	tagLimit      = "limit"
+	tagCounts     = "counts"
+	tagQuant      = "quant"
+	tagMissing    = "missing"
+	tagUnixNano   = "unixnano"
+	tagUnixMillis = "unixmillis"
+	tagIndices    = "indices"
+	tagRemaining  = "remaining"
+	tagBitset     = "bitset"
+	tagLabels     = "labels"
+	tagZip        = "zip"
+	tagDequant    = "dequant"
+	tagFlat       = "flat"
+	tagShape      = "shape"
+	tagPad        = "pad"
+	tagTrim       = "trim"
+	tagAxes       = "axes"
+	tagLengths    = "lengths"
+)
+
+// tagOptions is the string following a comma in a struct field's "triton" tag,
+// or the empty string.
+type tagOptions string
+
+// parseTag splits a struct field's triton tag into its name and comma-separated options.
+func parseTag(tag string) (string, tagOptions) {
+	name, opt, _ := strings.Cut(tag, ",")
+
+	return name, tagOptions(opt)
+}
+
+// Contains reports whether a comma-separated list of options contains a particular option.
+func (o tagOptions) Contains(option string) bool {
+	s := string(o)
+	for s != "" {
+		var name string
+		name, s, _ = strings.Cut(s, ",")
+		if name == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get returns value of option in form of name=value.
+// Option without value is reported as present with empty value.
+func (o tagOptions) Get(option string) (string, bool) {
+	s := string(o)
+	for s != "" {
+		var opt string
+		opt, s, _ = strings.Cut(s, ",")
+		name, value, _ := strings.Cut(opt, "=")
+		if name == option {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// Has reports whether option is present, with or without value.
+func (o tagOptions) Has(option string) bool {
+	_, ok := o.Get(option)
+
+	return ok
+}
+
+// byteOrder returns byte order set by bigendian or littleendian option, or nil.
+func (o tagOptions) byteOrder() binary.ByteOrder {
+	switch {
+	case o.Contains(tagBigEndian):
+		return binary.BigEndian
+	case o.Contains(tagLitEndian):
+		return binary.LittleEndian
+	default:
+		return nil
+	}
+}
+
+// ints returns integer values of option, taken from its value and options following it
+// that are integers themselves, e.g. 1 and 0 of axes=1,0.
+func (o tagOptions) ints(option string) ([]int, error) {
+	var res []int
+	found := false
+
+	s := string(o)
+	for s != "" {
+		var opt string
+		opt, s, _ = strings.Cut(s, ",")
+		name, value, hasValue := strings.Cut(opt, "=")
+
+		switch {
+		case name == option && hasValue:
+			found = true
+		case found && !hasValue:
+			value = opt
+		default:
+			if found {
+				return res, nil
+			}
+
+			continue
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			if len(res) != 0 {
+				// following option isn't a value.
+				return res, nil
+			}
+
+			return nil, fmt.Errorf("invalid %s value: %q", option, value)
+		}
+
+		res = append(res, n)
+	}
+
+	return res, nil
+}
+
+// params returns key:value parameters of option, e.g. scale and zero of quant=scale:0.02,zero:128.
+// Parameters are taken from value of option and options following it that contain colon.
+func (o tagOptions) params(option string) (map[string]string, bool) {
+	res := make(map[string]string)
+	found := false
+
+	s := string(o)
+	for s != "" {
+		var opt string
+		opt, s, _ = strings.Cut(s, ",")
+		name, value, hasValue := strings.Cut(opt, "=")
+
+		switch {
+		case name == option:
+			found = true
+		case found && !hasValue && strings.Contains(opt, ":"):
+			value = opt
+		default:
+			if found {
+				return res, true
+			}
+
+			continue
+		}
+
+		if key, v, ok := strings.Cut(value, ":"); ok {
+			res[key] = v
+		}
+	}
+
+	return res, found
+}
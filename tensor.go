@@ -0,0 +1,113 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Tensor is output of any rank decoded with its shape. Data holds elements in row-major order.
+// T must match datatype of output the same way slice element does, e.g. float32 for FP32.
+type Tensor[T any] struct {
+	Data  []T
+	Shape []int64
+}
+
+// Offset returns offset of element at indices within Data, computed with row-major strides of Shape.
+func (t *Tensor[T]) Offset(indices ...int) (int, error) {
+	if len(indices) != len(t.Shape) {
+		return 0, fmt.Errorf("got %d indices for tensor of shape %v", len(indices), t.Shape)
+	}
+
+	offset, stride := 0, 1
+	for i := len(indices) - 1; i >= 0; i-- {
+		if indices[i] < 0 || int64(indices[i]) >= t.Shape[i] {
+			return 0, fmt.Errorf("index %d is out of range [0, %d) of axis %d", indices[i], t.Shape[i], i)
+		}
+
+		offset += indices[i] * stride
+		stride *= int(t.Shape[i])
+	}
+
+	if offset >= len(t.Data) {
+		return 0, fmt.Errorf("offset %d is out of range of data of length %d", offset, len(t.Data))
+	}
+
+	return offset, nil
+}
+
+// At returns element at indices. It panics if indices don't match Shape.
+func (t *Tensor[T]) At(indices ...int) T {
+	offset, err := t.Offset(indices...)
+	if err != nil {
+		panic("tritonparser: " + err.Error())
+	}
+
+	return t.Data[offset]
+}
+
+// Set stores v at indices. It panics if indices don't match Shape.
+func (t *Tensor[T]) Set(v T, indices ...int) {
+	offset, err := t.Offset(indices...)
+	if err != nil {
+		panic("tritonparser: " + err.Error())
+	}
+
+	t.Data[offset] = v
+}
+
+// tensor is implemented by pointers to Tensor of any element type.
+type tensor interface {
+	data() reflect.Value
+	setShape(shape []int64)
+}
+
+func (t *Tensor[T]) data() reflect.Value {
+	return reflect.ValueOf(&t.Data).Elem()
+}
+
+func (t *Tensor[T]) setShape(shape []int64) {
+	t.Shape = slices.Clone(shape)
+}
+
+// getTensor returns tensor of field if it's a Tensor.
+func getTensor(field reflect.Value) (tensor, bool) {
+	if !field.CanAddr() {
+		return nil, false
+	}
+
+	t, ok := field.Addr().Interface().(tensor)
+
+	return t, ok
+}
+
+// unmarshalTensor decodes output of any rank into flat data of t.
+func unmarshalTensor(t tensor, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	if err := decodeFlat(t.data(), output, rawBytes, opts); err != nil {
+		return err
+	}
+
+	t.setShape(output.GetShape())
+
+	return nil
+}
+
+// decodeFlat decodes output of any rank into slice field in row-major order.
+func decodeFlat(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	if field.Kind() != reflect.Slice {
+		return fmt.Errorf("output %s: %s option requires slice field, got %s", output.GetName(), tagFlat, field.Type())
+	}
+
+	count := int64(1)
+	for _, dim := range output.GetShape() {
+		if dim < 0 {
+			return fmt.Errorf("invalid shape: %v", output.GetShape())
+		}
+
+		count *= dim
+	}
+
+	flat := reshaped{output, []int64{1, count}}
+
+	return parseToArray(map[string]reflect.Value{output.GetName(): field}, flat, rawBytes, opts)
+}
@@ -0,0 +1,306 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTensor(t *testing.T) {
+	type result struct {
+		Vector Tensor[int32]   `triton:"vector"`
+		Matrix Tensor[float32] `triton:"matrix"`
+		Cube   Tensor[int32]   `triton:"cube"`
+		Labels Tensor[string]  `triton:"labels"`
+		Empty  Tensor[int32]   `triton:"empty"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "vector", datatype: INT32, shape: []int64{3}},
+			{name: "matrix", datatype: FLOAT32, shape: []int64{2, 2}},
+			{name: "cube", datatype: INT32, shape: []int64{2, 1, 2}},
+			{name: "labels", datatype: STRING, shape: []int64{1, 2}},
+			{name: "empty", datatype: INT32, shape: []int64{2, 0}},
+		},
+		raw: [][]byte{
+			int32Bytes(1, 2, 3),
+			float32Bytes(1, 2, 3, 4),
+			int32Bytes(5, 6, 7, 8),
+			encodeStrings("a", "b"),
+			{},
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Vector: Tensor[int32]{Data: []int32{1, 2, 3}, Shape: []int64{3}},
+		Matrix: Tensor[float32]{Data: []float32{1, 2, 3, 4}, Shape: []int64{2, 2}},
+		Cube:   Tensor[int32]{Data: []int32{5, 6, 7, 8}, Shape: []int64{2, 1, 2}},
+		Labels: Tensor[string]{Data: []string{"a", "b"}, Shape: []int64{1, 2}},
+		Empty:  Tensor[int32]{Data: []int32{}, Shape: []int64{2, 0}},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+
+	resp.outputs[2].shape[0] = 4
+	if res.Cube.Shape[0] != 2 {
+		t.Error("shape of tensor shares memory with output")
+	}
+}
+
+func TestTensorErrors(t *testing.T) {
+	var res struct {
+		Tensor Tensor[int32] `triton:"tensor"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "datatype",
+			output: &testOutput{name: "tensor", datatype: FLOAT32, shape: []int64{2, 1, 1}},
+			raw:    float32Bytes(1, 2),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "tensor", datatype: INT32, shape: []int64{2, 1, 2}},
+			raw:    int32Bytes(1, 2, 3)[:11],
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "negative dimension",
+			output: &testOutput{name: "tensor", datatype: INT32, shape: []int64{2, -1}},
+			raw:    int32Bytes(1, 2),
+			want:   "invalid shape: [2 -1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTensorAt(t *testing.T) {
+	tensor := Tensor[int32]{Data: []int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, Shape: []int64{2, 3, 2}}
+
+	tests := []struct {
+		indices []int
+		want    int
+	}{
+		{indices: []int{0, 0, 0}, want: 0},
+		{indices: []int{0, 0, 1}, want: 1},
+		{indices: []int{0, 2, 0}, want: 4},
+		{indices: []int{1, 0, 0}, want: 6},
+		{indices: []int{1, 2, 1}, want: 11},
+	}
+
+	for _, tt := range tests {
+		offset, err := tensor.Offset(tt.indices...)
+		if err != nil || offset != tt.want {
+			t.Errorf("Offset(%v) = %d, %v, want %d", tt.indices, offset, err, tt.want)
+		}
+
+		if got := tensor.At(tt.indices...); got != int32(tt.want) {
+			t.Errorf("At(%v) = %d, want %d", tt.indices, got, tt.want)
+		}
+
+		tensor.Set(-1, tt.indices...)
+		if tensor.Data[tt.want] != -1 {
+			t.Errorf("Set(%v) stored into %v", tt.indices, tensor.Data)
+		}
+	}
+
+	scalar := Tensor[float32]{Data: []float32{0.5}}
+	if got := scalar.At(); got != 0.5 {
+		t.Errorf("At() of scalar = %v, want 0.5", got)
+	}
+}
+
+func TestTensorAtErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		tensor  Tensor[int32]
+		indices []int
+		want    string
+	}{
+		{
+			name:    "rank",
+			tensor:  Tensor[int32]{Data: make([]int32, 4), Shape: []int64{2, 2}},
+			indices: []int{1},
+			want:    "got 1 indices for tensor of shape [2 2]",
+		},
+		{
+			name:    "above dimension",
+			tensor:  Tensor[int32]{Data: make([]int32, 4), Shape: []int64{2, 2}},
+			indices: []int{0, 2},
+			want:    "index 2 is out of range [0, 2) of axis 1",
+		},
+		{
+			name:    "negative",
+			tensor:  Tensor[int32]{Data: make([]int32, 4), Shape: []int64{2, 2}},
+			indices: []int{-1, 0},
+			want:    "index -1 is out of range [0, 2) of axis 0",
+		},
+		{
+			name:    "short data",
+			tensor:  Tensor[int32]{Data: make([]int32, 3), Shape: []int64{2, 2}},
+			indices: []int{1, 1},
+			want:    "offset 3 is out of range of data of length 3",
+		},
+		{
+			name:    "empty dimension",
+			tensor:  Tensor[int32]{Data: nil, Shape: []int64{2, 0}},
+			indices: []int{0, 0},
+			want:    "index 0 is out of range [0, 0) of axis 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.tensor.Offset(tt.indices...); err == nil || err.Error() != tt.want {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+
+			for name, access := range map[string]func(){
+				"At":  func() { tt.tensor.At(tt.indices...) },
+				"Set": func() { tt.tensor.Set(1, tt.indices...) },
+			} {
+				func() {
+					defer func() {
+						if r := recover(); r != "tritonparser: "+tt.want {
+							t.Errorf("%s panicked with %v, want %q", name, r, tt.want)
+						}
+					}()
+
+					access()
+				}()
+			}
+		})
+	}
+}
+
+func TestFlat(t *testing.T) {
+	type result struct {
+		Cube       []float32 `triton:"cube,flat"`
+		CubeShape  []int64   `triton:"cube,shape"`
+		Matrix     []int32   `triton:"matrix,flat"`
+		Vector     []int64   `triton:"vector,flat"`
+		Labels     []string  `triton:"labels,flat"`
+		Empty      []float32 `triton:"empty,flat"`
+		EmptyShape []int64   `triton:"empty,shape"`
+		OnlyShape  []int64   `triton:"only,shape"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "cube", datatype: FLOAT32, shape: []int64{2, 1, 2}},
+			{name: "matrix", datatype: INT32, shape: []int64{2, 2}},
+			{name: "vector", datatype: INT64, shape: []int64{2}},
+			{name: "labels", datatype: STRING, shape: []int64{1, 2, 1}},
+			{name: "empty", datatype: FLOAT32, shape: []int64{3, 0, 2}},
+			{name: "only", datatype: INT32, shape: []int64{4, 1}},
+		},
+		raw: [][]byte{
+			float32Bytes(1, 2, 3, 4),
+			int32Bytes(5, 6, 7, 8),
+			int64Bytes(9, 10),
+			encodeStrings("a", "b"),
+			{},
+			int32Bytes(1, 2, 3, 4),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Cube:       []float32{1, 2, 3, 4},
+		CubeShape:  []int64{2, 1, 2},
+		Matrix:     []int32{5, 6, 7, 8},
+		Vector:     []int64{9, 10},
+		Labels:     []string{"a", "b"},
+		Empty:      []float32{},
+		EmptyShape: []int64{3, 0, 2},
+		OnlyShape:  []int64{4, 1},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+
+	resp.outputs[0].shape[0] = 4
+	if res.CubeShape[0] != 2 {
+		t.Error("shape field shares memory with output")
+	}
+}
+
+func TestFlatErrors(t *testing.T) {
+	var res struct {
+		Flat   []int32 `triton:"flat,flat"`
+		Scalar int32   `triton:"scalar,flat"`
+		Shape  []int32 `triton:"shape,shape"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "datatype",
+			output: &testOutput{name: "flat", datatype: FLOAT32, shape: []int64{2, 1, 1}},
+			raw:    float32Bytes(1, 2),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "flat", datatype: INT32, shape: []int64{2, 1, 2}},
+			raw:    int32Bytes(1, 2, 3)[:11],
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "negative dimension",
+			output: &testOutput{name: "flat", datatype: INT32, shape: []int64{2, -1}},
+			raw:    int32Bytes(1, 2),
+			want:   "invalid shape: [2 -1]",
+		},
+		{
+			name:   "scalar field",
+			output: &testOutput{name: "scalar", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(1),
+			want:   "output scalar: flat option requires slice field, got int32",
+		},
+		{
+			name:   "shape field type",
+			output: &testOutput{name: "shape", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "output shape: shape option requires []int64 field, got []int32",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
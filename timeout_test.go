@@ -0,0 +1,76 @@
+package tritonparser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowOutput takes delay to decode and fails with err.
+type slowOutput struct {
+	delay time.Duration
+	err   error
+}
+
+func (s *slowOutput) UnmarshalTriton(string, []int64, []byte) error {
+	time.Sleep(s.delay)
+
+	return s.err
+}
+
+func TestPerOutputTimeout(t *testing.T) {
+	errDecode := errors.New("decode failed")
+
+	tests := []struct {
+		name    string
+		slow    slowOutput
+		timeout time.Duration
+		want    error
+	}{
+		{name: "within budget", slow: slowOutput{delay: time.Millisecond}, timeout: time.Hour},
+		{name: "without budget", slow: slowOutput{delay: 10 * time.Millisecond}},
+		{name: "exceeded", slow: slowOutput{delay: 10 * time.Millisecond}, timeout: time.Millisecond, want: context.DeadlineExceeded},
+		{
+			name:    "decode error wins",
+			slow:    slowOutput{delay: 10 * time.Millisecond, err: errDecode},
+			timeout: time.Millisecond,
+			want:    errDecode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := struct {
+				Fast int32       `triton:"fast"`
+				Slow *slowOutput `triton:"slow"`
+			}{Slow: &tt.slow}
+
+			resp := &testResponse{
+				outputs: []*testOutput{
+					{name: "fast", datatype: INT32, shape: []int64{1}},
+					{name: "slow", datatype: INT32, shape: []int64{1}},
+				},
+				raw: [][]byte{int32Bytes(1), int32Bytes(2)},
+			}
+
+			err := UnmarshalWithOptions(resp, &res, Options{PerOutputTimeout: tt.timeout})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+
+			if !errors.Is(tt.want, context.DeadlineExceeded) && errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("got error %v, want no timeout", err)
+			}
+
+			if tt.want != nil && !strings.Contains(err.Error(), "output slow") {
+				t.Errorf("error %q doesn't name slow output", err)
+			}
+
+			if res.Fast != 1 {
+				t.Errorf("got fast output %d, want 1", res.Fast)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package tritonparser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	var res struct {
+		Score  float32   `triton:"score"`
+		Scores []float32 `triton:"scores"`
+		Absent []int32   `triton:"absent"`
+	}
+
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		raw     [][]byte
+		opts    Options
+		want    []string
+		err     bool
+	}{
+		{
+			name: "decoded",
+			outputs: []*testOutput{
+				{name: "score", datatype: FLOAT32, shape: []int64{1}},
+				{name: "scores", datatype: FLOAT32, shape: []int64{1, 2}},
+			},
+			raw: [][]byte{float32Bytes(1), float32Bytes(2, 3)},
+			want: []string{
+				"output score: datatype FP32, shape [1]",
+				"output score: matched field of type float32",
+				"output score: decoding FP32 as scalar",
+				"output scores: datatype FP32, shape [1 2]",
+				"output scores: decoding FP32 as vector",
+			},
+		},
+		{
+			name:    "unmatched",
+			outputs: []*testOutput{{name: "other", datatype: INT32, shape: []int64{1}}},
+			raw:     [][]byte{int32Bytes(1)},
+			want:    []string{"output other: no matching field"},
+		},
+		{
+			name:    "skipped",
+			outputs: []*testOutput{{name: "score", datatype: FLOAT32, shape: []int64{1}}},
+			raw:     [][]byte{float32Bytes(1)},
+			opts:    Options{Skip: []string{"score"}},
+			want:    []string{"output score: skipped"},
+		},
+		{
+			name:    "absent contents",
+			outputs: []*testOutput{{name: "absent", datatype: INT32, shape: []int64{1, 1}}},
+			raw:     [][]byte{nil},
+			want:    []string{"output absent: no raw contents, field is zeroed"},
+		},
+		{
+			name:    "failed",
+			outputs: []*testOutput{{name: "scores", datatype: FLOAT32, shape: []int64{1, 2}}},
+			raw:     [][]byte{float32Bytes(1)[:3]},
+			want:    []string{"output scores: matched field of type []float32", "output scores: decoding FP32 as vector"},
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.opts.Trace = &buf
+
+			err := UnmarshalWithOptions(&testResponse{outputs: tt.outputs, raw: tt.raw}, &res, tt.opts)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %t", err, tt.err)
+			}
+
+			for _, line := range tt.want {
+				if !strings.Contains(buf.String(), line+"\n") {
+					t.Errorf("trace lacks %q:\n%s", line, buf.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTrailingBytes(t *testing.T) {
+	var res struct {
+		Scalar       int32      `triton:"scalar"`
+		Array        []int32    `triton:"array"`
+		Matrix       [][]int32  `triton:"matrix"`
+		String       string     `triton:"string"`
+		Strings      []string   `triton:"strings"`
+		StringMatrix [][]string `triton:"string_matrix"`
+		Half         float32    `triton:"half"`
+	}
+
+	tests := []struct {
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			output: &testOutput{name: "scalar", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1, 2),
+			want:   "4 trailing bytes after 1 elements",
+		},
+		{
+			output: &testOutput{name: "array", datatype: INT32, shape: []int64{1, 2}},
+			raw:    append(int32Bytes(1, 2), 0),
+			want:   "not a multiple of element size",
+		},
+		{
+			output: &testOutput{name: "matrix", datatype: INT32, shape: []int64{2, 1}},
+			raw:    int32Bytes(1, 2, 3),
+			want:   "4 trailing bytes after 2 elements",
+		},
+		{
+			output: &testOutput{name: "string", datatype: STRING, shape: []int64{1}},
+			raw:    append(encodeStrings("a"), 0),
+			want:   "1 trailing bytes after 1 elements",
+		},
+		{
+			output: &testOutput{name: "strings", datatype: STRING, shape: []int64{1, 1}},
+			raw:    encodeStrings("a", "b"),
+			want:   "5 trailing bytes after 1 elements",
+		},
+		{
+			output: &testOutput{name: "string_matrix", datatype: STRING, shape: []int64{2, 1}},
+			raw:    encodeStrings("a", "b", "c"),
+			want:   "5 trailing bytes after 2 elements",
+		},
+		{
+			output: &testOutput{name: "half", datatype: FLOAT16, shape: []int64{1}},
+			raw:    halfBytes(0x3c00, 0),
+			want:   "2 trailing bytes after 1 elements",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output.name, func(t *testing.T) {
+			err := Unmarshal(&testResponse{outputs: []*testOutput{tt.output}, raw: [][]byte{tt.raw}}, &res)
+			if err == nil || !strings.Contains(err.Error(), "output "+tt.output.name) || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q of output %s", err, tt.want, tt.output.name)
+			}
+		})
+	}
+}
+
+type scalarResult struct {
+	Bool   bool    `triton:"bool"`
+	Int8   int8    `triton:"int8"`
+	Int16  int16   `triton:"int16"`
+	Uint64 uint64  `triton:"uint64"`
+	Double float64 `triton:"double"`
+	String string  `triton:"string"`
+}
+
+func TestScalarSize(t *testing.T) {
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "bool", datatype: BOOL, shape: []int64{1}},
+			{name: "int8", datatype: INT8, shape: []int64{1}},
+			{name: "int16", datatype: INT16, shape: []int64{1}},
+			{name: "uint64", datatype: UINT64, shape: []int64{1}},
+			{name: "double", datatype: FLOAT64, shape: []int64{1}},
+			{name: "string", datatype: STRING, shape: []int64{1}},
+		},
+		raw: [][]byte{{1}, {0xfe}, int16Bytes(-3), int64Bytes(4), float64Bytes(0.5), encodeStrings("ab")},
+	}
+
+	var res scalarResult
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := scalarResult{Bool: true, Int8: -2, Int16: -3, Uint64: 4, Double: 0.5, String: "ab"}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestScalarSizeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "bool trailing",
+			output: &testOutput{name: "bool", datatype: BOOL, shape: []int64{1}},
+			raw:    []byte{1, 0},
+			want:   "output bool: 1 trailing bytes after 1 elements",
+		},
+		{
+			name:   "int8 trailing",
+			output: &testOutput{name: "int8", datatype: INT8, shape: []int64{1}},
+			raw:    []byte{1, 2, 3},
+			want:   "output int8: 2 trailing bytes after 1 elements",
+		},
+		{
+			name:   "int16 trailing",
+			output: &testOutput{name: "int16", datatype: INT16, shape: []int64{1}},
+			raw:    int16Bytes(1, 2),
+			want:   "output int16: 2 trailing bytes after 1 elements",
+		},
+		{
+			name:   "uint64 short",
+			output: &testOutput{name: "uint64", datatype: UINT64, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "binary read failed",
+		},
+		{
+			name:   "double trailing",
+			output: &testOutput{name: "double", datatype: FLOAT64, shape: []int64{1}},
+			raw:    append(float64Bytes(1), 0),
+			want:   "output double: 1 trailing bytes after 1 elements",
+		},
+		{
+			name:   "string trailing",
+			output: &testOutput{name: "string", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("a", "b"),
+			want:   "output string: 5 trailing bytes after 1 elements",
+		},
+		{
+			name:   "string short",
+			output: &testOutput{name: "string", datatype: STRING, shape: []int64{1}},
+			raw:    []byte{3, 0, 0, 0, 'a'},
+			want:   "string of length 3 at offset 4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res scalarResult
+
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,989 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// pcm16Scale normalizes signed 16-bit PCM samples to [-1, 1].
+const pcm16Scale = 32768
+
+// decodeOutput decodes output into its field, applying tag options that transform decoded values.
+func decodeOutput(
+	fieldMap map[string]reflect.Value,
+	output TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+	tagOpts tagOptions,
+) error {
+	field := fieldMap[output.GetName()]
+
+	if tagOpts.Has(tagAxes) {
+		perm, err := tagOpts.ints(tagAxes)
+		if err != nil {
+			return fmt.Errorf("output %s: %w", output.GetName(), err)
+		}
+
+		if output, rawBytes, err = permuteAxes(output, rawBytes, opts, perm); err != nil {
+			return err
+		}
+	}
+
+	output, opts, err := batchLeading(output, opts)
+	if err != nil {
+		return err
+	}
+
+	output = squeezeTrailing(output, opts)
+
+	if order := tagOpts.byteOrder(); order != nil && !opts.fixedByteOrder {
+		fieldOpts := *opts
+		fieldOpts.ByteOrderFor = func(string) binary.ByteOrder { return order }
+		opts = &fieldOpts
+	}
+
+	if tagOpts != "" && opts.Trace != nil {
+		opts.tracef("output %s: tag options %q", output.GetName(), string(tagOpts))
+	}
+
+	if err := opts.checkLimits(output, rawBytes); err != nil {
+		return err
+	}
+
+	if err := decodeTransformed(fieldMap, output, rawBytes, opts, tagOpts); err != nil {
+		return err
+	}
+
+	if pad, ok := tagOpts.Get(tagPad); ok || tagOpts.Contains(tagTrim) {
+		if !ok || !tagOpts.Contains(tagTrim) {
+			return fmt.Errorf("output %s: %s and %s options must be used together", output.GetName(), tagPad, tagTrim)
+		}
+
+		if err := trimField(field, output.GetName(), pad); err != nil {
+			return err
+		}
+	}
+
+	if axis, ok := tagOpts.Get(tagReverse); ok {
+		return reverseField(field, output.GetName(), axis)
+	}
+
+	return nil
+}
+
+// decodeTransformed decodes output into its field with decoder selected by tag options.
+func decodeTransformed(
+	fieldMap map[string]reflect.Value,
+	output TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+	tagOpts tagOptions,
+) error {
+	field := fieldMap[output.GetName()]
+
+	switch {
+	case scalarType(field.Type()) == reflect.TypeFor[Classification]():
+		return decodeClassifications(field, output, rawBytes, opts)
+	case tagOpts.Has(tagMissing) && field.Kind() == reflect.Slice:
+		sentinel, _ := tagOpts.Get(tagMissing)
+		return decodeMissing(field, output, rawBytes, opts, sentinel)
+	case tagOpts.Has(tagQuant):
+		params, _ := tagOpts.params(tagQuant)
+		return decodeQuantized(field, output, rawBytes, opts, params)
+	case tagOpts.Has(tagDequant):
+		params, _ := tagOpts.params(tagDequant)
+		return decodeDequantized(field, output, rawBytes, opts, params)
+	case tagOpts.Contains(tagCounts):
+		return decodeCounts(field, output, rawBytes, opts)
+	case tagOpts.Has(tagLimit):
+		limit, _ := tagOpts.Get(tagLimit)
+		return decodeLimited(fieldMap, output, rawBytes, opts, limit)
+	case tagOpts.Has(tagRing):
+		capacity, _ := tagOpts.Get(tagRing)
+		return decodeRing(field, output, rawBytes, opts, capacity)
+	case tagOpts.Contains(tagImage):
+		return decodeImage(field, output, rawBytes)
+	case tagOpts.Contains(tagPCM16):
+		return decodePCM16(field, output, rawBytes, opts)
+	case tagOpts.Contains(tagArgmax):
+		return decodeArgmax(field, output, rawBytes, opts)
+	case tagOpts.Contains(tagNested):
+		return decodeNested(field, output, rawBytes, opts)
+	case tagOpts.Has(tagRunes):
+		mode, _ := tagOpts.Get(tagRunes)
+		return decodeRunes(field, output, rawBytes, opts, mode)
+	case tagOpts.Contains(tagParseStr):
+		return decodeParsedStrings(field, output, rawBytes, opts)
+	case tagOpts.Has(tagScale):
+		scale, _ := tagOpts.Get(tagScale)
+		return decodeScaled(field, output, rawBytes, opts, scale)
+	case tagOpts.Contains(tagUnixNano):
+		return decodeTimestamps(field, output, rawBytes, opts, time.Nanosecond)
+	case tagOpts.Contains(tagUnixMillis):
+		return decodeTimestamps(field, output, rawBytes, opts, time.Millisecond)
+	case tagOpts.Has(tagLabels):
+		name, _ := tagOpts.Get(tagLabels)
+		return decodeLabels(field, output, rawBytes, opts, name)
+	case tagOpts.Contains(tagBitset):
+		return decodeBitset(field, output, rawBytes)
+	case tagOpts.Contains(tagFlat):
+		return decodeFlat(field, output, rawBytes, opts)
+	case tagOpts.Has(tagRound):
+		mode, _ := tagOpts.Get(tagRound)
+		return decodeRounded(field, output, rawBytes, opts, mode)
+	default:
+		return parse(fieldMap, output, rawBytes, opts)
+	}
+}
+
+// nativeType returns type output is decoded into without coercion,
+// e.g. []float32 for FP32 output of shape [1, N].
+func nativeType(output TritonModelInferResponseOutputs) (reflect.Type, error) {
+	elem, ok := elementType(output.GetDatatype())
+	if !ok {
+		return nil, fmt.Errorf("unkwnow type: %s", output.GetDatatype())
+	}
+
+	kind, err := ClassifyShape(output.GetShape())
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case ShapeScalar:
+		return elem, nil
+	case ShapeVector:
+		return reflect.SliceOf(elem), nil
+	case ShapeMatrix:
+		return reflect.SliceOf(reflect.SliceOf(elem)), nil
+	case ShapeUnknown, ShapeHigherRank:
+		return nil, fmt.Errorf("unknown shape: %v", output.GetShape())
+	}
+
+	return nil, fmt.Errorf("unknown shape: %v", output.GetShape())
+}
+
+// decodeNative decodes output into value of its native type.
+func decodeNative(output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) (reflect.Value, error) {
+	t, err := nativeType(output)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	v := reflect.New(t).Elem()
+	if err := parse(map[string]reflect.Value{output.GetName(): v}, output, rawBytes, opts); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return v, nil
+}
+
+// mapElements applies fn to every element of possibly nested slice val.
+// Result has the same nesting with elements of type elem.
+func mapElements(val reflect.Value, elem reflect.Type, fn func(reflect.Value) reflect.Value) reflect.Value {
+	if val.Kind() != reflect.Slice {
+		return fn(val)
+	}
+
+	t := elem
+	for st := val.Type(); st.Kind() == reflect.Slice; st = st.Elem() {
+		t = reflect.SliceOf(t)
+	}
+
+	res := reflect.MakeSlice(t, val.Len(), val.Len())
+	for i := 0; i < val.Len(); i++ {
+		res.Index(i).Set(mapElements(val.Index(i), elem, fn))
+	}
+
+	return res
+}
+
+// setTransformed stores transformed value to field, checking types the same way decoders do.
+func setTransformed(field, val reflect.Value, output string, opts *Options) error {
+	if err := checkType(field, val.Type(), opts); err != nil {
+		return err
+	}
+
+	return setValue(field, val, output, opts)
+}
+
+// reuseMap returns map of field cleared for reuse, so decoding in a loop doesn't allocate maps again,
+// or new map of field type with room for n entries if field is nil.
+// Contents must be validated before, so field isn't left half-filled on error.
+func reuseMap(field reflect.Value, n int) reflect.Value {
+	if !field.IsNil() {
+		field.Clear()
+
+		return field
+	}
+
+	return reflect.MakeMapWithSize(field.Type(), n)
+}
+
+// decodePCM16 decodes INT16 PCM samples normalized to [-1, 1] float32.
+func decodePCM16(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	if output.GetDatatype() != INT16 {
+		return fmt.Errorf("output %s: %s option requires %s datatype, got %s", output.GetName(), tagPCM16, INT16, output.GetDatatype())
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	res := mapElements(val, reflect.TypeFor[float32](), func(v reflect.Value) reflect.Value {
+		return reflect.ValueOf(float32(v.Int()) / pcm16Scale)
+	})
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
+
+// decodeRounded decodes float output into integer field rounding values according to mode:
+// "" or "even" rounds half to even, "away" rounds half away from zero, "trunc" truncates.
+func decodeRounded(
+	field reflect.Value,
+	output TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+	mode string,
+) error {
+	var round func(float64) float64
+	switch mode {
+	case "", "even":
+		round = math.RoundToEven
+	case "away":
+		round = math.Round
+	case "trunc":
+		round = math.Trunc
+	default:
+		return fmt.Errorf("output %s: unknown rounding mode: %s", output.GetName(), mode)
+	}
+
+	elem := scalarType(field.Type())
+	if !isInt(elem.Kind()) && !isUint(elem.Kind()) {
+		return fmt.Errorf("output %s: %s option requires integer field, got %s", output.GetName(), tagRound, field.Type())
+	}
+
+	switch output.GetDatatype() {
+	case FLOAT16, FLOAT32, FLOAT64:
+	default:
+		return fmt.Errorf("output %s: %s option requires float datatype, got %s", output.GetName(), tagRound, output.GetDatatype())
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	res := mapElements(val, elem, func(v reflect.Value) reflect.Value {
+		r := reflect.New(elem).Elem()
+		f := round(v.Float())
+
+		switch {
+		case err != nil:
+		case math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64:
+			err = fmt.Errorf("output %s: %v overflows %s", output.GetName(), v.Float(), elem)
+		case isInt(elem.Kind()) && !r.OverflowInt(int64(f)):
+			r.SetInt(int64(f))
+		case isUint(elem.Kind()) && f >= 0 && !r.OverflowUint(uint64(f)):
+			r.SetUint(uint64(f))
+		default:
+			err = fmt.Errorf("output %s: %v overflows %s", output.GetName(), v.Float(), elem)
+		}
+
+		return r
+	})
+	if err != nil {
+		return err
+	}
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
+
+// decodeScaled decodes integer output holding fixed-point decimals into float field,
+// dividing values by scale, e.g. cents into units for scale=100.
+func decodeScaled(
+	field reflect.Value,
+	output TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+	scale string,
+) error {
+	s, err := strconv.ParseFloat(scale, 64)
+	if err != nil || !(s > 0) || math.IsInf(s, 1) {
+		return fmt.Errorf("output %s: %s option requires positive number, got %q", output.GetName(), tagScale, scale)
+	}
+
+	elem := scalarType(field.Type())
+	if !isFloat(elem.Kind()) {
+		return fmt.Errorf("output %s: %s option requires float field, got %s", output.GetName(), tagScale, field.Type())
+	}
+
+	if t, ok := elementType(output.GetDatatype()); !ok || (!isInt(t.Kind()) && !isUint(t.Kind())) {
+		return fmt.Errorf("output %s: %s option requires integer datatype, got %s", output.GetName(), tagScale, output.GetDatatype())
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	res := mapElements(val, elem, func(v reflect.Value) reflect.Value {
+		var f float64
+		if isInt(v.Kind()) {
+			f = float64(v.Int())
+		} else {
+			f = float64(v.Uint())
+		}
+
+		return reflect.ValueOf(f / s).Convert(elem)
+	})
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
+
+// decodeTimestamps decodes integer output holding Unix times in units of unit into time.Time field.
+// Times must be within range of int64 nanoseconds, so UnixNano of decoded times is exact.
+func decodeTimestamps(
+	field reflect.Value,
+	output TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+	unit time.Duration,
+) error {
+	elem := reflect.TypeFor[time.Time]()
+	if scalarType(field.Type()) != elem {
+		return fmt.Errorf("output %s: timestamp options require time.Time field, got %s", output.GetName(), field.Type())
+	}
+
+	if t, ok := elementType(output.GetDatatype()); !ok || (!isInt(t.Kind()) && !isUint(t.Kind())) {
+		return fmt.Errorf("output %s: timestamp options require integer datatype, got %s", output.GetName(), output.GetDatatype())
+	}
+
+	if shape := output.GetShape(); len(shape) == 1 && field.Kind() == reflect.Slice {
+		output = reshaped{output, []int64{1, shape[0]}}
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	limit := int64(math.MaxInt64 / unit)
+
+	res := mapElements(val, elem, func(v reflect.Value) reflect.Value {
+		var n int64
+		switch {
+		case isUint(v.Kind()) && v.Uint() > uint64(limit):
+			n = limit + 1
+		case isUint(v.Kind()):
+			n = int64(v.Uint())
+		default:
+			n = v.Int()
+		}
+
+		if n > limit || n < -limit {
+			if err == nil {
+				err = fmt.Errorf("output %s: timestamp %v is out of range", output.GetName(), v)
+			}
+
+			return reflect.ValueOf(time.Time{})
+		}
+
+		return reflect.ValueOf(time.Unix(0, n*int64(unit)))
+	})
+	if err != nil {
+		return err
+	}
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
+
+// decodeBitset decodes BOOL output of any shape into slice of unsigned integers, bit i of which
+// is element i of output in row-major order, e.g. bit 70 is bit 6 of the second element of []uint64.
+func decodeBitset(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte) error {
+	t := field.Type()
+	if t.Kind() != reflect.Slice || !isUint(t.Elem().Kind()) || t.Elem().Kind() == reflect.Uintptr {
+		return fmt.Errorf("output %s: %s option requires slice of unsigned integers field, got %s", output.GetName(), tagBitset, t)
+	}
+
+	if output.GetDatatype() != BOOL {
+		return fmt.Errorf("output %s: %s option requires %s datatype, got %s", output.GetName(), tagBitset, BOOL, output.GetDatatype())
+	}
+
+	size, err := OutputByteSize(BOOL, output.GetShape())
+	if err != nil {
+		return fmt.Errorf("output %s: %w", output.GetName(), err)
+	}
+
+	if len(rawBytes) != size {
+		return fmt.Errorf("output %s: raw contents length %d doesn't match shape %v", output.GetName(), len(rawBytes), output.GetShape())
+	}
+
+	bits := t.Elem().Bits()
+	words := make([]uint64, (size+bits-1)/bits)
+
+	for i, b := range rawBytes {
+		if b != 0 {
+			words[i/bits] |= 1 << (i % bits)
+		}
+	}
+
+	res := reflect.MakeSlice(t, len(words), len(words))
+	for i, w := range words {
+		res.Index(i).SetUint(w)
+	}
+
+	field.Set(res)
+
+	return nil
+}
+
+// decodeParsedStrings decodes STRING output holding formatted numbers into numeric or bool field.
+func decodeParsedStrings(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	if output.GetDatatype() != STRING {
+		return fmt.Errorf("output %s: %s option requires %s datatype, got %s", output.GetName(), tagParseStr, STRING, output.GetDatatype())
+	}
+
+	elem := scalarType(field.Type())
+	if !isNumeric(elem.Kind()) && elem.Kind() != reflect.Bool {
+		return fmt.Errorf("output %s: %s option requires numeric field, got %s", output.GetName(), tagParseStr, field.Type())
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	idx := 0
+	res := mapElements(val, elem, func(v reflect.Value) reflect.Value {
+		r := reflect.New(elem).Elem()
+		if err == nil {
+			if perr := parseString(r, v.String()); perr != nil {
+				err = fmt.Errorf("output %s: element %d: %w", output.GetName(), idx, perr)
+			}
+		}
+
+		idx++
+
+		return r
+	})
+	if err != nil {
+		return err
+	}
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
+
+// parseString parses s into numeric or bool value v according to its kind.
+func parseString(v reflect.Value, s string) error {
+	switch k := v.Kind(); {
+	case k == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case isInt(k):
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(n)
+	case isUint(k):
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(n)
+	default:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+	}
+
+	return nil
+}
+
+// decodeRunes decodes INT32 code points into string field, or every row of multidimensional
+// output into slice of strings. Invalid code points are replaced with utf8.RuneError
+// unless mode is "strict", which fails on them.
+func decodeRunes(
+	field reflect.Value,
+	output TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+	mode string,
+) error {
+	if mode != "" && mode != "strict" {
+		return fmt.Errorf("output %s: unknown %s mode: %s", output.GetName(), tagRunes, mode)
+	}
+
+	if output.GetDatatype() != INT32 {
+		return fmt.Errorf("output %s: %s option requires %s datatype, got %s", output.GetName(), tagRunes, INT32, output.GetDatatype())
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	toString := func(v reflect.Value) (string, error) {
+		var codes []int32
+		if v.Kind() == reflect.Slice {
+			codes = as[[]int32](v.Interface())
+		} else {
+			codes = []int32{as[int32](v.Interface())}
+		}
+
+		runes := make([]rune, len(codes))
+		for i, r := range codes {
+			switch {
+			case utf8.ValidRune(r):
+				runes[i] = r
+			case mode == "strict":
+				return "", fmt.Errorf("output %s: invalid code point %d at %d", output.GetName(), r, i)
+			default:
+				runes[i] = utf8.RuneError
+			}
+		}
+
+		return string(runes), nil
+	}
+
+	var res reflect.Value
+	if val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Slice {
+		strs := make([]string, val.Len())
+		for i := range strs {
+			if strs[i], err = toString(val.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		res = reflect.ValueOf(strs)
+	} else {
+		s, err := toString(val)
+		if err != nil {
+			return err
+		}
+
+		res = reflect.ValueOf(s)
+	}
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
+
+// decodeQuantized quantizes float output into integer field as round(x/scale)+zero,
+// rounding half to even and clamping to range of field type. NaN is mapped to zero point.
+func decodeQuantized(
+	field reflect.Value,
+	output TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+	params map[string]string,
+) error {
+	scale, err := strconv.ParseFloat(params["scale"], 64)
+	if err != nil || !(scale > 0) || math.IsInf(scale, 1) {
+		return fmt.Errorf("output %s: %s option requires positive scale, got %q", output.GetName(), tagQuant, params["scale"])
+	}
+
+	var zero int64
+	if z, ok := params["zero"]; ok {
+		if zero, err = strconv.ParseInt(z, 10, 64); err != nil {
+			return fmt.Errorf("output %s: %s option requires integer zero point, got %q", output.GetName(), tagQuant, z)
+		}
+	}
+
+	elem := scalarType(field.Type())
+	if !isInt(elem.Kind()) && !isUint(elem.Kind()) {
+		return fmt.Errorf("output %s: %s option requires integer field, got %s", output.GetName(), tagQuant, field.Type())
+	}
+
+	switch output.GetDatatype() {
+	case FLOAT16, FLOAT32, FLOAT64:
+	default:
+		return fmt.Errorf("output %s: %s option requires float datatype, got %s", output.GetName(), tagQuant, output.GetDatatype())
+	}
+
+	lo, hi := intRange(elem)
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	res := mapElements(val, elem, func(v reflect.Value) reflect.Value {
+		// NaN is quantized to zero point, which is clamped as well.
+		q := float64(zero)
+		if f := v.Float(); !math.IsNaN(f) {
+			q += math.RoundToEven(f / scale)
+		}
+
+		q = math.Max(lo, math.Min(hi, q))
+
+		r := reflect.New(elem).Elem()
+		bits := elem.Bits()
+
+		// hi of 64-bit types isn't representable as float, so it's set exactly.
+		switch {
+		case isInt(elem.Kind()) && q >= hi:
+			r.SetInt(math.MaxInt64 >> (64 - bits))
+		case isInt(elem.Kind()):
+			r.SetInt(int64(q))
+		case q >= hi:
+			r.SetUint(math.MaxUint64 >> (64 - bits))
+		default:
+			r.SetUint(uint64(q))
+		}
+
+		return r
+	})
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
+
+// decodeDequantized dequantizes integer output into float field as (x-zero)*scale.
+// Scale and zero point missing in params are taken from parameters of output, if any.
+func decodeDequantized(
+	field reflect.Value,
+	output TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+	params map[string]string,
+) error {
+	if outParams, ok := getParameters(output); ok {
+		for _, key := range [...]string{"scale", "zero"} {
+			if _, ok := params[key]; !ok && outParams[key] != nil {
+				params[key] = fmt.Sprint(outParams[key])
+			}
+		}
+	}
+
+	scale, err := strconv.ParseFloat(params["scale"], 64)
+	if err != nil || !(scale > 0) || math.IsInf(scale, 1) {
+		return fmt.Errorf("output %s: %s option requires positive scale, got %q", output.GetName(), tagDequant, params["scale"])
+	}
+
+	var zero int64
+	if z, ok := params["zero"]; ok {
+		if zero, err = strconv.ParseInt(z, 10, 64); err != nil {
+			return fmt.Errorf("output %s: %s option requires integer zero point, got %q", output.GetName(), tagDequant, z)
+		}
+	}
+
+	elem := scalarType(field.Type())
+	if !isFloat(elem.Kind()) {
+		return fmt.Errorf("output %s: %s option requires float field, got %s", output.GetName(), tagDequant, field.Type())
+	}
+
+	if t, ok := elementType(output.GetDatatype()); !ok || (!isInt(t.Kind()) && !isUint(t.Kind())) {
+		return fmt.Errorf("output %s: %s option requires integer datatype, got %s", output.GetName(), tagDequant, output.GetDatatype())
+	}
+
+	if shape := output.GetShape(); len(shape) == 1 && field.Kind() == reflect.Slice {
+		output = reshaped{output, []int64{1, shape[0]}}
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	res := mapElements(val, elem, func(v reflect.Value) reflect.Value {
+		var x float64
+		if isInt(v.Kind()) {
+			x = float64(v.Int())
+		} else {
+			x = float64(v.Uint())
+		}
+
+		return reflect.ValueOf((x - float64(zero)) * scale).Convert(elem)
+	})
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
+
+// intRange returns range of integer type t as floats.
+func intRange(t reflect.Type) (float64, float64) {
+	bits := t.Bits()
+	if isUint(t.Kind()) {
+		return 0, math.Ldexp(1, bits) - 1
+	}
+
+	return -math.Ldexp(1, bits-1), math.Ldexp(1, bits-1) - 1
+}
+
+// decodeCounts decodes STRING output of "token:count" entries into map field with string keys
+// and integer values. Counts of repeated tokens are summed.
+func decodeCounts(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	t := field.Type()
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String || (!isInt(t.Elem().Kind()) && !isUint(t.Elem().Kind())) {
+		return fmt.Errorf("output %s: %s option requires map field with string keys and integer values, got %s",
+			output.GetName(), tagCounts, t)
+	}
+
+	if output.GetDatatype() != STRING {
+		return fmt.Errorf("output %s: %s option requires %s datatype, got %s", output.GetName(), tagCounts, STRING, output.GetDatatype())
+	}
+
+	count := 1
+	for _, dim := range output.GetShape() {
+		if dim < 0 {
+			return fmt.Errorf("invalid shape: %v", output.GetShape())
+		}
+
+		count *= int(dim)
+	}
+
+	entries, err := stringBytesToArray(rawBytes, count, opts.byteOrder(output.GetName()), opts)
+	if err != nil {
+		return fmt.Errorf("output %s: %w", output.GetName(), err)
+	}
+
+	// entries are validated before field is touched, so it's left as is on error.
+	totals := make(map[string]int64, len(entries))
+	zero := reflect.New(t.Elem()).Elem()
+
+	for i, e := range entries {
+		// token may contain colons itself, so count follows the last one.
+		idx := strings.LastIndexByte(e, ':')
+		if idx == -1 {
+			return fmt.Errorf("output %s: element %d: invalid count entry: %q", output.GetName(), i, e)
+		}
+
+		n, err := strconv.ParseInt(e[idx+1:], 10, 64)
+		if err != nil {
+			return fmt.Errorf("output %s: element %d: invalid count: %w", output.GetName(), i, err)
+		}
+
+		prev := totals[e[:idx]]
+		if n > 0 && prev > math.MaxInt64-n || n < 0 && prev < math.MinInt64-n {
+			return fmt.Errorf("output %s: element %d: total count of %q overflows int64", output.GetName(), i, e[:idx])
+		}
+
+		n += prev
+		if isInt(zero.Kind()) && zero.OverflowInt(n) || isUint(zero.Kind()) && (n < 0 || zero.OverflowUint(uint64(n))) {
+			return fmt.Errorf("output %s: element %d: count %d overflows %s", output.GetName(), i, n, t.Elem())
+		}
+
+		totals[e[:idx]] = n
+	}
+
+	res := reuseMap(field, len(totals))
+	for key, n := range totals {
+		v := reflect.New(t.Elem()).Elem()
+		if isInt(v.Kind()) {
+			v.SetInt(n)
+		} else {
+			v.SetUint(uint64(n))
+		}
+
+		res.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), v)
+	}
+
+	field.Set(res)
+
+	return nil
+}
+
+// decodeLimited decodes only the first limit elements of output in row-major order into slice field.
+// The rest of raw contents is not read.
+func decodeLimited(
+	fieldMap map[string]reflect.Value,
+	output TritonModelInferResponseOutputs,
+	rawBytes []byte,
+	opts *Options,
+	limit string,
+) error {
+	n, err := strconv.Atoi(limit)
+	if err != nil || n < 0 {
+		return fmt.Errorf("output %s: %s option requires non-negative number, got %q", output.GetName(), tagLimit, limit)
+	}
+
+	if field := fieldMap[output.GetName()]; field.Kind() != reflect.Slice {
+		return fmt.Errorf("output %s: %s option requires slice field, got %s", output.GetName(), tagLimit, field.Type())
+	}
+
+	// count saturates, as only its minimum with n is used.
+	count := 1
+	for _, dim := range output.GetShape() {
+		switch {
+		case dim < 0:
+			return fmt.Errorf("invalid shape: %v", output.GetShape())
+		case dim == 0:
+			count = 0
+		case int64(count) > math.MaxInt64/dim:
+			count = math.MaxInt
+		default:
+			count *= int(dim)
+		}
+	}
+
+	n = min(n, count)
+
+	var size int
+	switch output.GetDatatype() {
+	case STRING:
+		order := opts.byteOrder(output.GetName())
+		for i := 0; i < n && len(rawBytes) != 0; i++ {
+			if _, size, err = stringBounds(rawBytes, size, order, opts.StringLengthPrefix); err != nil {
+				return fmt.Errorf("output %s: %w", output.GetName(), err)
+			}
+		}
+	default:
+		if size, err = OutputByteSize(output.GetDatatype(), []int64{int64(n)}); err != nil {
+			return fmt.Errorf("output %s: %w", output.GetName(), err)
+		}
+	}
+
+	if size > len(rawBytes) {
+		return fmt.Errorf("output %s: raw contents length %d is less than %d bytes of %d elements",
+			output.GetName(), len(rawBytes), size, n)
+	}
+
+	if opts.Trace != nil {
+		opts.tracef("output %s: decoding %d of %d elements", output.GetName(), n, count)
+	}
+
+	return parse(fieldMap, reshaped{output, []int64{1, int64(n)}}, rawBytes[:size], opts)
+}
+
+// reverseField reverses slice field along axis, "0" or empty for outer slice
+// and "1" for every inner slice of multidimensional field.
+func reverseField(field reflect.Value, output, axis string) error {
+	if field.Kind() != reflect.Slice {
+		return fmt.Errorf("output %s: %s option requires slice field, got %s", output, tagReverse, field.Type())
+	}
+
+	switch axis {
+	case "", "0":
+		reverseSlice(field)
+	case "1":
+		if field.Type().Elem().Kind() != reflect.Slice {
+			return fmt.Errorf("output %s: %s axis 1 requires multidimensional field, got %s", output, tagReverse, field.Type())
+		}
+
+		for i := 0; i < field.Len(); i++ {
+			reverseSlice(field.Index(i))
+		}
+	default:
+		return fmt.Errorf("output %s: unknown %s axis: %s", output, tagReverse, axis)
+	}
+
+	return nil
+}
+
+// trimField removes contiguous trailing elements equal to pad from slice field,
+// or from every row of multidimensional field.
+func trimField(field reflect.Value, output, pad string) error {
+	if field.Kind() != reflect.Slice {
+		return fmt.Errorf("output %s: %s option requires slice field, got %s", output, tagTrim, field.Type())
+	}
+
+	elem := scalarType(field.Type())
+
+	var p reflect.Value
+	if elem.Kind() == reflect.String {
+		p = reflect.ValueOf(pad).Convert(elem)
+	} else {
+		var err error
+		if p, err = parseSentinel(elem, pad); err != nil {
+			return fmt.Errorf("output %s: %s option: %w", output, tagPad, err)
+		}
+	}
+
+	if field.Type().Elem().Kind() == reflect.Slice {
+		for i := 0; i < field.Len(); i++ {
+			trimSlice(field.Index(i), p)
+		}
+
+		return nil
+	}
+
+	trimSlice(field, p)
+
+	return nil
+}
+
+func trimSlice(s, pad reflect.Value) {
+	n := s.Len()
+	for n > 0 && isSentinel(s.Index(n-1), pad) {
+		n--
+	}
+
+	s.SetLen(n)
+}
+
+func reverseSlice(s reflect.Value) {
+	swap := reflect.Swapper(s.Interface())
+	for i, j := 0, s.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
+
+// decodeArgmax decodes index of maximum element of array output into integer field.
+// Rows of multidimensional output are reduced separately into slice field.
+// Ties resolve to the lowest index.
+func decodeArgmax(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	elem := scalarType(field.Type())
+	if !isInt(elem.Kind()) && !isUint(elem.Kind()) {
+		return fmt.Errorf("output %s: %s option requires integer field, got %s", output.GetName(), tagArgmax, field.Type())
+	}
+
+	val, err := decodeNative(output, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	if val.Kind() != reflect.Slice || !isNumeric(scalarType(val.Type()).Kind()) {
+		return fmt.Errorf("output %s: %s option requires numeric array, got %s", output.GetName(), tagArgmax, val.Type())
+	}
+
+	var res reflect.Value
+	if val.Type().Elem().Kind() == reflect.Slice {
+		res = reflect.MakeSlice(reflect.SliceOf(elem), val.Len(), val.Len())
+		for i := 0; i < val.Len(); i++ {
+			res.Index(i).Set(reflect.ValueOf(argmax(val.Index(i))).Convert(elem))
+		}
+	} else {
+		res = reflect.ValueOf(argmax(val)).Convert(elem)
+	}
+
+	return setTransformed(field, res, output.GetName(), opts)
+}
+
+// argmax returns index of the first maximum element of numeric slice, or 0 for empty one.
+func argmax(arr reflect.Value) int {
+	best := 0
+	for i := 1; i < arr.Len(); i++ {
+		if greater(arr.Index(i), arr.Index(best)) {
+			best = i
+		}
+	}
+
+	return best
+}
+
+func greater(a, b reflect.Value) bool {
+	switch k := a.Kind(); {
+	case isInt(k):
+		return a.Int() > b.Int()
+	case isUint(k):
+		return a.Uint() > b.Uint()
+	default:
+		// NaN is never greater, but any value is greater than NaN.
+		return a.Float() > b.Float() || (math.IsNaN(b.Float()) && !math.IsNaN(a.Float()))
+	}
+}
@@ -0,0 +1,1598 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// unmarshalOutput decodes response of the only output into dst.
+func unmarshalOutput(dst any, output *testOutput, raw []byte, opts Options) error {
+	return UnmarshalWithOptions(&testResponse{outputs: []*testOutput{output}, raw: [][]byte{raw}}, dst, opts)
+}
+
+func int16Bytes(vs ...int16) []byte {
+	b := make([]byte, 0, 2*len(vs))
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint16(b, uint16(v))
+	}
+
+	return b
+}
+
+func TestPCM16(t *testing.T) {
+	type result struct {
+		Sample  float32     `triton:"sample,pcm16"`
+		Samples []float32   `triton:"samples,pcm16"`
+		Chans   [][]float32 `triton:"chans,pcm16"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "sample", datatype: INT16, shape: []int64{1}},
+			{name: "samples", datatype: INT16, shape: []int64{1, 3}},
+			{name: "chans", datatype: INT16, shape: []int64{2, 1}},
+		},
+		raw: [][]byte{int16Bytes(16384), int16Bytes(-32768, 0, 8192), int16Bytes(-16384, 32767)},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{Sample: 0.5, Samples: []float32{-1, 0, 0.25}, Chans: [][]float32{{-0.5}, {32767.0 / 32768}}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestPCM16Errors(t *testing.T) {
+	var res struct {
+		Samples []float32 `triton:"samples,pcm16"`
+		Ints    []int16   `triton:"ints,pcm16"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "datatype",
+			output: &testOutput{name: "samples", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(1),
+			want:   "requires INT16 datatype",
+		},
+		{
+			name:   "misaligned",
+			output: &testOutput{name: "samples", datatype: INT16, shape: []int64{1, 2}},
+			raw:    []byte{1, 2, 3},
+			want:   "not a multiple",
+		},
+		{
+			name:   "field type",
+			output: &testOutput{name: "ints", datatype: INT16, shape: []int64{1, 1}},
+			raw:    int16Bytes(1),
+			want:   "types doesn't match",
+		},
+		{
+			name:   "shape",
+			output: &testOutput{name: "samples", datatype: INT16, shape: []int64{1, 1, 1}},
+			raw:    int16Bytes(1),
+			want:   "not yet supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func float32Bytes(vs ...float32) []byte {
+	b := make([]byte, 0, 4*len(vs))
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(v))
+	}
+
+	return b
+}
+
+func TestRound(t *testing.T) {
+	type result struct {
+		Even  []int     `triton:"even,round"`
+		Away  []int64   `triton:"away,round=away"`
+		Trunc []int8    `triton:"trunc,round=trunc"`
+		Value uint16    `triton:"value,round=even"`
+		Rows  [][]int32 `triton:"rows,round"`
+	}
+
+	values := float32Bytes(0.5, 1.5, -2.5, 2.7)
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "even", datatype: FLOAT32, shape: []int64{1, 4}},
+			{name: "away", datatype: FLOAT32, shape: []int64{1, 4}},
+			{name: "trunc", datatype: FLOAT32, shape: []int64{1, 4}},
+			{name: "value", datatype: FLOAT32, shape: []int64{1}},
+			{name: "rows", datatype: FLOAT32, shape: []int64{2, 2}},
+		},
+		raw: [][]byte{values, values, values, float32Bytes(65534.5), values},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Even:  []int{0, 2, -2, 3},
+		Away:  []int64{1, 2, -3, 3},
+		Trunc: []int8{0, 1, -2, 2},
+		Value: 65534,
+		Rows:  [][]int32{{0, 2}, {-2, 3}},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestRoundErrors(t *testing.T) {
+	var res struct {
+		Int   int32   `triton:"int,round"`
+		Uint  uint8   `triton:"uint,round"`
+		Float float32 `triton:"float,round"`
+		Mode  int32   `triton:"mode,round=up"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "mode",
+			output: &testOutput{name: "mode", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(1),
+			want:   "unknown rounding mode",
+		},
+		{
+			name:   "field type",
+			output: &testOutput{name: "float", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(1),
+			want:   "requires integer field",
+		},
+		{
+			name:   "datatype",
+			output: &testOutput{name: "int", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "requires float datatype",
+		},
+		{
+			name:   "overflow",
+			output: &testOutput{name: "int", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(3e9),
+			want:   "overflows",
+		},
+		{
+			name:   "negative",
+			output: &testOutput{name: "uint", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(-1),
+			want:   "overflows",
+		},
+		{
+			name:   "nan",
+			output: &testOutput{name: "int", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(float32(math.NaN())),
+			want:   "overflows",
+		},
+		{
+			name:   "length",
+			output: &testOutput{name: "int", datatype: FLOAT32, shape: []int64{1}},
+			raw:    []byte{1, 2},
+			want:   "unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestArgmax(t *testing.T) {
+	type result struct {
+		Class   int     `triton:"class,argmax"`
+		Batch   []uint8 `triton:"batch,argmax"`
+		Scores  int64   `triton:"scores,argmax"`
+		Ties    int32   `triton:"ties,argmax"`
+		Unknown int     `triton:"unknown,argmax"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "class", datatype: INT32, shape: []int64{1, 4}},
+			{name: "batch", datatype: INT8, shape: []int64{2, 3}},
+			{name: "scores", datatype: FLOAT32, shape: []int64{1, 3}},
+			{name: "ties", datatype: UINT16, shape: []int64{1, 3}},
+			{name: "unknown", datatype: FLOAT32, shape: []int64{1, 0}},
+		},
+		raw: [][]byte{
+			int32Bytes(0, 0, 1, 0),
+			{0, 0, 1, 1, 0, 0},
+			float32Bytes(float32(math.NaN()), -1, 0.5),
+			int16Bytes(3, 7, 7),
+			{},
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{Class: 2, Batch: []uint8{2, 0}, Scores: 2, Ties: 1}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestArgmaxErrors(t *testing.T) {
+	var res struct {
+		Class int     `triton:"class,argmax"`
+		Float float32 `triton:"float,argmax"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "field type",
+			output: &testOutput{name: "float", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(0, 1),
+			want:   "requires integer field",
+		},
+		{
+			name:   "scalar",
+			output: &testOutput{name: "class", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "requires numeric array",
+		},
+		{
+			name:   "strings",
+			output: &testOutput{name: "class", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("a", "b"),
+			want:   "requires numeric array",
+		},
+		{
+			name:   "misaligned",
+			output: &testOutput{name: "class", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(0, 1)[:6],
+			want:   "not a multiple",
+		},
+		{
+			name:   "batch into scalar",
+			output: &testOutput{name: "class", datatype: INT32, shape: []int64{2, 2}},
+			raw:    int32Bytes(0, 1, 1, 0),
+			want:   "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestScale(t *testing.T) {
+	type result struct {
+		Price  float64     `triton:"price,scale=100"`
+		Prices []float32   `triton:"prices,scale=1000"`
+		Rows   [][]float64 `triton:"rows,scale=0.5"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "price", datatype: INT64, shape: []int64{1}},
+			{name: "prices", datatype: UINT16, shape: []int64{1, 2}},
+			{name: "rows", datatype: INT8, shape: []int64{2, 1}},
+		},
+		raw: [][]byte{int64Bytes(-1999), int16Bytes(1500, 250), {3, 0xff}},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{Price: -19.99, Prices: []float32{1.5, 0.25}, Rows: [][]float64{{6}, {-2}}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestScaleErrors(t *testing.T) {
+	var res struct {
+		Price    float64 `triton:"price,scale=100"`
+		Zero     float64 `triton:"zero,scale=0"`
+		Negative float64 `triton:"negative,scale=-1"`
+		Inf      float64 `triton:"inf,scale=+Inf"`
+		Word     float64 `triton:"word,scale=cents"`
+		Int      int64   `triton:"int,scale=100"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "zero",
+			output: &testOutput{name: "zero", datatype: INT64, shape: []int64{1}},
+			raw:    int64Bytes(1),
+			want:   "positive number",
+		},
+		{
+			name:   "negative",
+			output: &testOutput{name: "negative", datatype: INT64, shape: []int64{1}},
+			raw:    int64Bytes(1),
+			want:   "positive number",
+		},
+		{
+			name:   "inf",
+			output: &testOutput{name: "inf", datatype: INT64, shape: []int64{1}},
+			raw:    int64Bytes(1),
+			want:   "positive number",
+		},
+		{
+			name:   "word",
+			output: &testOutput{name: "word", datatype: INT64, shape: []int64{1}},
+			raw:    int64Bytes(1),
+			want:   "positive number",
+		},
+		{
+			name:   "field type",
+			output: &testOutput{name: "int", datatype: INT64, shape: []int64{1}},
+			raw:    int64Bytes(1),
+			want:   "float field",
+		},
+		{
+			name:   "datatype",
+			output: &testOutput{name: "price", datatype: FLOAT32, shape: []int64{1}},
+			raw:    float32Bytes(1),
+			want:   "requires integer datatype",
+		},
+		{
+			name:   "length",
+			output: &testOutput{name: "price", datatype: INT64, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseString(t *testing.T) {
+	type result struct {
+		Count  int64     `triton:"count,parsestring"`
+		Scores []float32 `triton:"scores,parsestring"`
+		Flags  [][]bool  `triton:"flags,parsestring"`
+		Small  uint8     `triton:"small,parsestring"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "count", datatype: STRING, shape: []int64{1}},
+			{name: "scores", datatype: STRING, shape: []int64{1, 2}},
+			{name: "flags", datatype: STRING, shape: []int64{2, 1}},
+			{name: "small", datatype: STRING, shape: []int64{1}},
+		},
+		raw: [][]byte{encodeStrings("-42"), encodeStrings("0.5", "1e3"), encodeStrings("true", "0"), encodeStrings("255")},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{Count: -42, Scores: []float32{0.5, 1000}, Flags: [][]bool{{true}, {false}}, Small: 255}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestParseStringErrors(t *testing.T) {
+	var res struct {
+		Count  int64     `triton:"count,parsestring"`
+		Scores []float32 `triton:"scores,parsestring"`
+		Small  uint8     `triton:"small,parsestring"`
+		Label  string    `triton:"label,parsestring"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "datatype",
+			output: &testOutput{name: "count", datatype: INT64, shape: []int64{1}},
+			raw:    int64Bytes(1),
+			want:   "requires BYTES datatype",
+		},
+		{
+			name:   "field type",
+			output: &testOutput{name: "label", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("1"),
+			want:   "requires numeric field",
+		},
+		{
+			name:   "syntax",
+			output: &testOutput{name: "scores", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("0.5", "high"),
+			want:   "output scores: element 1",
+		},
+		{
+			name:   "range",
+			output: &testOutput{name: "small", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("256"),
+			want:   "out of range",
+		},
+		{
+			name:   "contents",
+			output: &testOutput{name: "scores", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("0.5"),
+			want:   "output scores",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunes(t *testing.T) {
+	type result struct {
+		Text    string   `triton:"text,runes"`
+		Char    string   `triton:"char,runes"`
+		Lines   []string `triton:"lines,runes"`
+		Invalid string   `triton:"invalid,runes"`
+		Strict  string   `triton:"strict,runes=strict"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "text", datatype: INT32, shape: []int64{1, 3}},
+			{name: "char", datatype: INT32, shape: []int64{1}},
+			{name: "lines", datatype: INT32, shape: []int64{2, 2}},
+			{name: "invalid", datatype: INT32, shape: []int64{1, 2}},
+			{name: "strict", datatype: INT32, shape: []int64{1, 2}},
+		},
+		raw: [][]byte{
+			int32Bytes('h', 'é', '世'),
+			int32Bytes('x'),
+			int32Bytes('a', 'b', 'c', 'd'),
+			int32Bytes('a', 0xD800),
+			int32Bytes('o', 'k'),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{Text: "hé世", Char: "x", Lines: []string{"ab", "cd"}, Invalid: "a�", Strict: "ok"}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestRunesErrors(t *testing.T) {
+	var res struct {
+		Text   string   `triton:"text,runes"`
+		Lines  []string `triton:"lines,runes=strict"`
+		Strict string   `triton:"strict,runes=strict"`
+		Mode   string   `triton:"mode,runes=lenient"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "datatype",
+			output: &testOutput{name: "text", datatype: INT64, shape: []int64{1, 1}},
+			raw:    int64Bytes('a'),
+			want:   "runes option requires INT32 datatype, got INT64",
+		},
+		{
+			name:   "mode",
+			output: &testOutput{name: "mode", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes('a'),
+			want:   "unknown runes mode: lenient",
+		},
+		{
+			name:   "strict surrogate",
+			output: &testOutput{name: "strict", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes('a', 0xD800),
+			want:   "output strict: invalid code point 55296 at 1",
+		},
+		{
+			name:   "strict negative row",
+			output: &testOutput{name: "lines", datatype: INT32, shape: []int64{2, 1}},
+			raw:    int32Bytes('a', -1),
+			want:   "output lines: invalid code point -1 at 0",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "text", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes('a', 'b')[:6],
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "matrix into string",
+			output: &testOutput{name: "text", datatype: INT32, shape: []int64{2, 2}},
+			raw:    int32Bytes('a', 'b', 'c', 'd'),
+			want:   "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReverse(t *testing.T) {
+	type result struct {
+		Seq     []int32    `triton:"seq,reverse"`
+		Rows    [][]int32  `triton:"rows,reverse=0"`
+		Cols    [][]int32  `triton:"cols,reverse=1"`
+		Labels  []string   `triton:"labels,reverse"`
+		Words   [][]string `triton:"words,reverse=1"`
+		Single  []float32  `triton:"single,reverse"`
+		Trimmed []int32    `triton:"trimmed,pad=0,trim,reverse"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "seq", datatype: INT32, shape: []int64{1, 4}},
+			{name: "rows", datatype: INT32, shape: []int64{3, 1}},
+			{name: "cols", datatype: INT32, shape: []int64{2, 3}},
+			{name: "labels", datatype: STRING, shape: []int64{1, 3}},
+			{name: "words", datatype: STRING, shape: []int64{2, 2}},
+			{name: "single", datatype: FLOAT32, shape: []int64{1}},
+			{name: "trimmed", datatype: INT32, shape: []int64{1, 4}},
+		},
+		raw: [][]byte{
+			int32Bytes(1, 2, 3, 4),
+			int32Bytes(1, 2, 3),
+			int32Bytes(1, 2, 3, 4, 5, 6),
+			encodeStrings("a", "b", "c"),
+			encodeStrings("a", "b", "c", "d"),
+			float32Bytes(0.5),
+			int32Bytes(1, 2, 0, 0),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Seq:     []int32{4, 3, 2, 1},
+		Rows:    [][]int32{{3}, {2}, {1}},
+		Cols:    [][]int32{{3, 2, 1}, {6, 5, 4}},
+		Labels:  []string{"c", "b", "a"},
+		Words:   [][]string{{"b", "a"}, {"d", "c"}},
+		Single:  []float32{0.5},
+		Trimmed: []int32{2, 1},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestReverseErrors(t *testing.T) {
+	var res struct {
+		Scalar int32     `triton:"scalar,reverse"`
+		Flat   []int32   `triton:"flat,reverse=1"`
+		Axis   [][]int32 `triton:"axis,reverse=2"`
+		Seq    []int32   `triton:"seq,reverse"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "scalar field",
+			output: &testOutput{name: "scalar", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "output scalar: reverse option requires slice field, got int32",
+		},
+		{
+			name:   "inner axis of flat field",
+			output: &testOutput{name: "flat", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 2),
+			want:   "output flat: reverse axis 1 requires multidimensional field, got []int32",
+		},
+		{
+			name:   "unknown axis",
+			output: &testOutput{name: "axis", datatype: INT32, shape: []int64{2, 1}},
+			raw:    int32Bytes(1, 2),
+			want:   "output axis: unknown reverse axis: 2",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "seq", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 2)[:5],
+			want:   "not a multiple of element size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimit(t *testing.T) {
+	type result struct {
+		Emb     []float32 `triton:"emb,limit=2"`
+		Flat    []int32   `triton:"flat,limit=3"`
+		Labels  []string  `triton:"labels,limit=1"`
+		Short   []int16   `triton:"short,limit=10"`
+		None    []int32   `triton:"none,limit=0"`
+		Packed  []int8    `triton:"packed,limit=3"`
+		Vector  []int32   `triton:"vector,limit=2"`
+		Strings []string  `triton:"strings,limit=2"`
+		Huge    []int32   `triton:"huge,limit=2"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "emb", datatype: FLOAT32, shape: []int64{1, 4}},
+			{name: "flat", datatype: INT32, shape: []int64{2, 2}},
+			{name: "labels", datatype: STRING, shape: []int64{1, 3}},
+			{name: "short", datatype: INT16, shape: []int64{1, 2}},
+			{name: "none", datatype: INT32, shape: []int64{1, 2}},
+			{name: "packed", datatype: INT4, shape: []int64{1, 4}},
+			{name: "vector", datatype: INT32, shape: []int64{3}},
+			{name: "strings", datatype: STRING, shape: []int64{1, 3}},
+			{name: "huge", datatype: INT32, shape: []int64{1 << 62, 3}},
+		},
+		raw: [][]byte{
+			float32Bytes(1, 2, 3, 4),
+			int32Bytes(5, 6, 7, 8),
+			encodeStrings("a", "b", "c"),
+			int16Bytes(9, 10),
+			int32Bytes(11, 12),
+			{0x21, 0x43},
+			int32Bytes(13, 14, 15),
+			{},
+			int32Bytes(16, 17, 18),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Emb:     []float32{1, 2},
+		Flat:    []int32{5, 6, 7},
+		Labels:  []string{"a"},
+		Short:   []int16{9, 10},
+		None:    []int32{},
+		Packed:  []int8{1, 2, 3},
+		Vector:  []int32{13, 14},
+		Strings: []string{"", ""},
+		Huge:    []int32{16, 17},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestLimitErrors(t *testing.T) {
+	var res struct {
+		Scalar int32     `triton:"scalar,limit=1"`
+		Emb    []float32 `triton:"emb,limit=2"`
+		Labels []string  `triton:"labels,limit=2"`
+		Bad    []int32   `triton:"bad,limit=-1"`
+		Word   []int32   `triton:"word,limit=few"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "negative",
+			output: &testOutput{name: "bad", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 2),
+			want:   `output bad: limit option requires non-negative number, got "-1"`,
+		},
+		{
+			name:   "not a number",
+			output: &testOutput{name: "word", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 2),
+			want:   `output word: limit option requires non-negative number, got "few"`,
+		},
+		{
+			name:   "scalar field",
+			output: &testOutput{name: "scalar", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "output scalar: limit option requires slice field, got int32",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "emb", datatype: FLOAT32, shape: []int64{1, 4}},
+			raw:    float32Bytes(1),
+			want:   "output emb: raw contents length 4 is less than 8 bytes of 2 elements",
+		},
+		{
+			name:   "short string",
+			output: &testOutput{name: "labels", datatype: STRING, shape: []int64{1, 3}},
+			raw:    encodeStrings("a"),
+			want:   "output labels: binary read failed: string length at offset 5",
+		},
+		{
+			name:   "negative dimension",
+			output: &testOutput{name: "emb", datatype: FLOAT32, shape: []int64{1, -4}},
+			raw:    float32Bytes(1, 2),
+			want:   "invalid shape: [1 -4]",
+		},
+		{
+			name:   "datatype",
+			output: &testOutput{name: "emb", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 2),
+			want:   "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCounts(t *testing.T) {
+	type (
+		word   string
+		result struct {
+			Bag    map[string]int    `triton:"bag,counts"`
+			Matrix map[string]uint16 `triton:"matrix,counts"`
+			Named  map[word]int64    `triton:"named,counts"`
+			Colons map[string]int    `triton:"colons,counts"`
+			Reused map[string]int    `triton:"reused,counts"`
+			Deltas map[string]int8   `triton:"deltas,counts"`
+		}
+	)
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "bag", datatype: STRING, shape: []int64{1, 3}},
+			{name: "matrix", datatype: STRING, shape: []int64{2, 1}},
+			{name: "named", datatype: STRING, shape: []int64{1}},
+			{name: "colons", datatype: STRING, shape: []int64{1, 2}},
+			{name: "reused", datatype: STRING, shape: []int64{1, 1}},
+			{name: "deltas", datatype: STRING, shape: []int64{1, 2}},
+		},
+		raw: [][]byte{
+			encodeStrings("the:3", "cat:1", "the:2"),
+			encodeStrings("a:1", "b:65535"),
+			encodeStrings("go:7"),
+			encodeStrings("http://x:2", ":1"),
+			encodeStrings("new:1"),
+			encodeStrings("up:5", "up:-7"),
+		},
+	}
+
+	res := result{Reused: map[string]int{"old": 1}}
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Bag:    map[string]int{"the": 5, "cat": 1},
+		Matrix: map[string]uint16{"a": 1, "b": 65535},
+		Named:  map[word]int64{"go": 7},
+		Colons: map[string]int{"http://x": 2, "": 1},
+		Reused: map[string]int{"new": 1},
+		Deltas: map[string]int8{"up": -2},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestCountsErrors(t *testing.T) {
+	var res struct {
+		Bag    map[string]int     `triton:"bag,counts"`
+		Small  map[string]uint8   `triton:"small,counts"`
+		Wide   map[string]int64   `triton:"wide,counts"`
+		Floats map[string]float64 `triton:"floats,counts"`
+		Slice  []int              `triton:"slice,counts"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "datatype",
+			output: &testOutput{name: "bag", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(1),
+			want:   "output bag: counts option requires BYTES datatype, got INT32",
+		},
+		{
+			name:   "float values",
+			output: &testOutput{name: "floats", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("a:1"),
+			want:   "counts option requires map field with string keys and integer values, got map[string]float64",
+		},
+		{
+			name:   "slice field",
+			output: &testOutput{name: "slice", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("a:1"),
+			want:   "counts option requires map field with string keys and integer values, got []int",
+		},
+		{
+			name:   "missing colon",
+			output: &testOutput{name: "bag", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("a:1", "b"),
+			want:   `output bag: element 1: invalid count entry: "b"`,
+		},
+		{
+			name:   "invalid count",
+			output: &testOutput{name: "bag", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("a:x", "b:1"),
+			want:   "output bag: element 0: invalid count",
+		},
+		{
+			name:   "overflow",
+			output: &testOutput{name: "small", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("a:200", "a:56"),
+			want:   "output small: element 1: count 256 overflows uint8",
+		},
+		{
+			name:   "negative unsigned",
+			output: &testOutput{name: "small", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("a:-1"),
+			want:   "output small: element 0: count -1 overflows uint8",
+		},
+		{
+			name:   "total overflow",
+			output: &testOutput{name: "wide", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("a:9223372036854775807", "a:1"),
+			want:   `output wide: element 1: total count of "a" overflows int64`,
+		},
+		{
+			name:   "total underflow",
+			output: &testOutput{name: "wide", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("a:-9223372036854775808", "a:-1"),
+			want:   `output wide: element 1: total count of "a" overflows int64`,
+		},
+		{
+			name:   "missing element",
+			output: &testOutput{name: "bag", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("a:1"),
+			want:   "output bag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res.Bag = map[string]int{"kept": 1}
+
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+
+			if !reflect.DeepEqual(res.Bag, map[string]int{"kept": 1}) {
+				t.Errorf("got bag %v, want it untouched", res.Bag)
+			}
+		})
+	}
+}
+
+func float64Bytes(vs ...float64) []byte {
+	b := make([]byte, 0, 8*len(vs))
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	}
+
+	return b
+}
+
+func TestQuant(t *testing.T) {
+	type result struct {
+		Act     []int8    `triton:"act,quant=scale:0.5"`
+		Shifted []uint8   `triton:"shifted,quant=scale:0.02,zero:128"`
+		Matrix  [][]int16 `triton:"matrix,quant=scale:1,zero:-1"`
+		Half    int8      `triton:"half,quant=scale:0.5"`
+		Wide    []int64   `triton:"wide,quant=scale:1"`
+		Max     []uint64  `triton:"max,quant=scale:1"`
+		NaN     []int8    `triton:"nan,quant=scale:1,zero:300"`
+	}
+
+	inf := math.Inf(1)
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "act", datatype: FLOAT32, shape: []int64{1, 6}},
+			{name: "shifted", datatype: FLOAT32, shape: []int64{1, 4}},
+			{name: "matrix", datatype: FLOAT64, shape: []int64{2, 1}},
+			{name: "half", datatype: FLOAT16, shape: []int64{1}},
+			{name: "wide", datatype: FLOAT64, shape: []int64{1, 2}},
+			{name: "max", datatype: FLOAT64, shape: []int64{1, 2}},
+			{name: "nan", datatype: FLOAT32, shape: []int64{1, 2}},
+		},
+		raw: [][]byte{
+			float32Bytes(1, -1, 0.25, 0.75, 100, -100),
+			float32Bytes(0, 1, -1, 2.56),
+			float64Bytes(2.5, 3.5),
+			halfBytes(0x3e00),
+			float64Bytes(inf, -inf),
+			float64Bytes(inf, -1),
+			float32Bytes(float32(math.NaN()), 1),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Act:     []int8{2, -2, 0, 2, 127, -128},
+		Shifted: []uint8{128, 178, 78, 255},
+		Matrix:  [][]int16{{1}, {3}},
+		Half:    3,
+		Wide:    []int64{math.MaxInt64, math.MinInt64},
+		Max:     []uint64{math.MaxUint64, 0},
+		NaN:     []int8{127, 127},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestQuantErrors(t *testing.T) {
+	var res struct {
+		Missing []int8    `triton:"missing,quant=zero:1"`
+		Zero    []int8    `triton:"zero,quant=scale:0"`
+		Inf     []int8    `triton:"inf,quant=scale:Inf"`
+		NaN     []int8    `triton:"nan,quant=scale:NaN"`
+		Point   []int8    `triton:"point,quant=scale:1,zero:0.5"`
+		Float   []float32 `triton:"float,quant=scale:1"`
+		Act     []int8    `triton:"act,quant=scale:1"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "missing scale",
+			output: &testOutput{name: "missing", datatype: FLOAT32, shape: []int64{1, 1}},
+			raw:    float32Bytes(1),
+			want:   `output missing: quant option requires positive scale, got ""`,
+		},
+		{
+			name:   "zero scale",
+			output: &testOutput{name: "zero", datatype: FLOAT32, shape: []int64{1, 1}},
+			raw:    float32Bytes(1),
+			want:   `output zero: quant option requires positive scale, got "0"`,
+		},
+		{
+			name:   "infinite scale",
+			output: &testOutput{name: "inf", datatype: FLOAT32, shape: []int64{1, 1}},
+			raw:    float32Bytes(1),
+			want:   `output inf: quant option requires positive scale, got "Inf"`,
+		},
+		{
+			name:   "NaN scale",
+			output: &testOutput{name: "nan", datatype: FLOAT32, shape: []int64{1, 1}},
+			raw:    float32Bytes(1),
+			want:   `output nan: quant option requires positive scale, got "NaN"`,
+		},
+		{
+			name:   "zero point",
+			output: &testOutput{name: "point", datatype: FLOAT32, shape: []int64{1, 1}},
+			raw:    float32Bytes(1),
+			want:   `output point: quant option requires integer zero point, got "0.5"`,
+		},
+		{
+			name:   "float field",
+			output: &testOutput{name: "float", datatype: FLOAT32, shape: []int64{1, 1}},
+			raw:    float32Bytes(1),
+			want:   "output float: quant option requires integer field, got []float32",
+		},
+		{
+			name:   "integer datatype",
+			output: &testOutput{name: "act", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(1),
+			want:   "output act: quant option requires float datatype, got INT32",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "act", datatype: FLOAT32, shape: []int64{1, 2}},
+			raw:    float32Bytes(1, 2)[:6],
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "matrix into slice",
+			output: &testOutput{name: "act", datatype: FLOAT32, shape: []int64{2, 1}},
+			raw:    float32Bytes(1, 2),
+			want:   "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	type result struct {
+		At      time.Time     `triton:"at,unixnano"`
+		Series  []time.Time   `triton:"series,unixmillis"`
+		Matrix  [][]time.Time `triton:"matrix,unixmillis"`
+		Vector  []time.Time   `triton:"vector,unixnano"`
+		Seconds []time.Time   `triton:"seconds,unixmillis"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "at", datatype: INT64, shape: []int64{1}},
+			{name: "series", datatype: INT64, shape: []int64{1, 2}},
+			{name: "matrix", datatype: UINT32, shape: []int64{2, 1}},
+			{name: "vector", datatype: INT64, shape: []int64{2}},
+			{name: "seconds", datatype: INT32, shape: []int64{1, 1}},
+		},
+		raw: [][]byte{
+			int64Bytes(1_700_000_000_123_456_789),
+			int64Bytes(1_700_000_000_000, -1_000),
+			int32Bytes(1_000, 2_000),
+			int64Bytes(0, math.MaxInt64),
+			int32Bytes(math.MaxInt32),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		At:      time.Unix(1_700_000_000, 123_456_789),
+		Series:  []time.Time{time.UnixMilli(1_700_000_000_000), time.Unix(-1, 0)},
+		Matrix:  [][]time.Time{{time.Unix(1, 0)}, {time.Unix(2, 0)}},
+		Vector:  []time.Time{time.Unix(0, 0), time.Unix(0, math.MaxInt64)},
+		Seconds: []time.Time{time.UnixMilli(math.MaxInt32)},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestTimestampsErrors(t *testing.T) {
+	var res struct {
+		At     time.Time   `triton:"at,unixnano"`
+		Series []time.Time `triton:"series,unixmillis"`
+		Ints   []int64     `triton:"ints,unixmillis"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "field",
+			output: &testOutput{name: "ints", datatype: INT64, shape: []int64{1, 1}},
+			raw:    int64Bytes(1),
+			want:   "output ints: timestamp options require time.Time field, got []int64",
+		},
+		{
+			name:   "float datatype",
+			output: &testOutput{name: "series", datatype: FLOAT64, shape: []int64{1, 1}},
+			raw:    float64Bytes(1),
+			want:   "output series: timestamp options require integer datatype, got FP64",
+		},
+		{
+			name:   "string datatype",
+			output: &testOutput{name: "at", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings("1"),
+			want:   "output at: timestamp options require integer datatype, got BYTES",
+		},
+		{
+			name:   "above range",
+			output: &testOutput{name: "series", datatype: INT64, shape: []int64{1, 2}},
+			raw:    int64Bytes(1, math.MaxInt64/int64(time.Millisecond)+1),
+			want:   "output series: timestamp 9223372036855 is out of range",
+		},
+		{
+			name:   "below range",
+			output: &testOutput{name: "series", datatype: INT64, shape: []int64{1, 1}},
+			raw:    int64Bytes(-math.MaxInt64/int64(time.Millisecond) - 1),
+			want:   "output series: timestamp -9223372036855 is out of range",
+		},
+		{
+			name:   "unsigned above range",
+			output: &testOutput{name: "series", datatype: UINT64, shape: []int64{1, 1}},
+			raw:    int64Bytes(-1),
+			want:   "output series: timestamp 18446744073709551615 is out of range",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "series", datatype: INT64, shape: []int64{1, 2}},
+			raw:    int64Bytes(1, 2)[:9],
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "matrix into slice",
+			output: &testOutput{name: "series", datatype: INT64, shape: []int64{2, 1}},
+			raw:    int64Bytes(1, 2),
+			want:   "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBitset(t *testing.T) {
+	type result struct {
+		Mask   []uint64 `triton:"mask,bitset"`
+		Bytes  []uint8  `triton:"bytes,bitset"`
+		Matrix []uint16 `triton:"matrix,bitset"`
+		Empty  []uint32 `triton:"empty,bitset"`
+	}
+
+	mask := make([]byte, 70)
+	mask[0], mask[6], mask[69] = 1, 1, 2
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "mask", datatype: BOOL, shape: []int64{1, 70}},
+			{name: "bytes", datatype: BOOL, shape: []int64{9}},
+			{name: "matrix", datatype: BOOL, shape: []int64{2, 3}},
+			{name: "empty", datatype: BOOL, shape: []int64{0}},
+		},
+		raw: [][]byte{mask, {1, 0, 0, 0, 0, 0, 0, 1, 1}, {0, 1, 0, 1, 1, 0}, {}},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Mask:   []uint64{1<<0 | 1<<6, 1 << 5},
+		Bytes:  []uint8{0x81, 0x01},
+		Matrix: []uint16{0b011010},
+		Empty:  []uint32{},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestBitsetErrors(t *testing.T) {
+	var res struct {
+		Mask    []uint64  `triton:"mask,bitset"`
+		Signed  []int64   `triton:"signed,bitset"`
+		Pointer []uintptr `triton:"pointer,bitset"`
+		Bools   []bool    `triton:"bools,bitset"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "signed field",
+			output: &testOutput{name: "signed", datatype: BOOL, shape: []int64{1}},
+			raw:    []byte{1},
+			want:   "output signed: bitset option requires slice of unsigned integers field, got []int64",
+		},
+		{
+			name:   "uintptr field",
+			output: &testOutput{name: "pointer", datatype: BOOL, shape: []int64{1}},
+			raw:    []byte{1},
+			want:   "output pointer: bitset option requires slice of unsigned integers field, got []uintptr",
+		},
+		{
+			name:   "bool field",
+			output: &testOutput{name: "bools", datatype: BOOL, shape: []int64{1}},
+			raw:    []byte{1},
+			want:   "output bools: bitset option requires slice of unsigned integers field, got []bool",
+		},
+		{
+			name:   "datatype",
+			output: &testOutput{name: "mask", datatype: UINT8, shape: []int64{1}},
+			raw:    []byte{1},
+			want:   "output mask: bitset option requires BOOL datatype, got UINT8",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "mask", datatype: BOOL, shape: []int64{2, 2}},
+			raw:    []byte{1, 0, 1},
+			want:   "output mask: raw contents length 3 doesn't match shape [2 2]",
+		},
+		{
+			name:   "long",
+			output: &testOutput{name: "mask", datatype: BOOL, shape: []int64{2}},
+			raw:    []byte{1, 0, 1},
+			want:   "output mask: raw contents length 3 doesn't match shape [2]",
+		},
+		{
+			name:   "negative dimension",
+			output: &testOutput{name: "mask", datatype: BOOL, shape: []int64{-1}},
+			raw:    []byte{1},
+			want:   "output mask",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDequant(t *testing.T) {
+	type result struct {
+		Act    []float32   `triton:"act,dequant=scale:0.5"`
+		Zero   []float32   `triton:"zero,dequant=scale:0.02,zero:128"`
+		Matrix [][]float64 `triton:"matrix,dequant=scale:2,zero:-1"`
+		Single float64     `triton:"single,dequant=scale:0.25"`
+		Params []float32   `triton:"params,dequant"`
+		Tag    []float32   `triton:"tag,dequant=scale:1"`
+	}
+
+	resp := &Response{
+		Outputs: []TritonModelInferResponseOutputs{
+			&httpOutput{Name: "act", Datatype: INT8, Shape: []int64{1, 3}},
+			&httpOutput{Name: "zero", Datatype: UINT8, Shape: []int64{3}},
+			&httpOutput{Name: "matrix", Datatype: INT32, Shape: []int64{2, 1}},
+			&httpOutput{Name: "single", Datatype: INT64, Shape: []int64{1}},
+			&httpOutput{
+				Name: "params", Datatype: UINT8, Shape: []int64{1, 2},
+				Parameters: map[string]any{"scale": 0.5, "zero": float64(10)},
+			},
+			&httpOutput{
+				Name: "tag", Datatype: INT16, Shape: []int64{1, 1},
+				Parameters: map[string]any{"scale": 4.0, "zero": "3"},
+			},
+		},
+		RawOutputContents: [][]byte{
+			{2, 0xfe, 0x7f},
+			{128, 178, 0},
+			int32Bytes(1, -1),
+			int64Bytes(-8),
+			{10, 14},
+			int16Bytes(5),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Act:    []float32{1, -1, 63.5},
+		Zero:   []float32{0, 1, -2.56},
+		Matrix: [][]float64{{4}, {0}},
+		Single: -2,
+		Params: []float32{0, 2},
+		Tag:    []float32{2},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestDequantErrors(t *testing.T) {
+	var res struct {
+		Missing []float32 `triton:"missing,dequant=zero:1"`
+		Zero    []float32 `triton:"zero,dequant=scale:0"`
+		Inf     []float32 `triton:"inf,dequant=scale:Inf"`
+		Point   []float32 `triton:"point,dequant=scale:1,zero:0.5"`
+		Int     []int32   `triton:"int,dequant=scale:1"`
+		Act     []float32 `triton:"act,dequant=scale:1"`
+		Params  []float32 `triton:"params,dequant"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "missing scale",
+			output: &testOutput{name: "missing", datatype: INT8, shape: []int64{1, 1}},
+			raw:    []byte{1},
+			want:   `output missing: dequant option requires positive scale, got ""`,
+		},
+		{
+			name:   "zero scale",
+			output: &testOutput{name: "zero", datatype: INT8, shape: []int64{1, 1}},
+			raw:    []byte{1},
+			want:   `output zero: dequant option requires positive scale, got "0"`,
+		},
+		{
+			name:   "infinite scale",
+			output: &testOutput{name: "inf", datatype: INT8, shape: []int64{1, 1}},
+			raw:    []byte{1},
+			want:   `output inf: dequant option requires positive scale, got "Inf"`,
+		},
+		{
+			name:   "zero point",
+			output: &testOutput{name: "point", datatype: INT8, shape: []int64{1, 1}},
+			raw:    []byte{1},
+			want:   `output point: dequant option requires integer zero point, got "0.5"`,
+		},
+		{
+			name:   "integer field",
+			output: &testOutput{name: "int", datatype: INT8, shape: []int64{1, 1}},
+			raw:    []byte{1},
+			want:   "output int: dequant option requires float field, got []int32",
+		},
+		{
+			name:   "float datatype",
+			output: &testOutput{name: "act", datatype: FLOAT32, shape: []int64{1, 1}},
+			raw:    float32Bytes(1),
+			want:   "output act: dequant option requires integer datatype, got FP32",
+		},
+		{
+			name:   "without parameters",
+			output: &testOutput{name: "params", datatype: INT8, shape: []int64{1, 1}},
+			raw:    []byte{1},
+			want:   `output params: dequant option requires positive scale, got ""`,
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "act", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1, 2)[:6],
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "matrix into slice",
+			output: &testOutput{name: "act", datatype: INT8, shape: []int64{2, 1}},
+			raw:    []byte{1, 2},
+			want:   "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrim(t *testing.T) {
+	type result struct {
+		Tokens   []int64     `triton:"tokens,pad=0,trim"`
+		Inner    []int32     `triton:"inner,pad=0,trim"`
+		AllPad   []int32     `triton:"all_pad,pad=-1,trim"`
+		Rows     [][]uint8   `triton:"rows,pad=255,trim"`
+		NaN      []float32   `triton:"nan,pad=NaN,trim"`
+		Labels   []string    `triton:"labels,pad=,trim"`
+		Named    []string    `triton:"named,pad=<pad>,trim"`
+		Reversed []int16     `triton:"reversed,pad=0,trim,reverse"`
+		Matrix   [][]float64 `triton:"matrix,pad=0.5,trim"`
+	}
+
+	nan := float32(math.NaN())
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "tokens", datatype: INT64, shape: []int64{1, 5}},
+			{name: "inner", datatype: INT32, shape: []int64{4}},
+			{name: "all_pad", datatype: INT32, shape: []int64{1, 2}},
+			{name: "rows", datatype: UINT8, shape: []int64{2, 3}},
+			{name: "nan", datatype: FLOAT32, shape: []int64{1, 3}},
+			{name: "labels", datatype: STRING, shape: []int64{1, 3}},
+			{name: "named", datatype: STRING, shape: []int64{1, 3}},
+			{name: "reversed", datatype: INT16, shape: []int64{1, 4}},
+			{name: "matrix", datatype: FLOAT64, shape: []int64{2, 2}},
+		},
+		raw: [][]byte{
+			int64Bytes(5, 7, 0, 0, 0),
+			int32Bytes(0, 3, 0, 4),
+			int32Bytes(-1, -1),
+			{1, 255, 255, 255, 2, 3},
+			float32Bytes(1, nan, nan),
+			encodeStrings("a", "", ""),
+			encodeStrings("b", "<pad>", "<pad>"),
+			int16Bytes(1, 2, 0, 0),
+			float64Bytes(1, 0.5, 0.5, 0.5),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Tokens:   []int64{5, 7},
+		Inner:    []int32{0, 3, 0, 4},
+		AllPad:   []int32{},
+		Rows:     [][]uint8{{1}, {255, 2, 3}},
+		NaN:      []float32{1},
+		Labels:   []string{"a"},
+		Named:    []string{"b"},
+		Reversed: []int16{2, 1},
+		Matrix:   [][]float64{{1}, {}},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestTrimErrors(t *testing.T) {
+	var res struct {
+		PadOnly  []int32   `triton:"pad_only,pad=0"`
+		TrimOnly []int32   `triton:"trim_only,trim"`
+		Scalar   int32     `triton:"scalar,pad=0,trim"`
+		Invalid  []int32   `triton:"invalid,pad=x,trim"`
+		Range    []uint8   `triton:"range,pad=-1,trim"`
+		Bools    []bool    `triton:"bools,pad=0,trim"`
+		Tokens   []int64   `triton:"tokens,pad=0,trim"`
+		Floats   []float32 `triton:"floats,pad=0,trim"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "pad without trim",
+			output: &testOutput{name: "pad_only", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(0),
+			want:   "output pad_only: pad and trim options must be used together",
+		},
+		{
+			name:   "trim without pad",
+			output: &testOutput{name: "trim_only", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(0),
+			want:   "output trim_only: pad and trim options must be used together",
+		},
+		{
+			name:   "scalar field",
+			output: &testOutput{name: "scalar", datatype: INT32, shape: []int64{1}},
+			raw:    int32Bytes(0),
+			want:   "output scalar: trim option requires slice field, got int32",
+		},
+		{
+			name:   "invalid pad",
+			output: &testOutput{name: "invalid", datatype: INT32, shape: []int64{1, 1}},
+			raw:    int32Bytes(0),
+			want:   `output invalid: pad option: strconv.ParseInt: parsing "x": invalid syntax`,
+		},
+		{
+			name:   "pad out of range",
+			output: &testOutput{name: "range", datatype: UINT8, shape: []int64{1, 1}},
+			raw:    []byte{0},
+			want:   "output range: pad option",
+		},
+		{
+			name:   "bool field",
+			output: &testOutput{name: "bools", datatype: BOOL, shape: []int64{1, 1}},
+			raw:    []byte{0},
+			want:   "output bools: pad option: numeric field required, got bool",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "tokens", datatype: INT64, shape: []int64{1, 2}},
+			raw:    int64Bytes(1, 0)[:12],
+			want:   "not a multiple of element size",
+		},
+		{
+			name:   "matrix into slice",
+			output: &testOutput{name: "floats", datatype: FLOAT32, shape: []int64{2, 1}},
+			raw:    float32Bytes(1, 0),
+			want:   "types doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -2,10 +2,17 @@ package tritonparser
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"math"
 	"reflect"
+	"slices"
+	"time"
+	"unsafe"
 )
 
 const tag = "triton"
@@ -22,60 +29,735 @@ type TritonModelInferResponseOutputs interface {
 }
 
 // Unmarshal function is reading data from ModelInferResponse and stores values v.
-// v must be pointer to structure.
+// v must be pointer to structure. If response has a single output, v may also point to
+// a value of any other type output can be decoded into, e.g. *[]float32.
 // Compatibility between different versions of api should be granted by use of interfaces.
+//
+// Fields are matched with outputs by name from "triton" tag. Options may follow the name after comma:
+//   - required: Unmarshal returns error if output is missing in response, e.g. `triton:"logits,required"`.
+//   - bigendian, littleendian: override byte order of output, e.g. `triton:"vec,bigendian"`.
+//   - image: UINT8 output of shape [H, W, C] is decoded into image.Image, image.Gray, image.RGBA
+//     or image.NRGBA field, e.g. `triton:"out,image"`. C is 1 for grayscale, 3 for RGB and 4 for RGBA.
+//   - pcm16: INT16 samples are normalized to [-1, 1] float32, e.g. `triton:"audio,pcm16"`.
+//   - round: float output is rounded into integer field. Mode is one of even (default), away and trunc,
+//     e.g. `triton:"score,round=trunc"`.
+//   - runes: INT32 code points are joined into string field, or into slice of strings for every row
+//     of multidimensional output, e.g. `triton:"chars,runes"`. Invalid code points are replaced
+//     with utf8.RuneError, or rejected with runes=strict.
+//   - parsestring: BYTES output holding formatted numbers is parsed into numeric or bool field,
+//     e.g. `triton:"x,parsestring"`.
+//   - scale: integer output holds fixed-point decimals that are divided by positive scale
+//     into float field, e.g. `triton:"price,scale=100"`.
+//   - argmax: index of maximum element of array output is stored into integer field,
+//     or into slice of integers for every row of multidimensional output, e.g. `triton:"pred,argmax"`.
+//   - nested: BYTES output holds serialized response that is decoded with Options.NestedDecoder
+//     and unmarshaled into struct field, e.g. `triton:"inner,nested"`.
+//   - pad, trim: contiguous trailing elements equal to pad are removed from slice field,
+//     or from every row of multidimensional field, e.g. `triton:"toks,pad=0,trim"`.
+//   - axes: axes of output are permuted before decoding, so axis i of decoded value is axis axes[i]
+//     of output, e.g. `triton:"t,axes=1,0"` transposes matrix. Packed 4-bit outputs are not supported.
+//   - reverse: decoded slice is reversed, e.g. `triton:"seq,reverse"`. Axis of multidimensional
+//     output is 0 (default) for order of rows and 1 for order within rows, e.g. `triton:"seq,reverse=1"`.
+//   - quant: float output is quantized into integer field as round(x/scale)+zero, rounding half to even
+//     and clamping to range of field type, e.g. `triton:"act,quant=scale:0.02,zero:128"`.
+//   - dequant: integer output is dequantized into float field as (x-zero)*scale,
+//     e.g. `triton:"act,dequant=scale:0.02,zero:128"`. Scale and zero point missing in tag
+//     are taken from scale and zero parameters of output.
+//   - counts: BYTES output of "token:count" entries is decoded into map[string]int field,
+//     summing counts of repeated tokens, e.g. `triton:"bow,counts"`.
+//   - limit: only the first N elements of output in row-major order are decoded into slice field,
+//     e.g. `triton:"emb,limit=16"`.
+//   - ring: elements of output are pushed into RingBuffer field, which is allocated with given
+//     capacity if it has none, e.g. `triton:"audio,ring=16000"`.
+//   - concat: one-dimensional outputs listed with | are concatenated into slice field in order,
+//     e.g. `triton:"emb,concat=emb_0|emb_1"`. Parts must share datatype.
+//   - missing: elements equal to sentinel are left nil in slice of pointers field, e.g. `triton:"id,missing=-1"`.
+//     Scalar field equal to sentinel is zeroed and Valid of its sql.Null-style wrapper stays false.
+//     Sentinel of float fields may be NaN.
+//   - unixnano, unixmillis: integer output of Unix times in nanoseconds or milliseconds is decoded
+//     into time.Time field or slice of them, e.g. `triton:"ts,unixmillis"`. Times beyond years 1678 to 2262 are rejected.
+//   - indices: output of values is paired with one-dimensional integer output of their indices
+//     and decoded into Sparse or map[int64]T field, e.g. `triton:"feat,indices=feat_idx"`.
+//     Lengths of outputs must match and indices must be unique in map field.
+//   - lengths: one-dimensional output of values is split into rows of slice of slices field by lengths
+//     in one-dimensional integer output, e.g. `triton:"vals,lengths=lens"`. Lengths must sum up to number of values.
+//   - remaining: map[string]any field tagged `triton:",remaining"` receives outputs without field by name,
+//     decoded into values of native type of their datatype, e.g. []float32 for FP32 output of shape [N].
+//     Outputs of rank above 2 are flattened.
+//   - bitset: BOOL output of any shape is packed into slice of unsigned integers, bit i of which
+//     is element i in row-major order, e.g. `triton:"mask,bitset"` with []uint64 field.
+//   - labels: integer output of class indices is decoded into string field, or slice of them,
+//     with labels of table registered by RegisterLabels, e.g. `triton:"class,labels=imagenet"`.
+//   - zip: outputs matching tags of fields of element struct of slice field are zipped by index,
+//     e.g. `triton:",zip"` field of type []Box with `triton:"score"` and `triton:"label"` fields.
+//     Columns must have the same number of elements, rows of multidimensional outputs are elements.
+//   - flat: numeric output of any rank is decoded into slice field in row-major order, e.g. `triton:"t,flat"`.
+//   - shape: []int64 field receives shape of output instead of its contents, e.g. `triton:"t,shape"`,
+//     so it may accompany flat field.
+//   - parameters: map[string]any field receives parameters of output instead of its contents,
+//     e.g. `triton:"probs,parameters"`. Outputs must have GetParameters method, as generated
+//     InferOutputTensor does. InferParameter values are unwrapped to bool, int64, uint64, float64 or string.
+//
+// One-dimensional outputs, e.g. of shape [1], are decoded into a single value,
+// or into a slice if field is a slice.
+//
+// If response implements TritonErrorResponse and carries error message, Unmarshal returns *InferenceError
+// and v is untouched.
+//
+// BYTES outputs with empty raw contents are decoded into empty strings of output shape,
+// so they are distinguishable from missing outputs, which leave fields untouched.
+//
+// Nil raw contents of output with elements, as opposed to empty ones, zero its field,
+// or make Unmarshal return error if field is required.
+//
+// Elements of BYTES outputs may be decoded into [][]byte fields, or [][][]byte for multidimensional outputs.
+// The only element of BYTES output of shape [1] may be decoded into []byte field.
+//
+// Fields of sync/atomic types Bool, Int32, Int64, Uint32, Uint64 and Value receive values with Store,
+// so they may be read concurrently with decoding. They are zeroed with Store as well,
+// e.g. for output without raw contents or value equal to sentinel of missing option.
+//
+// Maps of fields, e.g. of counts or remaining options, are cleared and reused rather than allocated,
+// if they are not nil, so they may be presized for known keys.
+//
+// Slice of any fields receive elements boxed as native type of output datatype, e.g. float32 for FP32,
+// flattened into []any or by rows into [][]any.
+//
+// BYTES outputs of Triton classification extension are decoded into Classification fields or slices of them.
+//
+// If response implements TritonModelInfo, string fields tagged `triton:"_model_name"` and
+// `triton:"_model_version"` receive name and version of model.
+// If response has GetParameters method, as generated ModelInferResponse does, fields tagged
+// `triton:"_sequence_id"`, `triton:"_sequence_start"` and `triton:"_sequence_end"` receive
+// sequence parameters of stateful model: integer or string id and bool flags.
+//
+// Numeric outputs may be decoded into json.Number fields or slices of them, keeping exact representation.
+//
+// Slices of pointers, e.g. []*float32, receive a freshly allocated value for every element.
+//
+// Outputs of any rank are decoded into Tensor fields, which keep shape of output,
+// and pushed into RingBuffer fields, which keep the last elements of streamed outputs.
+//
+// Fields implementing TritonUnmarshaler decode outputs of any datatype and shape themselves.
+// Fields implementing encoding.BinaryUnmarshaler, e.g. netip.Addr, receive raw contents of output,
+// and slices of them receive raw bytes of every element, or every string of BYTES output.
+// Rows of UINT8 outputs of rank 2 are elements, e.g. [N, 16] output is decoded into N UUIDs.
+// Byte array fields, e.g. [16]byte, are decoded the same way and their length must match.
+//
+// Field may be a sql.Null-style wrapper, e.g. sql.NullFloat64 or struct{ Value float32; Valid bool }.
+// Value is decoded as usual and Valid reports whether output was present in response.
 func Unmarshal[T TritonModelInferResponseOutputs](inferResponse TritonModelInferResponse[T], v any) error {
+	return UnmarshalWithOptions(inferResponse, v, Options{})
+}
+
+// UnmarshalWithOptions is the same as Unmarshal, but decoding is configured with opts.
+func UnmarshalWithOptions[T TritonModelInferResponseOutputs](
+	inferResponse TritonModelInferResponse[T],
+	v any,
+	opts Options,
+) error {
 	rv := reflect.ValueOf(v)
-	if rv.Kind() != reflect.Pointer || rv.IsNil() {
-		return errors.New("v must be pointer")
+	if err := checkDestination(rv); err != nil {
+		return err
 	}
 
 	if rv.Elem().Kind() != reflect.Struct {
-		return errors.New("v must be struct")
+		return unmarshalSingle(inferResponse, rv, &opts)
 	}
 
-	if err := unmarshal(inferResponse, rv); err != nil {
+	if err := unmarshal(inferResponse, rv, &opts); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func unmarshal[T TritonModelInferResponseOutputs](inferResponse TritonModelInferResponse[T], rv reflect.Value) error {
+// checkDestination returns error if rv isn't a non-nil pointer to value of kind outputs can be decoded into.
+func checkDestination(rv reflect.Value) error {
+	if rv.Kind() != reflect.Pointer {
+		return fmt.Errorf("v must be pointer, got %s", rv.Kind())
+	}
+
+	if rv.IsNil() {
+		return errors.New("v must be non-nil pointer")
+	}
+
+	switch k := rv.Elem().Kind(); k { //nolint:exhaustive // only unsupported kinds are matched.
+	case reflect.Map, reflect.Chan, reflect.Func, reflect.Interface, reflect.Pointer, reflect.UnsafePointer,
+		reflect.Complex64, reflect.Complex128, reflect.Uintptr:
+		return fmt.Errorf("unsupported destination kind: %s", k)
+	default:
+		return nil
+	}
+}
+
+// UnmarshalType allocates new value of type t, decodes inferResponse into it and returns it.
+// If t is a pointer type, pointer to decoded value is returned.
+func UnmarshalType[T TritonModelInferResponseOutputs](inferResponse TritonModelInferResponse[T], t reflect.Type) (any, error) {
+	if t == nil {
+		return nil, errors.New("t must not be nil")
+	}
+
+	isPtr := t.Kind() == reflect.Pointer
+	if isPtr {
+		t = t.Elem()
+	}
+
+	rv := reflect.New(t)
+	if err := Unmarshal(inferResponse, rv.Interface()); err != nil {
+		return nil, err
+	}
+
+	if isPtr {
+		return rv.Interface(), nil
+	}
+
+	return rv.Elem().Interface(), nil
+}
+
+func unmarshal[T TritonModelInferResponseOutputs](
+	inferResponse TritonModelInferResponse[T],
+	rv reflect.Value,
+	opts *Options,
+) error {
+	return decodeFields(inferResponse, newFieldSet(rv, opts), opts)
+}
+
+// fieldSet is a destination struct with fields resolved by output name.
+type fieldSet struct {
+	rv        reflect.Value
+	fields    map[string]reflect.Value
+	tagOpts   map[string]tagOptions
+	params    map[string]reflect.Value
+	shapes    map[string]reflect.Value
+	valid     map[string]reflect.Value
+	concat    []concatField
+	sparse    []sparseField
+	ragged    []raggedField
+	zip       []zipField
+	remaining reflect.Value
+	required  []string
+}
+
+func newFieldSet(rv reflect.Value, opts *Options) *fieldSet {
+	m := getTagFieldMap(rv, opts)
+
+	return &fieldSet{
+		rv:        rv,
+		fields:    m,
+		tagOpts:   getTagOptionsMap(rv, opts),
+		params:    getOptionFieldMap(rv, tagParameters, opts),
+		shapes:    getOptionFieldMap(rv, tagShape, opts),
+		valid:     unwrapNullable(m),
+		concat:    getConcatFields(rv, opts),
+		sparse:    getSparseFields(rv, opts),
+		ragged:    getRaggedFields(rv, opts),
+		zip:       getZipFields(rv, opts),
+		remaining: getRemainingField(rv, opts),
+		required:  getRequiredOutputs(rv, opts),
+	}
+}
+
+func decodeFields[T TritonModelInferResponseOutputs](
+	inferResponse TritonModelInferResponse[T],
+	fs *fieldSet,
+	opts *Options,
+) error {
+	if err := responseError(inferResponse); err != nil {
+		return err
+	}
+
 	outputs := inferResponse.GetOutputs()
 	rawBytes := inferResponse.GetRawOutputContents()
-	m := getTagFieldMap(rv)
+	matched := make(map[string]bool, len(outputs))
+	queued := make(map[string]int)
+	var batch batchSize
+	var jobs []decodeJob
+
+	rawBytes, err := joinChunks(outputs, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	order, err := detectByteOrder(outputs, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	if order != nil {
+		if opts.Trace != nil {
+			opts.tracef("detected %s byte order", order)
+		}
+
+		// opts of Binding are reused, so detected order applies to copy.
+		detected := *opts
+		detected.ByteOrder, detected.ByteOrderFor = order, nil
+		opts = &detected
+	}
+
+	if opts.Offsets != nil {
+		fillOffsets(opts.Offsets, outputs, rawBytes)
+	}
+
+	opts.Stats.reset()
+	defer func() { opts.Stats.finish(len(outputs)) }()
+
+	if opts.PositionalFallback {
+		// fields of Binding are reused, so positional matches of this response apply to copy.
+		matches := *fs
+		matches.fields = maps.Clone(fs.fields)
+		matches.tagOpts = maps.Clone(fs.tagOpts)
+		matches.valid = maps.Clone(fs.valid)
+		fs = &matches
+	}
+
+	for _, v := range fs.valid {
+		// outputs of previous response must not remain valid.
+		v.SetBool(false)
+	}
+
+	if r := fs.remaining; r.IsValid() && r.Kind() == reflect.Map && !r.IsNil() {
+		// outputs of previous response must not remain.
+		r.Clear()
+	}
 
 	for i, o := range outputs {
-		if _, ok := m[o.GetName()]; !ok {
+		if opts.Trace != nil {
+			opts.tracef("output %s: datatype %s, shape %v", o.GetName(), o.GetDatatype(), o.GetShape())
+		}
+
+		if f, ok := fs.params[o.GetName()]; ok {
+			if opts.Trace != nil {
+				opts.tracef("output %s: parameters into field of type %s", o.GetName(), f.Type())
+			}
+
+			if err := setParameters(f, o); err != nil {
+				return err
+			}
+		}
+
+		if f, ok := fs.shapes[o.GetName()]; ok {
+			if err := setShape(f, o); err != nil {
+				return err
+			}
+		}
+
+		if !lookupField(fs, i, outputs, opts) {
+			if fs.remaining.IsValid() && !fs.referenced(o.GetName(), opts) {
+				if err := decodeRemaining(fs.remaining, o, i, rawBytes, opts); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if opts.Trace != nil {
+				opts.tracef("output %s: no matching field", o.GetName())
+			}
+
 			continue
 		}
 
-		if err := parse(m, o, rawBytes[i]); err != nil {
+		matched[o.GetName()] = true
+
+		if !opts.selected(o.GetName()) {
+			if opts.Trace != nil {
+				opts.tracef("output %s: skipped", o.GetName())
+			}
+
+			opts.Stats.skip(1)
+
+			continue
+		}
+
+		if opts.Trace != nil {
+			opts.tracef("output %s: matched field of type %s", o.GetName(), fs.fields[o.GetName()].Type())
+		}
+
+		if opts.ValidateBatchConsistency {
+			if err := batch.check(o, opts.BatchAxis); err != nil {
+				return err
+			}
+		}
+
+		raw, err := rawContents(o, i, rawBytes, opts)
+		if err != nil {
 			return err
 		}
+
+		if absentContents(o, raw) {
+			if slices.Contains(fs.required, o.GetName()) {
+				return fmt.Errorf("required output %s has no raw contents", o.GetName())
+			}
+
+			if opts.Trace != nil {
+				opts.tracef("output %s: no raw contents, field is zeroed", o.GetName())
+			}
+
+			zeroField(fs.fields[o.GetName()])
+
+			if v, ok := fs.valid[o.GetName()]; ok {
+				v.SetBool(false)
+			}
+
+			continue
+		}
+
+		opts.Stats.add(o, raw)
+
+		if opts.Parallelism > 1 {
+			if j, ok := queued[o.GetName()]; ok {
+				// the last of outputs with the same name wins, as in sequential decoding.
+				jobs[j] = decodeJob{o, raw}
+			} else {
+				queued[o.GetName()] = len(jobs)
+				jobs = append(jobs, decodeJob{o, raw})
+			}
+
+			continue
+		}
+
+		if err := decodeField(fs, o, raw, opts); err != nil {
+			return err
+		}
+	}
+
+	if len(jobs) != 0 {
+		if err := decodeParallel(fs, jobs, opts); err != nil {
+			return err
+		}
+	}
+
+	info, err := setModelInfo(inferResponse, fs.fields)
+	if err != nil {
+		return err
+	}
+
+	seq, err := setSequenceInfo(inferResponse, fs.fields)
+	if err != nil {
+		return err
+	}
+
+	info = append(info, seq...)
+
+	for _, name := range info {
+		matched[name] = true
+	}
+
+	for _, cf := range fs.concat {
+		if !opts.selected(cf.name) {
+			continue
+		}
+
+		ok, err := decodeConcat(cf, outputs, rawBytes, opts)
+		if err != nil {
+			return err
+		}
+
+		matched[cf.name] = matched[cf.name] || ok
+	}
+
+	for _, sf := range fs.sparse {
+		if !opts.selected(sf.name) {
+			continue
+		}
+
+		ok, err := decodeSparse(sf, outputs, rawBytes, opts)
+		if err != nil {
+			return err
+		}
+
+		matched[sf.name] = matched[sf.name] || ok
+	}
+
+	for _, rf := range fs.ragged {
+		if !opts.selected(rf.name) {
+			continue
+		}
+
+		ok, err := decodeRagged(rf, outputs, rawBytes, opts)
+		if err != nil {
+			return err
+		}
+
+		matched[rf.name] = matched[rf.name] || ok
+	}
+
+	for _, zf := range fs.zip {
+		if err := decodeZip(zf, outputs, rawBytes, opts); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range fs.required {
+		if !matched[name] {
+			return fmt.Errorf("required output %s is missing", name)
+		}
 	}
 
 	return nil
 }
 
-func parse(fieldMap map[string]reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte) error {
-	var err error
-	shape := output.GetShape()
+// absentContents reports whether raw contents of output with elements are nil, rather than empty.
+func absentContents(output TritonModelInferResponseOutputs, raw []byte) bool {
+	if raw != nil {
+		return false
+	}
 
-	if len(shape) > 2 {
-		return errors.New("len(shape) > 2 is not yet supported")
+	// product of dimensions may overflow, so output has elements if none of them is zero or negative.
+	for _, dim := range output.GetShape() {
+		if dim <= 0 {
+			return false
+		}
 	}
 
-	switch {
-	case len(shape) == 1:
-		err = parseToValue(fieldMap, output, rawBytes)
-	case shape[0] == 1 && len(shape) == 2:
-		err = parseToArray(fieldMap, output, rawBytes)
-	case len(shape) == 2 && shape[0] > 1:
-		err = parseToMultidimenshionalArray(fieldMap, output, rawBytes)
-	default:
-		err = fmt.Errorf("unknown shape: %v", shape)
+	return true
+}
+
+// decodeField decompresses raw contents of output and decodes them into its field.
+func decodeField(fs *fieldSet, output TritonModelInferResponseOutputs, raw []byte, opts *Options) (err error) {
+	if opts.PerOutputTimeout > 0 {
+		// decoding can't be interrupted without leaving field half-written, so budget is checked afterwards.
+		defer func(start time.Time) {
+			if elapsed := time.Since(start); err == nil && elapsed > opts.PerOutputTimeout {
+				err = fmt.Errorf("output %s: decoding took %s, exceeding %s: %w",
+					output.GetName(), elapsed, opts.PerOutputTimeout, context.DeadlineExceeded)
+			}
+		}(time.Now())
+	}
+
+	b, err := decompress(raw, opts)
+	if err != nil {
+		return fmt.Errorf("output %s: %w", output.GetName(), err)
+	}
+
+	if opts.Trace != nil {
+		opts.tracef("output %s: %d bytes, %d after decompression", output.GetName(), len(raw), len(b))
+	}
+
+	tagOpts := fs.tagOpts[output.GetName()]
+	if err := decodeOutput(fs.fields, output, b, opts, tagOpts); err != nil {
+		return err
+	}
+
+	if sentinel, ok := tagOpts.Get(tagMissing); ok && fs.fields[output.GetName()].Kind() != reflect.Slice {
+		missing, err := clearMissing(fs.fields[output.GetName()], output, sentinel)
+		if err != nil || missing {
+			return err
+		}
+	}
+
+	if v, ok := fs.valid[output.GetName()]; ok {
+		v.SetBool(true)
+	}
+
+	return nil
+}
+
+// unmarshalSingle decodes the only output of response into non-struct value rv points to.
+func unmarshalSingle[T TritonModelInferResponseOutputs](
+	inferResponse TritonModelInferResponse[T],
+	rv reflect.Value,
+	opts *Options,
+) error {
+	if err := responseError(inferResponse); err != nil {
+		return err
+	}
+
+	outputs := inferResponse.GetOutputs()
+	rawBytes, err := joinChunks(outputs, inferResponse.GetRawOutputContents(), opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.Offsets != nil {
+		fillOffsets(opts.Offsets, outputs, rawBytes)
+	}
+
+	idx := -1
+	for i, o := range outputs {
+		if !opts.selected(o.GetName()) {
+			continue
+		}
+
+		if idx != -1 {
+			return fmt.Errorf("v of type %s requires exactly one output, got outputs %s and %s",
+				rv.Elem().Type(), outputs[idx].GetName(), o.GetName())
+		}
+
+		idx = i
+	}
+
+	if idx == -1 {
+		return fmt.Errorf("v of type %s requires exactly one output, got none", rv.Elem().Type())
+	}
+
+	o := outputs[idx]
+	raw, err := rawContents(o, idx, rawBytes, opts)
+	if err != nil {
+		return err
+	}
+
+	opts.Stats.reset()
+	opts.Stats.add(o, raw)
+	opts.Stats.skip(len(outputs) - 1)
+
+	b, err := decompress(raw, opts)
+	if err != nil {
+		return fmt.Errorf("output %s: %w", o.GetName(), err)
+	}
+
+	return decodeOutput(map[string]reflect.Value{o.GetName(): rv.Elem()}, o, b, opts, "")
+}
+
+// fillOffsets stores start and end of raw contents of every output within their concatenation.
+func fillOffsets[T TritonModelInferResponseOutputs](offsets map[string][2]int, outputs []T, rawBytes [][]byte) {
+	start := 0
+	for i, o := range outputs {
+		if i >= len(rawBytes) {
+			return
+		}
+
+		end := start + len(rawBytes[i])
+		offsets[o.GetName()] = [2]int{start, end}
+		start = end
+	}
+}
+
+func parse(fieldMap map[string]reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	if err := opts.checkLimits(output, rawBytes); err != nil {
+		return err
+	}
+
+	if u, ok := getUnmarshaler(fieldMap[output.GetName()]); ok {
+		if opts.Trace != nil {
+			opts.tracef("output %s: decoding with TritonUnmarshaler", output.GetName())
+		}
+
+		return unmarshalCustom(u, output, rawBytes)
+	}
+
+	if u, ok := getBinaryUnmarshaler(fieldMap[output.GetName()]); ok {
+		if opts.Trace != nil {
+			opts.tracef("output %s: decoding with encoding.BinaryUnmarshaler", output.GetName())
+		}
+
+		if err := u.UnmarshalBinary(rawBytes); err != nil {
+			return fmt.Errorf("output %s: %w", output.GetName(), err)
+		}
+
+		return nil
+	}
+
+	if f := fieldMap[output.GetName()]; isAtomic(f) {
+		if opts.Trace != nil {
+			opts.tracef("output %s: storing into %s", output.GetName(), f.Type())
+		}
+
+		return unmarshalAtomic(f, output, rawBytes, opts)
+	}
+
+	if f := fieldMap[output.GetName()]; isByteArray(f.Type()) {
+		if opts.Trace != nil {
+			opts.tracef("output %s: copying raw contents into %s", output.GetName(), f.Type())
+		}
+
+		b := rawBytes
+		if output.GetDatatype() == STRING {
+			strs, err := stringBytesToArray(rawBytes, 1, opts.byteOrder(output.GetName()), opts)
+			if err != nil {
+				return fmt.Errorf("output %s: %w", output.GetName(), err)
+			}
+
+			b = []byte(strs[0])
+		}
+
+		if err := setByteArray(f, b); err != nil {
+			return fmt.Errorf("output %s: %w", output.GetName(), err)
+		}
+
+		return nil
+	}
+
+	if isBinaryUnmarshalerSlice(fieldMap[output.GetName()]) {
+		if opts.Trace != nil {
+			opts.tracef("output %s: decoding elements from raw bytes", output.GetName())
+		}
+
+		return unmarshalBinarySlice(fieldMap[output.GetName()], output, rawBytes, opts)
+	}
+
+	if r, ok := getRing(fieldMap[output.GetName()]); ok {
+		if opts.Trace != nil {
+			opts.tracef("output %s: pushing %s into ring buffer", output.GetName(), output.GetDatatype())
+		}
+
+		return unmarshalRing(r, output, rawBytes, opts)
+	}
+
+	if output.GetDatatype() == STRING && isBytesSlice(fieldMap[output.GetName()]) {
+		if opts.Trace != nil {
+			opts.tracef("output %s: decoding %s elements as byte slices", output.GetName(), output.GetDatatype())
+		}
+
+		return unmarshalBytesSlice(fieldMap[output.GetName()], output, rawBytes, opts)
+	}
+
+	if isBoxedSlice(fieldMap[output.GetName()].Type()) {
+		if opts.Trace != nil {
+			opts.tracef("output %s: boxing %s elements", output.GetName(), output.GetDatatype())
+		}
+
+		return unmarshalBoxed(fieldMap[output.GetName()], output, rawBytes, opts)
+	}
+
+	if t, ok := getTensor(fieldMap[output.GetName()]); ok {
+		if opts.Trace != nil {
+			opts.tracef("output %s: decoding %s as tensor", output.GetName(), output.GetDatatype())
+		}
+
+		return unmarshalTensor(t, output, rawBytes, opts)
+	}
+
+	if t, ok := pointedType(fieldMap[output.GetName()].Type()); ok {
+		if opts.Trace != nil {
+			opts.tracef("output %s: decoding %s into pointer elements", output.GetName(), output.GetDatatype())
+		}
+
+		return unmarshalPointers(fieldMap[output.GetName()], t, output, rawBytes, opts)
+	}
+
+	if len(output.GetShape()) == 0 {
+		// rank 0 output is a single value, as of shape [1].
+		output = reshaped{output, []int64{1}}
+	}
+
+	kind, err := ClassifyShape(output.GetShape())
+	if err != nil {
+		return err
+	}
+
+	if opts.Trace != nil {
+		opts.tracef("output %s: decoding %s as %s", output.GetName(), output.GetDatatype(), kind)
+	}
+
+	switch kind {
+	case ShapeScalar:
+		isBytes := output.GetDatatype() == STRING && fieldMap[output.GetName()].Type() == bytesType
+		if fieldMap[output.GetName()].Kind() == reflect.Slice && !isBytes {
+			// one-dimensional output is decoded as [1, N] when field is a slice.
+			err = parseToArray(fieldMap, reshaped{output, []int64{1, output.GetShape()[0]}}, rawBytes, opts)
+
+			break
+		}
+
+		err = parseToValue(fieldMap, output, rawBytes, opts)
+	case ShapeVector:
+		err = parseToArray(fieldMap, output, rawBytes, opts)
+	case ShapeMatrix:
+		err = parseToMultidimenshionalArray(fieldMap, output, rawBytes, opts)
+	case ShapeUnknown, ShapeHigherRank:
+		err = fmt.Errorf("unknown shape: %v", output.GetShape())
 	}
 
 	if err != nil {
@@ -92,33 +774,40 @@ func parseToMultidimenshionalArray(
 	fieldMap map[string]reflect.Value,
 	output TritonModelInferResponseOutputs,
 	rawBytes []byte,
+	opts *Options,
 ) error {
 	var err error
 	switch output.GetDatatype() {
 	case BOOL:
-		err = unmarshalMultidimenshionalArray[bool](fieldMap, output, rawBytes)
+		err = unmarshalMultidimenshionalArray[bool](fieldMap, output, rawBytes, opts)
 	case UINT8:
-		err = unmarshalMultidimenshionalArray[uint8](fieldMap, output, rawBytes)
+		err = unmarshalMultidimenshionalArray[uint8](fieldMap, output, rawBytes, opts)
 	case UINT16:
-		err = unmarshalMultidimenshionalArray[uint16](fieldMap, output, rawBytes)
+		err = unmarshalMultidimenshionalArray[uint16](fieldMap, output, rawBytes, opts)
 	case UINT32:
-		err = unmarshalMultidimenshionalArray[uint32](fieldMap, output, rawBytes)
+		err = unmarshalMultidimenshionalArray[uint32](fieldMap, output, rawBytes, opts)
+	case UINT64:
+		err = unmarshalMultidimenshionalArray[uint64](fieldMap, output, rawBytes, opts)
 	case INT8:
-		err = unmarshalMultidimenshionalArray[int8](fieldMap, output, rawBytes)
+		err = unmarshalMultidimenshionalArray[int8](fieldMap, output, rawBytes, opts)
 	case INT16:
-		err = unmarshalMultidimenshionalArray[int16](fieldMap, output, rawBytes)
+		err = unmarshalMultidimenshionalArray[int16](fieldMap, output, rawBytes, opts)
 	case INT32:
-		err = unmarshalMultidimenshionalArray[int32](fieldMap, output, rawBytes)
+		err = unmarshalMultidimenshionalArray[int32](fieldMap, output, rawBytes, opts)
 	case INT64:
-		err = unmarshalMultidimenshionalArray[int64](fieldMap, output, rawBytes)
+		err = unmarshalMultidimenshionalArray[int64](fieldMap, output, rawBytes, opts)
 	case FLOAT16:
 		err = fmt.Errorf("%s not yet supported", FLOAT16)
 	case FLOAT32:
-		err = unmarshalMultidimenshionalArray[float32](fieldMap, output, rawBytes)
+		err = unmarshalMultidimenshionalArray[float32](fieldMap, output, rawBytes, opts)
 	case FLOAT64:
-		err = unmarshalMultidimenshionalArray[float64](fieldMap, output, rawBytes)
+		err = unmarshalMultidimenshionalArray[float64](fieldMap, output, rawBytes, opts)
 	case STRING:
-		err = unmarshalMultidimenshionalStringArray(fieldMap, output, rawBytes)
+		err = unmarshalMultidimenshionalStringArray(fieldMap, output, rawBytes, opts)
+	case INT4:
+		err = unmarshalInt4MultidimenshionalArray[int8](fieldMap, output, rawBytes, opts)
+	case UINT4:
+		err = unmarshalInt4MultidimenshionalArray[uint8](fieldMap, output, rawBytes, opts)
 	default:
 		return fmt.Errorf("unkwnow type: %s", output.GetDatatype())
 	}
@@ -137,34 +826,40 @@ func parseToArray(
 	fieldMap map[string]reflect.Value,
 	output TritonModelInferResponseOutputs,
 	rawBytes []byte,
-	// isArray bool,
+	opts *Options,
 ) error {
 	var err error
 	switch output.GetDatatype() {
 	case BOOL:
-		err = unmarshalArray[bool](fieldMap, output, rawBytes)
+		err = unmarshalArray[bool](fieldMap, output, rawBytes, opts)
 	case UINT8:
-		err = unmarshalArray[uint8](fieldMap, output, rawBytes)
+		err = unmarshalArray[uint8](fieldMap, output, rawBytes, opts)
 	case UINT16:
-		err = unmarshalArray[uint16](fieldMap, output, rawBytes)
+		err = unmarshalArray[uint16](fieldMap, output, rawBytes, opts)
 	case UINT32:
-		err = unmarshalArray[uint32](fieldMap, output, rawBytes)
+		err = unmarshalArray[uint32](fieldMap, output, rawBytes, opts)
+	case UINT64:
+		err = unmarshalArray[uint64](fieldMap, output, rawBytes, opts)
 	case INT8:
-		err = unmarshalArray[int8](fieldMap, output, rawBytes)
+		err = unmarshalArray[int8](fieldMap, output, rawBytes, opts)
 	case INT16:
-		err = unmarshalArray[int16](fieldMap, output, rawBytes)
+		err = unmarshalArray[int16](fieldMap, output, rawBytes, opts)
 	case INT32:
-		err = unmarshalArray[int32](fieldMap, output, rawBytes)
+		err = unmarshalArray[int32](fieldMap, output, rawBytes, opts)
 	case INT64:
-		err = unmarshalArray[int64](fieldMap, output, rawBytes)
+		err = unmarshalArray[int64](fieldMap, output, rawBytes, opts)
 	case FLOAT16:
-		err = fmt.Errorf("%s not yet supported", FLOAT16)
+		err = unmarshalFloat16Array(fieldMap, output, rawBytes, opts)
 	case FLOAT32:
-		err = unmarshalArray[float32](fieldMap, output, rawBytes)
+		err = unmarshalArray[float32](fieldMap, output, rawBytes, opts)
 	case FLOAT64:
-		err = unmarshalArray[float64](fieldMap, output, rawBytes)
+		err = unmarshalArray[float64](fieldMap, output, rawBytes, opts)
 	case STRING:
-		err = unmarshalStringArray(fieldMap, output, rawBytes)
+		err = unmarshalStringArray(fieldMap, output, rawBytes, opts)
+	case INT4:
+		err = unmarshalInt4Array[int8](fieldMap, output, rawBytes, opts)
+	case UINT4:
+		err = unmarshalInt4Array[uint8](fieldMap, output, rawBytes, opts)
 	default:
 		return fmt.Errorf("unkwnow type: %s", output.GetDatatype())
 	}
@@ -183,33 +878,40 @@ func parseToValue(
 	fieldMap map[string]reflect.Value,
 	output TritonModelInferResponseOutputs,
 	rawBytes []byte,
+	opts *Options,
 ) error {
 	var err error
 	switch output.GetDatatype() {
 	case BOOL:
-		err = unmarshalValue[bool](fieldMap, output, rawBytes)
+		err = unmarshalValue[bool](fieldMap, output, rawBytes, opts)
 	case UINT8:
-		err = unmarshalValue[uint8](fieldMap, output, rawBytes)
+		err = unmarshalValue[uint8](fieldMap, output, rawBytes, opts)
 	case UINT16:
-		err = unmarshalValue[uint16](fieldMap, output, rawBytes)
+		err = unmarshalValue[uint16](fieldMap, output, rawBytes, opts)
 	case UINT32:
-		err = unmarshalValue[uint32](fieldMap, output, rawBytes)
+		err = unmarshalValue[uint32](fieldMap, output, rawBytes, opts)
+	case UINT64:
+		err = unmarshalValue[uint64](fieldMap, output, rawBytes, opts)
 	case INT8:
-		err = unmarshalValue[int8](fieldMap, output, rawBytes)
+		err = unmarshalValue[int8](fieldMap, output, rawBytes, opts)
 	case INT16:
-		err = unmarshalValue[int16](fieldMap, output, rawBytes)
+		err = unmarshalValue[int16](fieldMap, output, rawBytes, opts)
 	case INT32:
-		err = unmarshalValue[int32](fieldMap, output, rawBytes)
+		err = unmarshalValue[int32](fieldMap, output, rawBytes, opts)
 	case INT64:
-		err = unmarshalValue[int64](fieldMap, output, rawBytes)
+		err = unmarshalValue[int64](fieldMap, output, rawBytes, opts)
 	case FLOAT16:
-		err = fmt.Errorf("%s not yet supported", FLOAT16)
+		err = unmarshalFloat16Value(fieldMap, output, rawBytes, opts)
 	case FLOAT32:
-		err = unmarshalValue[float32](fieldMap, output, rawBytes)
+		err = unmarshalValue[float32](fieldMap, output, rawBytes, opts)
 	case FLOAT64:
-		err = unmarshalValue[float64](fieldMap, output, rawBytes)
+		err = unmarshalValue[float64](fieldMap, output, rawBytes, opts)
 	case STRING:
-		err = unmarshalStringValue(fieldMap, output, rawBytes)
+		err = unmarshalStringValue(fieldMap, output, rawBytes, opts)
+	case INT4:
+		err = unmarshalInt4Value[int8](fieldMap, output, rawBytes, opts)
+	case UINT4:
+		err = unmarshalInt4Value[uint8](fieldMap, output, rawBytes, opts)
 	default:
 		return fmt.Errorf("unkwnow type: %s", output.GetDatatype())
 	}
@@ -225,30 +927,38 @@ func unmarshalStringValue(
 	fieldMap map[string]reflect.Value,
 	resp TritonModelInferResponseOutputs,
 	rawBytes []byte,
+	opts *Options,
 ) error {
-	var strLen uint32
-
-	if len(rawBytes) == 0 {
-		return nil
-	}
-
-	buf := bytes.NewBuffer(rawBytes)
-	if err := binary.Read(buf, binary.LittleEndian, &strLen); err != nil {
-		return fmt.Errorf("binary read failed: %w", err)
+	field := fieldMap[resp.GetName()]
+	isBytes := field.IsValid() && field.Type() == bytesType
+	if !isBytes {
+		if err := checkType(field, reflect.TypeFor[string](), opts); err != nil {
+			return err
+		}
 	}
 
-	var val string
+	// empty contents are an empty string, so output is distinguishable from missing one.
+	start, end := 0, 0
+	if len(rawBytes) != 0 {
+		var err error
+		if start, end, err = stringBounds(rawBytes, 0, opts.byteOrder(resp.GetName()), opts.StringLengthPrefix); err != nil {
+			return err
+		}
 
-	if fieldMap[resp.GetName()].Type() != reflect.TypeOf(val) {
-		return fmt.Errorf("types doesn't match exp: %T got: %s", val, fieldMap[resp.GetName()].Type().String())
+		if end != len(rawBytes) {
+			return fmt.Errorf("output %s: %w", resp.GetName(), trailingBytesError(len(rawBytes)-end, 1))
+		}
 	}
 
-	if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
-		return fmt.Errorf("binary read failed: %w", err)
+	// binary contents are copied without conversion to string.
+	if isBytes {
+		field.SetBytes(append([]byte{}, rawBytes[start:end]...))
+
+		return nil
 	}
 
 	if v, ok := fieldMap[resp.GetName()]; ok {
-		v.Set(reflect.ValueOf(val))
+		return setValue(v, reflect.ValueOf(string(rawBytes[start:end])), resp.GetName(), opts)
 	}
 
 	return nil
@@ -258,19 +968,24 @@ func unmarshalValue[T any](
 	fieldMap map[string]reflect.Value,
 	resp TritonModelInferResponseOutputs,
 	rawBytes []byte,
+	opts *Options,
 ) error {
 	var val T
-	if fieldMap[resp.GetName()].Type() != reflect.TypeOf(val) {
-		return fmt.Errorf("types doesn't match exp: %T got: %s", val, fieldMap[resp.GetName()].Type().String())
+	if err := checkType(fieldMap[resp.GetName()], reflect.TypeOf(val), opts); err != nil {
+		return err
 	}
 
 	buf := bytes.NewBuffer(rawBytes)
-	if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
+	if err := binary.Read(buf, opts.byteOrder(resp.GetName()), &val); err != nil {
 		return fmt.Errorf("binary read failed: %w", err)
 	}
 
+	if buf.Len() != 0 {
+		return fmt.Errorf("output %s: %w", resp.GetName(), trailingBytesError(buf.Len(), 1))
+	}
+
 	if v, ok := fieldMap[resp.GetName()]; ok {
-		v.Set(reflect.ValueOf(val))
+		return setValue(v, reflect.ValueOf(val), resp.GetName(), opts)
 	}
 
 	return nil
@@ -280,26 +995,63 @@ func unmarshalMultidimenshionalArray[T any](
 	fieldMap map[string]reflect.Value,
 	resp TritonModelInferResponseOutputs,
 	rawBytes []byte,
+	opts *Options,
 ) error {
 	numOfArrays := resp.GetShape()[0]
 	arrLen := resp.GetShape()[1]
-	arr := make([][]T, 0, numOfArrays)
-	if fieldMap[resp.GetName()].Type() != reflect.TypeOf(arr) {
-		return fmt.Errorf("types doesn't match exp: %T got: %s", arr, fieldMap[resp.GetName()].Type().String())
+	arr := make([][]T, numOfArrays)
+	if err := checkType(fieldMap[resp.GetName()], reflect.TypeOf(arr), opts); err != nil {
+		return err
+	}
+
+	for i := range arr {
+		arr[i] = make([]T, arrLen)
 	}
 
 	buf := bytes.NewReader(rawBytes)
-	for i := 0; i < int(numOfArrays); i++ {
-		for j := 0; j < int(arrLen); j++ {
-			err := binary.Read(buf, binary.LittleEndian, &arr[i][j])
-			if err != nil {
-				return fmt.Errorf("binary read failed: %w", err)
-			}
+	order := opts.byteOrder(resp.GetName())
+	err := walkMultidimenshional(int(numOfArrays), int(arrLen), opts, func(i, j int) error {
+		if err := binary.Read(buf, order, &arr[i][j]); err != nil {
+			return fmt.Errorf("binary read failed: %w", err)
 		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if buf.Len() != 0 {
+		return fmt.Errorf("output %s: %w", resp.GetName(), trailingBytesError(buf.Len(), len(arr)*int(arrLen)))
 	}
 
 	if v, ok := fieldMap[resp.GetName()]; ok {
-		v.Set(reflect.ValueOf(arr))
+		return setValue(v, reflect.ValueOf(arr), resp.GetName(), opts)
+	}
+
+	return nil
+}
+
+// walkMultidimenshional calls fn for every element in memory order of raw contents.
+func walkMultidimenshional(numOfArrays, arrLen int, opts *Options, fn func(i, j int) error) error {
+	if opts.ColumnMajor {
+		for j := 0; j < arrLen; j++ {
+			for i := 0; i < numOfArrays; i++ {
+				if err := fn(i, j); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	for i := 0; i < numOfArrays; i++ {
+		for j := 0; j < arrLen; j++ {
+			if err := fn(i, j); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -309,12 +1061,13 @@ func unmarshalMultidimenshionalStringArray(
 	fieldMap map[string]reflect.Value,
 	resp TritonModelInferResponseOutputs,
 	rawBytes []byte,
+	opts *Options,
 ) error {
 	numOfArrays := resp.GetShape()[0]
 	arrLen := resp.GetShape()[1]
 	arr := make([][]string, numOfArrays)
-	if fieldMap[resp.GetName()].Type() != reflect.TypeOf(arr) {
-		return fmt.Errorf("types doesn't match exp: %T got: %s", arr, fieldMap[resp.GetName()].Type().String())
+	if err := checkType(fieldMap[resp.GetName()], reflect.TypeOf(arr), opts); err != nil {
+		return err
 	}
 
 	for i := range arr {
@@ -322,32 +1075,31 @@ func unmarshalMultidimenshionalStringArray(
 	}
 
 	if len(rawBytes) == 0 {
+		if v, ok := fieldMap[resp.GetName()]; ok {
+			return setValue(v, reflect.ValueOf(arr), resp.GetName(), opts)
+		}
+
 		return nil
 	}
 
 	prev := 0
-	for i := 0; i < int(numOfArrays); i++ {
-		for j := 0; j < int(arrLen); j++ {
-			buf := bytes.NewReader(rawBytes[prev : prev+4])
-			var strLen uint32
-			if err := binary.Read(buf, binary.LittleEndian, &strLen); err != nil {
-				return fmt.Errorf("binary read failed: %w", err)
-			}
+	r := newStringReader(rawBytes, opts.byteOrder(resp.GetName()), opts)
+	err := walkMultidimenshional(int(numOfArrays), int(arrLen), opts, func(i, j int) error {
+		var err error
+		arr[i][j], prev, err = r.read(prev)
 
-			buf = bytes.NewReader(rawBytes[prev+4 : prev+4+int(strLen)])
-			tmp := make([]byte, strLen)
-			err := binary.Read(buf, binary.LittleEndian, &tmp)
-			if err != nil {
-				return fmt.Errorf("binary read failed: %w", err)
-			}
+		return err
+	})
+	if err != nil {
+		return err
+	}
 
-			arr[i][j] = string(tmp)
-			prev += 4 + int(strLen)
-		}
+	if prev != len(rawBytes) {
+		return fmt.Errorf("output %s: %w", resp.GetName(), trailingBytesError(len(rawBytes)-prev, len(arr)*int(arrLen)))
 	}
 
 	if v, ok := fieldMap[resp.GetName()]; ok {
-		v.Set(reflect.ValueOf(arr))
+		return setValue(v, reflect.ValueOf(arr), resp.GetName(), opts)
 	}
 
 	return nil
@@ -357,20 +1109,21 @@ func unmarshalArray[T any](
 	fieldMap map[string]reflect.Value,
 	resp TritonModelInferResponseOutputs,
 	rawBytes []byte,
+	opts *Options,
 ) error {
 	arrLen := resp.GetShape()[1]
 	arr := make([]T, 0, arrLen)
-	if fieldMap[resp.GetName()].Type() != reflect.TypeOf(arr) {
-		return fmt.Errorf("types doesn't match exp: %T got: %s", arr, fieldMap[resp.GetName()].Type().String())
+	if err := checkType(fieldMap[resp.GetName()], reflect.TypeOf(arr), opts); err != nil {
+		return err
 	}
 
-	arr, err := bytesToArray(rawBytes, arr)
+	arr, err := bytesToArray(rawBytes, arr, opts.byteOrder(resp.GetName()))
 	if err != nil {
-		return err
+		return fmt.Errorf("output %s: %w", resp.GetName(), err)
 	}
 
 	if v, ok := fieldMap[resp.GetName()]; ok {
-		v.Set(reflect.ValueOf(arr))
+		return setValue(v, reflect.ValueOf(arr), resp.GetName(), opts)
 	}
 
 	return nil
@@ -380,64 +1133,116 @@ func unmarshalStringArray(
 	fieldMap map[string]reflect.Value,
 	resp TritonModelInferResponseOutputs,
 	rawBytes []byte,
+	opts *Options,
 ) error {
-	arrLen := len(resp.GetShape())
+	arrLen := int(resp.GetShape()[1])
 	var arr []string
-	if fieldMap[resp.GetName()].Type() != reflect.TypeOf(arr) && fieldMap[resp.GetName()].Type() != reflect.TypeOf([][]string{}) {
-		return fmt.Errorf("types doesn't match exp: %T got: %s", arr, fieldMap[resp.GetName()].Type().String())
-	}
-
-	if len(rawBytes) == 0 {
-		return nil
+	// [1, N] output may also be decoded as a single row of [][]string.
+	nested := checkType(fieldMap[resp.GetName()], reflect.TypeOf([][]string{}), opts) == nil
+	if !nested {
+		if err := checkType(fieldMap[resp.GetName()], reflect.TypeOf(arr), opts); err != nil {
+			return err
+		}
 	}
 
-	arr, err := stringBytesToArray(rawBytes, arrLen)
-	if err != nil {
-		return err
+	arr = make([]string, arrLen)
+	if len(rawBytes) != 0 {
+		var err error
+		if arr, err = stringBytesToArray(rawBytes, arrLen, opts.byteOrder(resp.GetName()), opts); err != nil {
+			return fmt.Errorf("output %s: %w", resp.GetName(), err)
+		}
 	}
 
 	if v, ok := fieldMap[resp.GetName()]; ok {
-		switch {
-		case fieldMap[resp.GetName()].Type() == reflect.TypeOf([][]string{}):
-			v.Set(reflect.ValueOf([][]string{arr}))
-		case fieldMap[resp.GetName()].Type() == reflect.TypeOf(arr):
-			v.Set(reflect.ValueOf(arr))
+		if nested {
+			return setValue(v, reflect.ValueOf([][]string{arr}), resp.GetName(), opts)
 		}
+
+		return setValue(v, reflect.ValueOf(arr), resp.GetName(), opts)
 	}
 
 	return nil
 }
 
-func stringBytesToArray(b []byte, size int) ([]string, error) {
+func stringBytesToArray(b []byte, size int, order binary.ByteOrder, opts *Options) ([]string, error) {
+	r := newStringReader(b, order, opts)
 	prev := 0
 	arr := make([]string, size)
 	for i := 0; i < size; i++ {
-		buf := bytes.NewReader(b[prev : prev+4])
-		var strLen uint32
-		if err := binary.Read(buf, binary.LittleEndian, &strLen); err != nil {
-			return nil, fmt.Errorf("binary read failed: %w", err)
+		var err error
+		arr[i], prev, err = r.read(prev)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		buf = bytes.NewReader(b[prev+4 : prev+4+int(strLen)])
-		t := make([]byte, strLen)
-		if err := binary.Read(buf, binary.LittleEndian, &t); err != nil {
-			return nil, fmt.Errorf("binary read failed: %w", err)
-		}
+	if prev != len(b) {
+		return nil, trailingBytesError(len(b)-prev, size)
+	}
 
-		prev += 4 + int(strLen)
+	return arr, nil
+}
 
-		arr[i] = string(t)
+// readString reads string with length prefix of given width, 4 if zero, from b at offset.
+// It returns the string and offset of the next one.
+func readString(b []byte, offset int, order binary.ByteOrder, prefix int) (string, int, error) {
+	start, end, err := stringBounds(b, offset, order, prefix)
+	if err != nil {
+		return "", 0, err
 	}
 
-	return arr, nil
+	return string(b[start:end]), end, nil
+}
+
+// stringBounds returns bounds of string with length prefix of given width, 4 if zero, at offset of b.
+func stringBounds(b []byte, offset int, order binary.ByteOrder, prefix int) (int, int, error) {
+	if prefix == 0 {
+		prefix = 4
+	}
+
+	if prefix != 4 && prefix != 8 {
+		return 0, 0, fmt.Errorf("unsupported string length prefix width: %d", prefix)
+	}
+
+	if len(b)-offset < prefix {
+		return 0, 0, fmt.Errorf("binary read failed: string length at offset %d: %w", offset, io.ErrUnexpectedEOF)
+	}
+
+	var strLen uint64
+	if prefix == 8 {
+		strLen = order.Uint64(b[offset:])
+	} else {
+		strLen = uint64(order.Uint32(b[offset:]))
+	}
+
+	offset += prefix
+
+	if uint64(len(b)-offset) < strLen {
+		return 0, 0, fmt.Errorf("binary read failed: string of length %d at offset %d: %w", strLen, offset, io.ErrUnexpectedEOF)
+	}
+
+	return offset, offset + int(strLen), nil
 }
 
-func bytesToArray[T any](b []byte, arr []T) ([]T, error) {
+func bytesToArray[T any](b []byte, arr []T, order binary.ByteOrder) ([]T, error) {
 	buf := bytes.NewReader(b)
 	var t T
-	size := reflect.TypeOf(t).Size()
+	size := unsafe.Sizeof(t)
+	if len(b)%int(size) != 0 {
+		return nil, fmt.Errorf("raw contents length %d is not a multiple of element size %d", len(b), size)
+	}
+
+	// floats are decoded in bulk, as binary.Read of every element dominates decoding of large embeddings.
+	// Little-endian contents are copied as is on little-endian hosts.
+	switch a := any(arr).(type) {
+	case []float32:
+		return as[[]T](float32sFromBytes(b, a, order)), nil
+	case []float64:
+		return as[[]T](float64sFromBytes(b, a, order)), nil
+	}
+
 	for i := 0; i < len(b); i += int(size) {
-		err := binary.Read(buf, binary.LittleEndian, &t)
+		err := binary.Read(buf, order, &t)
 		if err != nil {
 			return nil, fmt.Errorf("binary read failed: %w", err)
 		}
@@ -448,14 +1253,161 @@ func bytesToArray[T any](b []byte, arr []T) ([]T, error) {
 	return arr, nil
 }
 
-func getTagFieldMap(rv reflect.Value) map[string]reflect.Value {
+func float32sFromBytes(b []byte, arr []float32, order binary.ByteOrder) []float32 {
+	if order == binary.LittleEndian && hostLittleEndian {
+		return appendReinterpreted(b, arr)
+	}
+
+	arr = slices.Grow(arr, len(b)/4)
+	for i := 0; i+4 <= len(b); i += 4 {
+		arr = append(arr, math.Float32frombits(order.Uint32(b[i:])))
+	}
+
+	return arr
+}
+
+func float64sFromBytes(b []byte, arr []float64, order binary.ByteOrder) []float64 {
+	if order == binary.LittleEndian && hostLittleEndian {
+		return appendReinterpreted(b, arr)
+	}
+
+	arr = slices.Grow(arr, len(b)/8)
+	for i := 0; i+8 <= len(b); i += 8 {
+		arr = append(arr, math.Float64frombits(order.Uint64(b[i:])))
+	}
+
+	return arr
+}
+
+func trailingBytesError(n, count int) error {
+	return fmt.Errorf("%d trailing bytes after %d elements", n, count)
+}
+
+// lookupField reports whether output i has a field in fs.
+// Field is resolved with Options.PositionalFallback if output isn't matched by name,
+// then its tag options and nullable wrapper apply as if it was matched by name.
+func lookupField[T TritonModelInferResponseOutputs](fs *fieldSet, i int, outputs []T, opts *Options) bool {
+	name := outputs[i].GetName()
+	if _, ok := fs.fields[name]; ok {
+		return true
+	}
+
+	if !opts.PositionalFallback {
+		return false
+	}
+
+	f, tagOpts, ok := getPositionalField(fs.rv, i, outputs, opts)
+	if !ok {
+		return false
+	}
+
+	if value, valid, ok := nullableFields(f); ok {
+		f = value
+		fs.valid[name] = valid
+	}
+
+	fs.fields[name], fs.tagOpts[name] = f, tagOpts
+
+	return true
+}
+
+func getTagFieldMap(rv reflect.Value, opts *Options) map[string]reflect.Value {
 	fieldsNum := rv.Elem().NumField()
 	m := make(map[string]reflect.Value)
 
 	for i := 0; i < fieldsNum; i++ {
-		field := rv.Elem().Type().Field(i).Tag.Get(tag)
+		field, tagOpts := parseTag(opts.fieldTag(rv.Elem().Type().Field(i)))
+		if isSideField(tagOpts) {
+			continue
+		}
+
 		m[field] = rv.Elem().Field(i)
 	}
 
 	return m
 }
+
+// isSideField reports whether field holds metadata of output rather than its contents.
+// Such fields may share output name with the field of contents.
+func isSideField(opts tagOptions) bool {
+	return opts.Contains(tagParameters) || opts.Contains(tagShape) || opts.Has(tagConcat) || opts.Has(tagIndices) ||
+		opts.Has(tagLengths) || opts.Contains(tagRemaining) || opts.Contains(tagZip)
+}
+
+// getOptionFieldMap returns fields tagged with option, e.g. parameters, by output name.
+func getOptionFieldMap(rv reflect.Value, option string, opts *Options) map[string]reflect.Value {
+	fieldsNum := rv.Elem().NumField()
+	m := make(map[string]reflect.Value)
+
+	for i := 0; i < fieldsNum; i++ {
+		field, tagOpts := parseTag(opts.fieldTag(rv.Elem().Type().Field(i)))
+		if tagOpts.Contains(option) {
+			m[field] = rv.Elem().Field(i)
+		}
+	}
+
+	return m
+}
+
+// getTagOptionsMap returns options of triton tags by output name.
+func getTagOptionsMap(rv reflect.Value, opts *Options) map[string]tagOptions {
+	fieldsNum := rv.Elem().NumField()
+	m := make(map[string]tagOptions)
+
+	for i := 0; i < fieldsNum; i++ {
+		field, tagOpts := parseTag(opts.fieldTag(rv.Elem().Type().Field(i)))
+		if isSideField(tagOpts) {
+			continue
+		}
+
+		m[field] = tagOpts
+	}
+
+	return m
+}
+
+// getPositionalField returns field declared at the same position as output i.
+// Field is not returned if it's matched by name with another output.
+func getPositionalField[T TritonModelInferResponseOutputs](
+	rv reflect.Value,
+	i int,
+	outputs []T,
+	opts *Options,
+) (reflect.Value, tagOptions, bool) {
+	if i >= rv.Elem().NumField() {
+		return reflect.Value{}, "", false
+	}
+
+	field := rv.Elem().Field(i)
+	if !field.CanSet() {
+		return reflect.Value{}, "", false
+	}
+
+	name, tagOpts := parseTag(opts.fieldTag(rv.Elem().Type().Field(i)))
+	if isSideField(tagOpts) {
+		return reflect.Value{}, "", false
+	}
+
+	for _, o := range outputs {
+		if o.GetName() == name {
+			return reflect.Value{}, "", false
+		}
+	}
+
+	return field, tagOpts, true
+}
+
+// getRequiredOutputs returns names of outputs tagged as required in order of fields declaration.
+func getRequiredOutputs(rv reflect.Value, opts *Options) []string {
+	fieldsNum := rv.Elem().NumField()
+	var res []string
+
+	for i := 0; i < fieldsNum; i++ {
+		field, tagOpts := parseTag(opts.fieldTag(rv.Elem().Type().Field(i)))
+		if tagOpts.Contains(tagRequired) {
+			res = append(res, field)
+		}
+	}
+
+	return res
+}
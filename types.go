@@ -1,13 +1,26 @@
 package tritonparser
 
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Datatypes of Triton tensors.
 const (
 	BOOL = "BOOL"
 
+	// UINT4 and INT4 are packed two elements per byte, low nibble first.
+	UINT4  = "UINT4"
 	UINT8  = "UINT8"
 	UINT16 = "UINT16"
 	UINT32 = "UINT32"
 	UINT64 = "UINT64"
 
+	INT4  = "INT4"
 	INT8  = "INT8"
 	INT16 = "INT16"
 	INT32 = "INT32"
@@ -19,3 +32,173 @@ const (
 
 	STRING = "BYTES"
 )
+
+// SupportedDatatypes returns datatypes that can be decoded.
+// FLOAT16 is supported only for single values and [1, N] or [N] shaped outputs.
+func SupportedDatatypes() []string {
+	return []string{
+		BOOL,
+		UINT4, UINT8, UINT16, UINT32, UINT64,
+		INT4, INT8, INT16, INT32, INT64,
+		FLOAT16, FLOAT32, FLOAT64,
+		STRING,
+	}
+}
+
+// CanDecode returns error naming every output of inferResponse whose datatype or shape
+// can't be decoded into plain fields, e.g. unknown datatype or FLOAT16 matrix,
+// so unsupported models can be rejected before decoding their responses.
+// Outputs of rank above 2 are reported too, though Tensor and flat fields accept them.
+func CanDecode[T TritonModelInferResponseOutputs](inferResponse TritonModelInferResponse[T]) error {
+	var errs []error
+
+	for _, o := range inferResponse.GetOutputs() {
+		if _, ok := elementType(o.GetDatatype()); !ok {
+			errs = append(errs, fmt.Errorf("output %s: unkwnow type: %s", o.GetName(), o.GetDatatype()))
+
+			continue
+		}
+
+		shape := o.GetShape()
+		if len(shape) == 0 {
+			// rank 0 output is a single value, as of shape [1].
+			shape = []int64{1}
+		}
+
+		kind, err := ClassifyShape(shape)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("output %s: %w", o.GetName(), err))
+
+			continue
+		}
+
+		if o.GetDatatype() == FLOAT16 && kind == ShapeMatrix {
+			errs = append(errs, fmt.Errorf("output %s: %s of shape %v not yet supported", o.GetName(), FLOAT16, o.GetShape()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// elementType returns type of single decoded element of datatype.
+// FLOAT16 elements are widened to float32.
+func elementType(datatype string) (reflect.Type, bool) {
+	switch datatype {
+	case BOOL:
+		return reflect.TypeFor[bool](), true
+	case UINT4, UINT8:
+		return reflect.TypeFor[uint8](), true
+	case UINT16:
+		return reflect.TypeFor[uint16](), true
+	case UINT32:
+		return reflect.TypeFor[uint32](), true
+	case UINT64:
+		return reflect.TypeFor[uint64](), true
+	case INT4, INT8:
+		return reflect.TypeFor[int8](), true
+	case INT16:
+		return reflect.TypeFor[int16](), true
+	case INT32:
+		return reflect.TypeFor[int32](), true
+	case INT64:
+		return reflect.TypeFor[int64](), true
+	case FLOAT16, FLOAT32:
+		return reflect.TypeFor[float32](), true
+	case FLOAT64:
+		return reflect.TypeFor[float64](), true
+	case STRING:
+		return reflect.TypeFor[string](), true
+	default:
+		return nil, false
+	}
+}
+
+// ErrVariableSize is returned by OutputByteSize for datatypes whose size depends on contents.
+var ErrVariableSize = errors.New("size depends on contents")
+
+// OutputByteSize returns size of raw contents of output with datatype and shape.
+// ErrVariableSize is returned for STRING outputs.
+func OutputByteSize(datatype string, shape []int64) (int, error) {
+	if datatype == STRING {
+		return 0, fmt.Errorf("%s: %w", STRING, ErrVariableSize)
+	}
+
+	count := int64(1)
+	for _, dim := range shape {
+		if dim < 0 {
+			return 0, fmt.Errorf("invalid shape: %v", shape)
+		}
+
+		// 8 is the largest element size.
+		if dim != 0 && count > math.MaxInt/8/dim {
+			return 0, fmt.Errorf("shape %v is too large", shape)
+		}
+
+		count *= dim
+	}
+
+	switch datatype {
+	case INT4, UINT4:
+		return int((count + 1) / 2), nil
+	case FLOAT16:
+		return int(count) * 2, nil
+	}
+
+	t, ok := elementType(datatype)
+	if !ok {
+		return 0, fmt.Errorf("unkwnow type: %s", datatype)
+	}
+
+	return int(count) * int(t.Size()), nil
+}
+
+//nolint:gochecknoglobals // registry is shared by all encoders.
+var typeMappings sync.Map
+
+// RegisterTypeMapping maps goType to Triton datatype, overriding default mapping of DatatypeOf,
+// e.g. for custom half-precision type to FLOAT16. It panics if datatype is not supported.
+func RegisterTypeMapping(goType reflect.Type, datatype string) {
+	if goType == nil {
+		panic("tritonparser: RegisterTypeMapping of nil type")
+	}
+
+	if !slices.Contains(SupportedDatatypes(), datatype) {
+		panic(fmt.Sprintf("tritonparser: RegisterTypeMapping of %s to unknown datatype %s", goType, datatype))
+	}
+
+	typeMappings.Store(goType, datatype)
+}
+
+// DatatypeOf returns Triton datatype values of t are encoded as.
+// Slices are mapped by type of their elements, except []byte, which is BYTES element itself.
+// Types without registered mapping are mapped by kind, e.g. int32 and named types of it to INT32.
+func DatatypeOf(t reflect.Type) (string, bool) {
+	for t.Kind() == reflect.Slice && t != reflect.TypeFor[[]byte]() {
+		if datatype, ok := typeMappings.Load(t); ok {
+			return as[string](datatype), true
+		}
+
+		t = t.Elem()
+	}
+
+	if datatype, ok := typeMappings.Load(t); ok {
+		return as[string](datatype), true
+	}
+
+	switch k := t.Kind(); {
+	case k == reflect.Bool:
+		return BOOL, true
+	case k == reflect.String, t == reflect.TypeFor[[]byte]():
+		return STRING, true
+	case t == reflect.TypeFor[Float16]():
+		return FLOAT16, true
+	case isInt(k):
+		return fmt.Sprintf("INT%d", t.Bits()), true
+	case isUint(k) && k != reflect.Uintptr:
+		return fmt.Sprintf("UINT%d", t.Bits()), true
+	case isFloat(k):
+		return fmt.Sprintf("FP%d", t.Bits()), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,273 @@
+package tritonparser
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSupportedDatatypes(t *testing.T) {
+	seen := make(map[string]bool)
+
+	for _, dt := range SupportedDatatypes() {
+		if seen[dt] {
+			t.Errorf("datatype %s is listed twice", dt)
+		}
+
+		seen[dt] = true
+
+		if _, ok := elementType(dt); !ok {
+			t.Errorf("datatype %s has no element type", dt)
+		}
+	}
+
+	for _, dt := range []string{"", "FP8", "BF16", "fp32", "STRING"} {
+		if seen[dt] {
+			t.Errorf("datatype %q must not be supported", dt)
+		}
+
+		if _, ok := elementType(dt); ok {
+			t.Errorf("datatype %q must have no element type", dt)
+		}
+	}
+
+	// callers may modify returned slice.
+	SupportedDatatypes()[0] = "changed"
+	if SupportedDatatypes()[0] != BOOL {
+		t.Error("modification of returned slice is visible")
+	}
+}
+
+func TestOutputByteSize(t *testing.T) {
+	tests := []struct {
+		datatype string
+		shape    []int64
+		want     int
+	}{
+		{datatype: BOOL, shape: []int64{3}, want: 3},
+		{datatype: UINT8, shape: []int64{2, 2}, want: 4},
+		{datatype: INT16, shape: []int64{1, 3}, want: 6},
+		{datatype: FLOAT16, shape: []int64{2, 3}, want: 12},
+		{datatype: FLOAT32, shape: []int64{1, 2, 3}, want: 24},
+		{datatype: UINT64, shape: []int64{2}, want: 16},
+		{datatype: FLOAT64, shape: []int64{1}, want: 8},
+		{datatype: INT4, shape: []int64{3}, want: 2},
+		{datatype: UINT4, shape: []int64{2, 2}, want: 2},
+		{datatype: INT32, shape: []int64{2, 0}, want: 0},
+		{datatype: INT32, shape: nil, want: 4},
+	}
+
+	for _, tt := range tests {
+		got, err := OutputByteSize(tt.datatype, tt.shape)
+		if err != nil || got != tt.want {
+			t.Errorf("OutputByteSize(%s, %v) = %d, %v, want %d", tt.datatype, tt.shape, got, err, tt.want)
+		}
+	}
+}
+
+func TestOutputByteSizeErrors(t *testing.T) {
+	tests := []struct {
+		datatype string
+		shape    []int64
+		want     string
+	}{
+		{datatype: STRING, shape: []int64{1}, want: "BYTES: size depends on contents"},
+		{datatype: INT32, shape: []int64{2, -1}, want: "invalid shape: [2 -1]"},
+		{datatype: INT8, shape: []int64{math.MaxInt64, 2}, want: "is too large"},
+		{datatype: INT8, shape: []int64{math.MaxInt32, math.MaxInt32, 2}, want: "is too large"},
+		{datatype: "COMPLEX64", shape: []int64{1}, want: "COMPLEX64"},
+	}
+
+	for _, tt := range tests {
+		_, err := OutputByteSize(tt.datatype, tt.shape)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("OutputByteSize(%s, %v) error %v, want %q", tt.datatype, tt.shape, err, tt.want)
+		}
+
+		if (tt.datatype == STRING) != errors.Is(err, ErrVariableSize) {
+			t.Errorf("OutputByteSize(%s, %v) error %v, want ErrVariableSize %t", tt.datatype, tt.shape, err, tt.datatype == STRING)
+		}
+	}
+}
+
+type (
+	bfloat16 uint16
+	packed   []byte
+)
+
+func TestDatatypeOf(t *testing.T) {
+	RegisterTypeMapping(reflect.TypeFor[bfloat16](), FLOAT16)
+	RegisterTypeMapping(reflect.TypeFor[packed](), UINT4)
+
+	tests := []struct {
+		t    reflect.Type
+		want string
+	}{
+		{t: reflect.TypeFor[bool](), want: BOOL},
+		{t: reflect.TypeFor[int8](), want: INT8},
+		{t: reflect.TypeFor[int16](), want: INT16},
+		{t: reflect.TypeFor[int32](), want: INT32},
+		{t: reflect.TypeFor[int64](), want: INT64},
+		{t: reflect.TypeFor[uint8](), want: UINT8},
+		{t: reflect.TypeFor[uint16](), want: UINT16},
+		{t: reflect.TypeFor[uint32](), want: UINT32},
+		{t: reflect.TypeFor[uint64](), want: UINT64},
+		{t: reflect.TypeFor[float32](), want: FLOAT32},
+		{t: reflect.TypeFor[float64](), want: FLOAT64},
+		{t: reflect.TypeFor[Float16](), want: FLOAT16},
+		{t: reflect.TypeFor[string](), want: STRING},
+		{t: reflect.TypeFor[[]byte](), want: STRING},
+		{t: reflect.TypeFor[[][]byte](), want: STRING},
+		{t: reflect.TypeFor[[][]float32](), want: FLOAT32},
+		{t: reflect.TypeFor[label](), want: INT32},
+		{t: reflect.TypeFor[bfloat16](), want: FLOAT16},
+		{t: reflect.TypeFor[[]bfloat16](), want: FLOAT16},
+		{t: reflect.TypeFor[packed](), want: UINT4},
+		{t: reflect.TypeFor[[]packed](), want: UINT4},
+	}
+
+	for _, tt := range tests {
+		if got, ok := DatatypeOf(tt.t); !ok || got != tt.want {
+			t.Errorf("DatatypeOf(%s) = %q, %t, want %q", tt.t, got, ok, tt.want)
+		}
+	}
+
+	for _, unsupported := range []reflect.Type{
+		reflect.TypeFor[uintptr](),
+		reflect.TypeFor[complex64](),
+		reflect.TypeFor[struct{}](),
+		reflect.TypeFor[map[string]int32](),
+		reflect.TypeFor[*int32](),
+		reflect.TypeFor[[]any](),
+	} {
+		if got, ok := DatatypeOf(unsupported); ok {
+			t.Errorf("DatatypeOf(%s) = %q, want unsupported", unsupported, got)
+		}
+	}
+}
+
+func TestRegisterTypeMappingPanics(t *testing.T) {
+	tests := []struct {
+		name     string
+		t        reflect.Type
+		datatype string
+		want     string
+	}{
+		{name: "nil type", datatype: INT32, want: "tritonparser: RegisterTypeMapping of nil type"},
+		{
+			name:     "unknown datatype",
+			t:        reflect.TypeFor[bfloat16](),
+			datatype: "BF16",
+			want:     "tritonparser: RegisterTypeMapping of tritonparser.bfloat16 to unknown datatype BF16",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tt.want {
+					t.Errorf("got panic %v, want %q", r, tt.want)
+				}
+			}()
+
+			RegisterTypeMapping(tt.t, tt.datatype)
+		})
+	}
+}
+
+func TestCanDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+	}{
+		{name: "no outputs"},
+		{
+			name: "every datatype",
+			outputs: []*testOutput{
+				{name: "bool", datatype: BOOL, shape: []int64{1}},
+				{name: "uint8", datatype: UINT8, shape: []int64{1, 4}},
+				{name: "int64", datatype: INT64, shape: []int64{3, 2}},
+				{name: "float64", datatype: FLOAT64, shape: []int64{2}},
+				{name: "string", datatype: STRING, shape: []int64{2, 2}},
+				{name: "int4", datatype: INT4, shape: []int64{1, 3}},
+				{name: "uint4", datatype: UINT4, shape: []int64{4}},
+			},
+		},
+		{
+			name: "float16 value and vector",
+			outputs: []*testOutput{
+				{name: "value", datatype: FLOAT16, shape: []int64{1}},
+				{name: "scalar", datatype: FLOAT16, shape: []int64{3}},
+				{name: "vector", datatype: FLOAT16, shape: []int64{1, 3}},
+			},
+		},
+		{name: "rank 0", outputs: []*testOutput{{name: "value", datatype: INT32}}},
+		{name: "empty dimension", outputs: []*testOutput{{name: "empty", datatype: INT32, shape: []int64{1, 0}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CanDecode[*testOutput](&testResponse{outputs: tt.outputs}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestCanDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []*testOutput
+		want    []string
+	}{
+		{
+			name:    "unknown datatype",
+			outputs: []*testOutput{{name: "complex", datatype: "COMPLEX64", shape: []int64{1}}},
+			want:    []string{"output complex: unkwnow type: COMPLEX64"},
+		},
+		{
+			name:    "float16 matrix",
+			outputs: []*testOutput{{name: "half", datatype: FLOAT16, shape: []int64{2, 3}}},
+			want:    []string{"output half: FP16 of shape [2 3] not yet supported"},
+		},
+		{
+			name:    "negative dimension",
+			outputs: []*testOutput{{name: "negative", datatype: INT32, shape: []int64{1, -1}}},
+			want:    []string{"output negative: invalid shape: [1 -1]"},
+		},
+		{
+			name:    "rank 3",
+			outputs: []*testOutput{{name: "cube", datatype: FLOAT32, shape: []int64{1, 2, 3}}},
+			want:    []string{"output cube: len(shape) > 2 is not yet supported"},
+		},
+		{
+			name:    "empty batch",
+			outputs: []*testOutput{{name: "empty", datatype: INT32, shape: []int64{0, 2}}},
+			want:    []string{"output empty: unknown shape: [0 2]"},
+		},
+		{
+			name: "every failed output",
+			outputs: []*testOutput{
+				{name: "first", datatype: "BF16", shape: []int64{1}},
+				{name: "valid", datatype: INT32, shape: []int64{1, 2}},
+				{name: "second", datatype: FLOAT16, shape: []int64{4, 4}},
+			},
+			want: []string{"output first: unkwnow type: BF16", "output second: FP16 of shape [4 4] not yet supported"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CanDecode[*testOutput](&testResponse{outputs: tt.outputs})
+			if err == nil {
+				t.Fatal("got nil error")
+			}
+
+			if got := strings.Split(err.Error(), "\n"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got errors %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package tritonparser
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// TritonUnmarshaler is implemented by types that decode outputs themselves.
+// UnmarshalTriton receives raw contents of output after decompression and must copy them
+// if they are retained after returning.
+type TritonUnmarshaler interface {
+	UnmarshalTriton(datatype string, shape []int64, raw []byte) error
+}
+
+// getUnmarshaler returns TritonUnmarshaler of field if field or pointer to it implements it.
+// Nil pointer fields are allocated.
+func getUnmarshaler(field reflect.Value) (TritonUnmarshaler, bool) {
+	t := reflect.TypeFor[TritonUnmarshaler]()
+
+	switch {
+	case field.Kind() == reflect.Pointer && field.Type().Implements(t):
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+
+		return as[TritonUnmarshaler](field.Interface()), true
+	case field.CanAddr() && field.Addr().Type().Implements(t):
+		return as[TritonUnmarshaler](field.Addr().Interface()), true
+	default:
+		return nil, false
+	}
+}
+
+func unmarshalCustom(u TritonUnmarshaler, output TritonModelInferResponseOutputs, rawBytes []byte) error {
+	if err := u.UnmarshalTriton(output.GetDatatype(), output.GetShape(), rawBytes); err != nil {
+		return fmt.Errorf("output %s: %w", output.GetName(), err)
+	}
+
+	return nil
+}
+
+// getBinaryUnmarshaler returns encoding.BinaryUnmarshaler of field, the same way as getUnmarshaler.
+func getBinaryUnmarshaler(field reflect.Value) (encoding.BinaryUnmarshaler, bool) {
+	t := reflect.TypeFor[encoding.BinaryUnmarshaler]()
+
+	switch {
+	case field.Kind() == reflect.Pointer && field.Type().Implements(t):
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+
+		return as[encoding.BinaryUnmarshaler](field.Interface()), true
+	case field.CanAddr() && field.Addr().Type().Implements(t):
+		return as[encoding.BinaryUnmarshaler](field.Addr().Interface()), true
+	default:
+		return nil, false
+	}
+}
+
+// isByteArray reports whether t is an array of bytes, e.g. [16]byte.
+func isByteArray(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8
+}
+
+// setByteArray copies b into byte array v, whose length b must match.
+func setByteArray(v reflect.Value, b []byte) error {
+	if len(b) != v.Len() {
+		return fmt.Errorf("%d bytes don't match length of %s", len(b), v.Type())
+	}
+
+	for i, c := range b {
+		v.Index(i).SetUint(uint64(c))
+	}
+
+	return nil
+}
+
+// isBinaryUnmarshalerSlice reports whether field is a slice of elements implementing encoding.BinaryUnmarshaler
+// or byte arrays.
+func isBinaryUnmarshalerSlice(field reflect.Value) bool {
+	if field.Kind() != reflect.Slice {
+		return false
+	}
+
+	elem := field.Type().Elem()
+	t := reflect.TypeFor[encoding.BinaryUnmarshaler]()
+
+	return (elem.Kind() == reflect.Pointer && elem.Implements(t)) || reflect.PointerTo(elem).Implements(t) || isByteArray(elem)
+}
+
+// unmarshalBinarySlice feeds every element of output to UnmarshalBinary of slice field elements,
+// or copies it into byte array elements. Elements of BYTES output are its strings,
+// elements of UINT8 output of rank 2 and above are rows of its last dimension, e.g. 16 bytes
+// of every UUID of [N, 16] output, and elements of other datatypes are their raw bytes.
+func unmarshalBinarySlice(field reflect.Value, output TritonModelInferResponseOutputs, rawBytes []byte, opts *Options) error {
+	shape := output.GetShape()
+	count := 1
+	for _, dim := range shape {
+		if dim < 0 {
+			return fmt.Errorf("invalid shape: %v", shape)
+		}
+
+		count *= int(dim)
+	}
+
+	var elems [][]byte
+
+	switch output.GetDatatype() {
+	case STRING:
+		strs, err := stringBytesToArray(rawBytes, count, opts.byteOrder(output.GetName()), opts)
+		if err != nil {
+			return fmt.Errorf("output %s: %w", output.GetName(), err)
+		}
+
+		for _, s := range strs {
+			elems = append(elems, []byte(s))
+		}
+	case INT4, UINT4:
+		return fmt.Errorf("output %s: %s elements can't be unmarshaled with encoding.BinaryUnmarshaler",
+			output.GetName(), output.GetDatatype())
+	default:
+		size, err := OutputByteSize(output.GetDatatype(), nil)
+		if err != nil {
+			return fmt.Errorf("output %s: %w", output.GetName(), err)
+		}
+
+		if output.GetDatatype() == UINT8 && len(shape) > 1 && shape[len(shape)-1] != 0 {
+			size = int(shape[len(shape)-1])
+			count /= size
+		}
+
+		if len(rawBytes) != count*size {
+			return fmt.Errorf("output %s: raw contents length %d doesn't match shape %v", output.GetName(), len(rawBytes), output.GetShape())
+		}
+
+		for i := 0; i < count; i++ {
+			elems = append(elems, rawBytes[i*size:(i+1)*size])
+		}
+	}
+
+	res := reflect.MakeSlice(field.Type(), len(elems), len(elems))
+	for i, b := range elems {
+		var err error
+		if u, ok := getBinaryUnmarshaler(res.Index(i)); ok {
+			err = u.UnmarshalBinary(b)
+		} else {
+			err = setByteArray(res.Index(i), b)
+		}
+
+		if err != nil {
+			return fmt.Errorf("output %s[%d]: %w", output.GetName(), i, err)
+		}
+	}
+
+	field.Set(res)
+
+	return nil
+}
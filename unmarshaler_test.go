@@ -0,0 +1,331 @@
+package tritonparser
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var errBBoxSize = errors.New("bbox needs 16 bytes")
+
+// bbox decodes FLOAT32 output of shape [4] itself.
+type bbox struct {
+	datatype string
+	shape    []int64
+	raw      []byte
+}
+
+func (b *bbox) UnmarshalTriton(datatype string, shape []int64, raw []byte) error {
+	if len(raw) != 16 {
+		return fmt.Errorf("%w, got %d", errBBoxSize, len(raw))
+	}
+
+	b.datatype, b.shape, b.raw = datatype, shape, append([]byte(nil), raw...)
+
+	return nil
+}
+
+func TestTritonUnmarshaler(t *testing.T) {
+	type result struct {
+		Box     bbox  `triton:"box"`
+		Pointer *bbox `triton:"pointer"`
+	}
+
+	raw := int32Bytes(1, 2, 3, 4)
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "box", datatype: FLOAT32, shape: []int64{4}},
+			{name: "pointer", datatype: INT32, shape: []int64{2, 2}},
+		},
+		raw: [][]byte{raw, raw},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		Box:     bbox{datatype: FLOAT32, shape: []int64{4}, raw: raw},
+		Pointer: &bbox{datatype: INT32, shape: []int64{2, 2}, raw: raw},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestTritonUnmarshalerErrors(t *testing.T) {
+	var res struct {
+		Box bbox `triton:"box"`
+	}
+
+	tests := []struct {
+		name string
+		raw  []byte
+		want string
+	}{
+		{name: "short", raw: int32Bytes(1, 2, 3), want: "output box: bbox needs 16 bytes, got 12"},
+		{name: "long", raw: int32Bytes(1, 2, 3, 4, 5), want: "output box: bbox needs 16 bytes, got 20"},
+		{name: "empty", raw: []byte{}, want: "output box: bbox needs 16 bytes, got 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &testResponse{
+				outputs: []*testOutput{{name: "box", datatype: FLOAT32, shape: []int64{4}}},
+				raw:     [][]byte{tt.raw},
+			}
+
+			err := Unmarshal(resp, &res)
+			if !errors.Is(err, errBBoxSize) || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// bigEndian32 is uint32 decoded from its big-endian bytes with encoding.BinaryUnmarshaler.
+type bigEndian32 uint32
+
+func (b *bigEndian32) UnmarshalBinary(data []byte) error {
+	if len(data) != 4 {
+		return fmt.Errorf("bigEndian32 needs 4 bytes, got %d", len(data))
+	}
+
+	*b = bigEndian32(binary.BigEndian.Uint32(data))
+
+	return nil
+}
+
+func TestBinaryUnmarshaler(t *testing.T) {
+	type result struct {
+		Value    bigEndian32    `triton:"value"`
+		Pointer  *bigEndian32   `triton:"pointer"`
+		Values   []bigEndian32  `triton:"values"`
+		Pointers []*bigEndian32 `triton:"pointers"`
+		Strings  []bigEndian32  `triton:"strings"`
+		Matrix   []bigEndian32  `triton:"matrix"`
+	}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "value", datatype: INT32, shape: []int64{1}},
+			{name: "pointer", datatype: UINT8, shape: []int64{4}},
+			{name: "values", datatype: INT32, shape: []int64{1, 2}},
+			{name: "pointers", datatype: UINT32, shape: []int64{1}},
+			{name: "strings", datatype: STRING, shape: []int64{2}},
+			{name: "matrix", datatype: INT32, shape: []int64{2, 1}},
+		},
+		raw: [][]byte{
+			{0, 0, 0, 1},
+			{0, 0, 1, 0},
+			{0, 0, 0, 2, 0, 0, 0, 3},
+			{0, 0, 0, 4},
+			encodeStrings("\x00\x00\x00\x05", "\x00\x00\x00\x06"),
+			{0, 0, 0, 7, 0, 0, 0, 8},
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	pointer, element := bigEndian32(256), bigEndian32(4)
+	want := result{
+		Value:    1,
+		Pointer:  &pointer,
+		Values:   []bigEndian32{2, 3},
+		Pointers: []*bigEndian32{&element},
+		Strings:  []bigEndian32{5, 6},
+		Matrix:   []bigEndian32{7, 8},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestBinaryUnmarshalerErrors(t *testing.T) {
+	var res struct {
+		Value  bigEndian32   `triton:"value"`
+		Values []bigEndian32 `triton:"values"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "scalar",
+			output: &testOutput{name: "value", datatype: INT64, shape: []int64{1}},
+			raw:    int64Bytes(1),
+			want:   "output value: bigEndian32 needs 4 bytes, got 8",
+		},
+		{
+			name:   "element size",
+			output: &testOutput{name: "values", datatype: INT16, shape: []int64{1, 2}},
+			raw:    int16Bytes(1, 2),
+			want:   "output values[0]: bigEndian32 needs 4 bytes, got 2",
+		},
+		{
+			name:   "string element",
+			output: &testOutput{name: "values", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("\x00\x00\x00\x01", "\x01"),
+			want:   "output values[1]: bigEndian32 needs 4 bytes, got 1",
+		},
+		{
+			name:   "short",
+			output: &testOutput{name: "values", datatype: INT32, shape: []int64{1, 2}},
+			raw:    int32Bytes(1),
+			want:   "output values: raw contents length 4 doesn't match shape [1 2]",
+		},
+		{
+			name:   "missing string",
+			output: &testOutput{name: "values", datatype: STRING, shape: []int64{1, 2}},
+			raw:    encodeStrings("\x00\x00\x00\x01"),
+			want:   "output values",
+		},
+		{
+			name:   "packed",
+			output: &testOutput{name: "values", datatype: INT4, shape: []int64{1, 2}},
+			raw:    []byte{0x21},
+			want:   "output values: INT4 elements can't be unmarshaled with encoding.BinaryUnmarshaler",
+		},
+		{
+			name:   "negative dimension",
+			output: &testOutput{name: "values", datatype: INT32, shape: []int64{1, -2}},
+			raw:    int32Bytes(1),
+			want:   "invalid shape: [1 -2]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteArrays(t *testing.T) {
+	type result struct {
+		ID      [16]byte     `triton:"id"`
+		Key     [4]byte      `triton:"key"`
+		IDs     [][16]byte   `triton:"ids"`
+		Addr    netip.Addr   `triton:"addr"`
+		Addrs   []netip.Addr `triton:"addrs"`
+		Empty   [][4]byte    `triton:"empty"`
+		Strings [][2]byte    `triton:"strings"`
+	}
+
+	id := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	id2 := [16]byte{0: 0xff}
+
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "id", datatype: UINT8, shape: []int64{16}},
+			{name: "key", datatype: STRING, shape: []int64{1}},
+			{name: "ids", datatype: UINT8, shape: []int64{2, 16}},
+			{name: "addr", datatype: UINT8, shape: []int64{4}},
+			{name: "addrs", datatype: STRING, shape: []int64{2}},
+			{name: "empty", datatype: UINT8, shape: []int64{0, 4}},
+			{name: "strings", datatype: STRING, shape: []int64{1, 2}},
+		},
+		raw: [][]byte{
+			id[:],
+			encodeStrings("abcd"),
+			append(id[:], id2[:]...),
+			{10, 0, 0, 1},
+			encodeStrings("\xc0\xa8\x00\x01", string(netip.MustParseAddr("::1").AsSlice())),
+			{},
+			encodeStrings("ab", "cd"),
+		},
+	}
+
+	var res result
+	if err := Unmarshal(resp, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := result{
+		ID:      id,
+		Key:     [4]byte{'a', 'b', 'c', 'd'},
+		IDs:     [][16]byte{id, id2},
+		Addr:    netip.MustParseAddr("10.0.0.1"),
+		Addrs:   []netip.Addr{netip.MustParseAddr("192.168.0.1"), netip.MustParseAddr("::1")},
+		Empty:   [][4]byte{},
+		Strings: [][2]byte{{'a', 'b'}, {'c', 'd'}},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestByteArraysErrors(t *testing.T) {
+	var res struct {
+		ID    [16]byte     `triton:"id"`
+		IDs   [][16]byte   `triton:"ids"`
+		Addrs []netip.Addr `triton:"addrs"`
+	}
+
+	tests := []struct {
+		name   string
+		output *testOutput
+		raw    []byte
+		want   string
+	}{
+		{
+			name:   "short array",
+			output: &testOutput{name: "id", datatype: UINT8, shape: []int64{15}},
+			raw:    make([]byte, 15),
+			want:   "output id: 15 bytes don't match length of [16]uint8",
+		},
+		{
+			name:   "long string",
+			output: &testOutput{name: "id", datatype: STRING, shape: []int64{1}},
+			raw:    encodeStrings(strings.Repeat("a", 17)),
+			want:   "output id: 17 bytes don't match length of [16]uint8",
+		},
+		{
+			name:   "short string contents",
+			output: &testOutput{name: "id", datatype: STRING, shape: []int64{1}},
+			raw:    []byte{16, 0, 0},
+			want:   "output id",
+		},
+		{
+			name:   "row length",
+			output: &testOutput{name: "ids", datatype: UINT8, shape: []int64{2, 8}},
+			raw:    make([]byte, 16),
+			want:   "output ids[0]: 8 bytes don't match length of [16]uint8",
+		},
+		{
+			name:   "rows raw length",
+			output: &testOutput{name: "ids", datatype: UINT8, shape: []int64{2, 16}},
+			raw:    make([]byte, 31),
+			want:   "output ids: raw contents length 31 doesn't match shape [2 16]",
+		},
+		{
+			name:   "address length",
+			output: &testOutput{name: "addrs", datatype: STRING, shape: []int64{2}},
+			raw:    encodeStrings("\x7f\x00\x00\x01", "\x01\x02\x03"),
+			want:   "output addrs[1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := unmarshalOutput(&res, tt.output, tt.raw, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package tritonparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+type typedResult struct {
+	Score int32   `triton:"score"`
+	Ids   []int64 `triton:"ids"`
+}
+
+func TestUnmarshalType(t *testing.T) {
+	resp := &testResponse{
+		outputs: []*testOutput{
+			{name: "score", datatype: INT32, shape: []int64{1}},
+			{name: "ids", datatype: INT64, shape: []int64{1, 2}},
+		},
+		raw: [][]byte{int32Bytes(5), int64Bytes(1, 2)},
+	}
+	want := typedResult{Score: 5, Ids: []int64{1, 2}}
+
+	tests := []struct {
+		name string
+		t    reflect.Type
+		get  func(any) typedResult
+	}{
+		{name: "value", t: reflect.TypeFor[typedResult](), get: func(v any) typedResult { return v.(typedResult) }},
+		{name: "pointer", t: reflect.TypeFor[*typedResult](), get: func(v any) typedResult { return *v.(*typedResult) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := UnmarshalType(resp, tt.t)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if reflect.TypeOf(v) != tt.t {
+				t.Fatalf("got %T, want %s", v, tt.t)
+			}
+
+			if got := tt.get(v); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTypeErrors(t *testing.T) {
+	resp := &testResponse{
+		outputs: []*testOutput{{name: "score", datatype: INT32, shape: []int64{1}}},
+		raw:     [][]byte{int32Bytes(5)},
+	}
+
+	tests := []struct {
+		name string
+		resp *testResponse
+		t    reflect.Type
+	}{
+		{name: "nil type", resp: resp, t: nil},
+		{name: "map", resp: resp, t: reflect.TypeFor[map[string]int32]()},
+		{name: "func", resp: resp, t: reflect.TypeFor[func()]()},
+		{
+			name: "length",
+			resp: &testResponse{outputs: resp.outputs, raw: [][]byte{{1, 2}}},
+			t:    reflect.TypeFor[typedResult](),
+		},
+		{
+			name: "field type",
+			resp: &testResponse{outputs: []*testOutput{{name: "score", datatype: FLOAT32, shape: []int64{1}}}, raw: resp.raw},
+			t:    reflect.TypeFor[*typedResult](),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if v, err := UnmarshalType(tt.resp, tt.t); err == nil {
+				t.Fatalf("got nil error, value %v", v)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package tritonparser
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// zipField is a slice of structs field receiving columnar outputs zipped by index.
+type zipField struct {
+	name  string
+	field reflect.Value
+}
+
+// zipColumn is a field of element struct of zipField matched with output by its tag.
+type zipColumn struct {
+	output string
+	index  int
+}
+
+// getZipFields returns fields tagged with zip option in order of fields declaration.
+func getZipFields(rv reflect.Value, opts *Options) []zipField {
+	fieldsNum := rv.Elem().NumField()
+	var res []zipField
+
+	for i := 0; i < fieldsNum; i++ {
+		sf := rv.Elem().Type().Field(i)
+		if _, tagOpts := parseTag(opts.fieldTag(sf)); tagOpts.Contains(tagZip) {
+			res = append(res, zipField{name: sf.Name, field: rv.Elem().Field(i)})
+		}
+	}
+
+	return res
+}
+
+// columns returns columns of element struct of zf.
+func (zf zipField) columns(opts *Options) ([]zipColumn, error) {
+	t := zf.field.Type()
+	if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("field %s: %s option requires slice of structs field, got %s", zf.name, tagZip, t)
+	}
+
+	record := t.Elem()
+	var res []zipColumn
+
+	for j := 0; j < record.NumField(); j++ {
+		name, tagOpts := parseTag(opts.fieldTag(record.Field(j)))
+		if name == "" || !record.Field(j).IsExported() {
+			continue
+		}
+
+		if tagOpts != "" {
+			return nil, fmt.Errorf("field %s: tag options of %s columns are not supported, got %q", zf.name, tagZip, string(tagOpts))
+		}
+
+		res = append(res, zipColumn{output: name, index: j})
+	}
+
+	return res, nil
+}
+
+// decodeZip decodes outputs matching columns of zf and stores them into zf field,
+// element i of every column into record i. Columns must have the same number of elements.
+// Rows of multidimensional outputs are elements. Fields of missing columns are left zero,
+// and field is untouched if none of columns is present.
+func decodeZip[T TritonModelInferResponseOutputs](zf zipField, outputs []T, rawBytes [][]byte, opts *Options) error {
+	idx := make(map[string]int, len(outputs))
+	for i, o := range outputs {
+		idx[o.GetName()] = i
+	}
+
+	var (
+		res   reflect.Value
+		first string
+	)
+
+	columns, err := zf.columns(opts)
+	if err != nil {
+		return err
+	}
+
+	record := zf.field.Type().Elem()
+
+	for _, c := range columns {
+		i, ok := idx[c.output]
+		if !ok || !opts.selected(c.output) {
+			continue
+		}
+
+		col, err := decodeColumn(outputs[i], i, rawBytes, reflect.SliceOf(record.Field(c.index).Type), opts)
+		if err != nil {
+			return err
+		}
+
+		if !res.IsValid() {
+			first = c.output
+			res = reflect.MakeSlice(zf.field.Type(), col.Len(), col.Len())
+		}
+
+		if col.Len() != res.Len() {
+			return fmt.Errorf("output %s: %d elements of column don't match %d elements of column %s",
+				c.output, col.Len(), res.Len(), first)
+		}
+
+		for j := 0; j < col.Len(); j++ {
+			res.Index(j).Field(c.index).Set(col.Index(j))
+		}
+	}
+
+	if res.IsValid() {
+		if opts.Trace != nil {
+			opts.tracef("%s: zipped %d records", zf.field.Type(), res.Len())
+		}
+
+		zf.field.Set(res)
+	}
+
+	return nil
+}
+
+// decodeColumn decodes output i into slice of type t, an element per value, or per row of multidimensional output.
+func decodeColumn[T TritonModelInferResponseOutputs](o T, i int, rawBytes [][]byte, t reflect.Type, opts *Options) (reflect.Value, error) {
+	raw, err := rawContents(o, i, rawBytes, opts)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	opts.Stats.add(o, raw)
+
+	b, err := decompress(raw, opts)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("output %s: %w", o.GetName(), err)
+	}
+
+	var output TritonModelInferResponseOutputs = o
+	if shape := o.GetShape(); len(shape) == 1 {
+		output = reshaped{o, []int64{1, shape[0]}}
+	}
+
+	if err := opts.checkLimits(output, b); err != nil {
+		return reflect.Value{}, err
+	}
+
+	val, err := decodeNative(output, b, opts)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	res := reflect.New(t).Elem()
+	if err := setTransformed(res, val, o.GetName(), opts); err != nil {
+		return reflect.Value{}, fmt.Errorf("output %s: %w", o.GetName(), err)
+	}
+
+	return res, nil
+}
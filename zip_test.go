@@ -0,0 +1,174 @@
+package tritonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type zipBox struct {
+	Score  float32 `triton:"score"`
+	Label  string  `triton:"label"`
+	Coords []int32 `triton:"coords"`
+	Note   string
+}
+
+func TestZip(t *testing.T) {
+	type result struct {
+		Boxes []zipBox  `triton:",zip"`
+		Score []float32 `triton:"score"`
+	}
+
+	tests := []struct {
+		name string
+		resp *testResponse
+		want result
+	}{
+		{
+			name: "columns",
+			resp: &testResponse{
+				outputs: []*testOutput{
+					{name: "score", datatype: FLOAT32, shape: []int64{2}},
+					{name: "label", datatype: STRING, shape: []int64{1, 2}},
+					{name: "coords", datatype: INT32, shape: []int64{2, 2}},
+				},
+				raw: [][]byte{float32Bytes(0.5, 0.25), encodeStrings("cat", "dog"), int32Bytes(1, 2, 3, 4)},
+			},
+			want: result{
+				Boxes: []zipBox{
+					{Score: 0.5, Label: "cat", Coords: []int32{1, 2}},
+					{Score: 0.25, Label: "dog", Coords: []int32{3, 4}},
+				},
+				Score: []float32{0.5, 0.25},
+			},
+		},
+		{
+			name: "missing column",
+			resp: &testResponse{
+				outputs: []*testOutput{{name: "label", datatype: STRING, shape: []int64{1}}},
+				raw:     [][]byte{encodeStrings("fox")},
+			},
+			want: result{Boxes: []zipBox{{Label: "fox"}}},
+		},
+		{
+			name: "empty columns",
+			resp: &testResponse{
+				outputs: []*testOutput{
+					{name: "score", datatype: FLOAT32, shape: []int64{0}},
+					{name: "label", datatype: STRING, shape: []int64{1, 0}},
+				},
+				raw: [][]byte{{}, {}},
+			},
+			want: result{Boxes: []zipBox{}, Score: []float32{}},
+		},
+		{
+			name: "no columns",
+			resp: &testResponse{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res result
+			if err := Unmarshal(tt.resp, &res); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestZipErrors(t *testing.T) {
+	type result struct {
+		Boxes []zipBox `triton:",zip"`
+	}
+
+	tests := []struct {
+		name string
+		dst  any
+		resp *testResponse
+		want string
+	}{
+		{
+			name: "length mismatch",
+			dst:  &result{},
+			resp: &testResponse{
+				outputs: []*testOutput{
+					{name: "score", datatype: FLOAT32, shape: []int64{3}},
+					{name: "label", datatype: STRING, shape: []int64{2}},
+				},
+				raw: [][]byte{float32Bytes(1, 2, 3), encodeStrings("a", "b")},
+			},
+			want: "output label: 2 elements of column don't match 3 elements of column score",
+		},
+		{
+			name: "row count mismatch",
+			dst:  &result{},
+			resp: &testResponse{
+				outputs: []*testOutput{
+					{name: "score", datatype: FLOAT32, shape: []int64{1}},
+					{name: "coords", datatype: INT32, shape: []int64{2, 1}},
+				},
+				raw: [][]byte{float32Bytes(1), int32Bytes(1, 2)},
+			},
+			want: "output coords: 2 elements of column don't match 1 elements of column score",
+		},
+		{
+			name: "column type",
+			dst:  &result{},
+			resp: &testResponse{
+				outputs: []*testOutput{{name: "label", datatype: INT32, shape: []int64{1}}},
+				raw:     [][]byte{int32Bytes(1)},
+			},
+			want: "output label",
+		},
+		{
+			name: "short column",
+			dst:  &result{},
+			resp: &testResponse{
+				outputs: []*testOutput{{name: "score", datatype: FLOAT32, shape: []int64{2}}},
+				raw:     [][]byte{float32Bytes(1, 2)[:7]},
+			},
+			want: "not a multiple of element size",
+		},
+		{
+			name: "short string column",
+			dst:  &result{},
+			resp: &testResponse{
+				outputs: []*testOutput{{name: "label", datatype: STRING, shape: []int64{2}}},
+				raw:     [][]byte{encodeStrings("a")},
+			},
+			want: "string length at offset 5",
+		},
+		{
+			name: "not slice of structs",
+			dst: &struct {
+				Scores []float32 `triton:",zip"`
+			}{},
+			resp: &testResponse{},
+			want: "field Scores: zip option requires slice of structs field, got []float32",
+		},
+		{
+			name: "column options",
+			dst: &struct {
+				Boxes []struct {
+					Score float32 `triton:"score,round=nearest"`
+				} `triton:",zip"`
+			}{},
+			resp: &testResponse{},
+			want: `field Boxes: tag options of zip columns are not supported, got "round=nearest"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal(tt.resp, tt.dst)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}